@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	repo "github.com/Hao1995/short-url/internal/adapter/repository/mysql"
+	"github.com/Hao1995/short-url/internal/config"
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/usecase"
+
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+const adminUsage = `Usage: app [-config path] admin [flags] <command> [flags] <args>
+
+Investigate and manage the short urls through the same use cases as the service.
+
+Commands:
+  inspect <id>                  print the short url
+  search <url>                  list the short urls of the target url from the newest one
+  disable <id>                  stop redirecting the short url
+  enable <id>                   redirect the disabled short url again
+  extend <id> <time|duration>   set the expiry to the RFC 3339 time, or extend it by the duration, e.g. 720h
+  purge <id>                    remove the short url from both tiers of the cache
+  clicks <id>                   list the recent clicks of the short url from the newest one
+
+The changes purge the cache, while the local cache of the running instances expires after its TTL.
+
+Flags:
+`
+
+// shortUrlView is the output of a short url
+type shortUrlView struct {
+	ID        string    `json:"id"`
+	Url       string    `json:"url"`
+	Status    string    `json:"status"`
+	ExpireAt  time.Time `json:"expireAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// clickView is the output of a click
+type clickView struct {
+	ClickedAt time.Time `json:"clickedAt"`
+	ClickID   string    `json:"clickId,omitempty"`
+	Rule      int       `json:"rule,omitempty"`
+	Variant   int       `json:"variant,omitempty"`
+	Referer   string    `json:"referer"`
+	UserAgent string    `json:"userAgent"`
+}
+
+// runAdmin runs the admin subcommand and returns the exit code
+func runAdmin(ctx context.Context, cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("admin", flag.ContinueOnError)
+	output := fs.String("o", "table", "output format, table or json")
+	limit := fs.Int("limit", 20, "max number of the short urls of search, or the clicks of clicks")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), adminUsage)
+		fs.PrintDefaults()
+	}
+
+	// the flags go either before or after the command
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	cmd := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[min(1, fs.NArg()):]); err != nil {
+		return 2
+	}
+	nargs := map[string]int{"inspect": 1, "search": 1, "disable": 1, "enable": 1, "extend": 2, "purge": 1, "clicks": 1}
+	if n, ok := nargs[cmd]; !ok || fs.NArg() != n || (*output != "table" && *output != "json") {
+		fs.Usage()
+		return 2
+	}
+
+	adminUC, closeAll, err := newAdminUseCase(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeAll()
+
+	w := &printer{w: os.Stdout, json: *output == "json"}
+	if err := runAdminCommand(ctx, adminUC, w, cmd, fs.Args(), *limit); err != nil {
+		if errors.Is(err, domain.ErrRecordNotFound) {
+			fmt.Fprintf(os.Stderr, "short url not found: %s\n", fs.Arg(0))
+		} else {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return 1
+	}
+	return 0
+}
+
+func runAdminCommand(ctx context.Context, adminUC usecase.AdminUseCase, w *printer, cmd string, args []string, limit int) error {
+	id := args[0]
+	switch cmd {
+	case "inspect":
+		obj, err := adminUC.Inspect(ctx, id)
+		if err != nil {
+			return err
+		}
+		return w.shortUrls([]*domain.ShortUrlDto{obj})
+	case "search":
+		objs, err := adminUC.Search(ctx, &domain.ListReqDto{Url: args[0], Limit: limit})
+		if err != nil {
+			return err
+		}
+		return w.shortUrls(objs)
+	case "disable", "enable":
+		status := domain.LinkStatusDisabled
+		if cmd == "enable" {
+			status = domain.LinkStatusActive
+		}
+		if err := adminUC.SetStatus(ctx, id, status); err != nil {
+			return err
+		}
+	case "extend":
+		expireAt, err := parseExpiry(ctx, adminUC, id, args[1])
+		if err != nil {
+			return err
+		}
+		if err := adminUC.Extend(ctx, id, expireAt); err != nil {
+			return err
+		}
+	case "purge":
+		if err := adminUC.Purge(ctx, id); err != nil {
+			return err
+		}
+		return w.message(map[string]string{"id": id, "result": "purged"})
+	case "clicks":
+		objs, err := adminUC.Clicks(ctx, id, limit)
+		if err != nil {
+			return err
+		}
+		return w.clicks(objs)
+	}
+
+	// print the short url after it's changed
+	obj, err := adminUC.Inspect(ctx, id)
+	if err != nil {
+		return err
+	}
+	return w.shortUrls([]*domain.ShortUrlDto{obj})
+}
+
+// parseExpiry parses the RFC 3339 time, or the duration extending the current expiry.
+// An expired short url is extended from now.
+func parseExpiry(ctx context.Context, adminUC usecase.AdminUseCase, id string, s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return time.Time{}, fmt.Errorf("invalid expiry, want an RFC 3339 time or a positive duration: %s", s)
+	}
+
+	obj, err := adminUC.Inspect(ctx, id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	from := obj.ExpireAt
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+	return from.Add(d).UTC().Truncate(time.Second), nil
+}
+
+// newAdminUseCase connects to the primary of each shard and the Redis, the replicas are skipped
+// so that the operators always see the latest records
+func newAdminUseCase(cfg *config.Config) (usecase.AdminUseCase, func(), error) {
+	dbs := []*gorm.DB{}
+	clusters := []*repo.Cluster{}
+	for i, addrs := range shardAddrs(cfg.MySQL) {
+		dsn, err := mysqlDSN(cfg.MySQL, addrs[0])
+		if err == nil {
+			var db *gorm.DB
+			if db, err = openDB(dsn); err == nil {
+				dbs = append(dbs, db)
+				clusters = append(clusters, repo.NewCluster(db))
+				continue
+			}
+		}
+		closeDBs(dbs)
+		return nil, nil, fmt.Errorf("failed to connect to the DB of shard(%d): %w", i, err)
+	}
+
+	repoImpl, _, err := newRepository(cfg.MySQL, clusters)
+	if err != nil {
+		closeDBs(dbs)
+		return nil, nil, err
+	}
+
+	ring := redis.NewRing(&redis.RingOptions{Addrs: cfg.Redis.Addrs})
+	// the CLI only publishes the deleted keys, the instances serving the links evict them from their local cache
+	c, nc, _ := newCaches(cfg.Cache, ring)
+
+	return usecase.NewShortUrlAdminUseCase(repoImpl, c, nc), func() {
+		ring.Close()
+		closeDBs(dbs)
+	}, nil
+}
+
+// printer prints the results in tables or JSON
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func (p *printer) shortUrls(objs []*domain.ShortUrlDto) error {
+	views := make([]shortUrlView, len(objs))
+	for i, obj := range objs {
+		views[i] = shortUrlView{
+			ID:        obj.TargetID,
+			Url:       obj.Url,
+			Status:    obj.Status.String(),
+			ExpireAt:  obj.ExpireAt,
+			CreatedAt: obj.CreatedAt,
+		}
+	}
+	if p.json {
+		return p.encode(views)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tEXPIRE AT\tCREATED AT\tURL")
+	for _, view := range views {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", view.ID, view.Status, formatTime(view.ExpireAt), formatTime(view.CreatedAt), view.Url)
+	}
+	return tw.Flush()
+}
+
+func (p *printer) clicks(objs []*domain.ClickDto) error {
+	views := make([]clickView, len(objs))
+	for i, obj := range objs {
+		views[i] = clickView{
+			ClickedAt: obj.ClickedAt,
+			ClickID:   obj.ClickID,
+			Rule:      obj.Rule,
+			Variant:   obj.Variant,
+			Referer:   obj.Referer,
+			UserAgent: obj.UserAgent,
+		}
+	}
+	if p.json {
+		return p.encode(views)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "CLICKED AT\tCLICK ID\tRULE\tVARIANT\tREFERER\tUSER AGENT")
+	for _, view := range views {
+		rule, variant := "-", "-"
+		if view.Rule > 0 {
+			rule = strconv.Itoa(view.Rule)
+		}
+		if view.Variant > 0 {
+			variant = strconv.Itoa(view.Variant)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", formatTime(view.ClickedAt), orDash(view.ClickID), rule, variant, orDash(view.Referer), orDash(view.UserAgent))
+	}
+	return tw.Flush()
+}
+
+func (p *printer) message(fields map[string]string) error {
+	if p.json {
+		return p.encode(fields)
+	}
+	_, err := fmt.Fprintf(p.w, "%s %s\n", fields["result"], fields["id"])
+	return err
+}
+
+func (p *printer) encode(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -1,51 +1,509 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"database/sql"
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
 	"time"
 
 	repo "github.com/Hao1995/short-url/internal/adapter/repository/mysql"
+	"github.com/Hao1995/short-url/internal/config"
 	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/metrics"
 	"github.com/Hao1995/short-url/internal/router/handler"
+	"github.com/Hao1995/short-url/internal/router/middleware"
 	"github.com/Hao1995/short-url/internal/usecase"
-	"github.com/Hao1995/short-url/pkg/migrationkit"
+	"github.com/Hao1995/short-url/pkg/cachekit"
+	"github.com/Hao1995/short-url/pkg/geokit"
+	"github.com/Hao1995/short-url/pkg/lifecyclekit"
+	"github.com/Hao1995/short-url/pkg/logkit"
+	"github.com/Hao1995/short-url/pkg/policykit"
+	"github.com/Hao1995/short-url/pkg/scankit"
+	"github.com/Hao1995/short-url/pkg/tracekit"
 
-	"github.com/fvbock/endless"
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/viney-shih/go-cache"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
 const (
-	MIGRATION_DIR = "database/migration"
+	// SHUTDOWN_STEP_TIMEOUT bounds each step of the shutdown, except the ones with their own timeout
+	SHUTDOWN_STEP_TIMEOUT = 5 * time.Second
+)
+
+var (
+	configPath         = flag.String("config", "", "path of the YAML or TOML config file, the env vars override it")
+	configPollInterval = flag.Duration("config-poll-interval", 10*time.Second, "interval of checking the config file changed, 0 disables it")
 )
 
 func main() {
-	// Migration
-	dsn := fmt.Sprintf(
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fatal("failed to load config", logkit.Err(err))
+	}
+	// the subcommands print their results to stdout
+	logOutput := os.Stdout
+	if flag.NArg() > 0 {
+		logOutput = os.Stderr
+	}
+	if err := logkit.Setup(logOutput, logkit.Config{Level: cfg.Log.Level, SampleRatio: cfg.Log.SampleRatio}); err != nil {
+		fatal("failed to set up logging", logkit.Err(err))
+	}
+	slog.Info("config loaded", "cfg", cfg)
+
+	ctx := context.Background()
+	switch flag.Arg(0) {
+	case "":
+	case "migrate":
+		os.Exit(runMigrate(ctx, cfg, flag.Args()[1:]))
+	case "admin":
+		os.Exit(runAdmin(ctx, cfg, flag.Args()[1:]))
+	default:
+		fatal("unknown subcommand", "subcommand", flag.Arg(0))
+	}
+
+	lc := lifecyclekit.New()
+	watcher := config.NewWatcher(*configPath, cfg, *configPollInterval)
+
+	// Init tracing
+	shutdownTracing, err := tracekit.Setup(ctx, tracekit.Config{
+		ServiceName:  cfg.App.Name,
+		Exporter:     tracekit.Exporter(cfg.Trace.Exporter),
+		OTLPEndpoint: cfg.Trace.OTLPEndpoint,
+		OTLPInsecure: cfg.Trace.OTLPInsecure,
+		SampleRatio:  cfg.Trace.SampleRatio,
+	})
+	if err != nil {
+		fatal("failed to set up tracing", logkit.Err(err))
+	}
+
+	healthHlr := handler.NewHealthHandler(cfg.App.ReadinessTimeout)
+
+	dbs := []*gorm.DB{}
+	clusters := []*repo.Cluster{}
+	for i, addrs := range shardAddrs(cfg.MySQL) {
+		// Init DB connection
+		dsn, err := mysqlDSN(cfg.MySQL, addrs[0])
+		if err != nil {
+			fatal("invalid address of the shard", "shard", i, logkit.Err(err))
+		}
+		db, err := openDB(dsn)
+		if err != nil {
+			fatal("failed to connect to DB of the shard", "shard", i, logkit.Err(err))
+		}
+		dbs = append(dbs, db)
+
+		// Migration, which is run by `migrate up` before deploying in the other environments
+		if cfg.App.Env == "dev" {
+			if err := migrateUp(ctx, db); err != nil {
+				fatal("failed to migrate database", "shard", i, logkit.Err(err))
+			}
+			slog.Info("Migrate the DB of the shard successfully", "shard", i)
+		}
+
+		registerDBStats(db, fmt.Sprintf("shard%d_primary", i))
+
+		replicas := []*gorm.DB{}
+		for j, addr := range addrs[1:] {
+			dsn, err := mysqlDSN(cfg.MySQL, addr)
+			if err != nil {
+				fatal("invalid replica address of the shard", "shard", i, logkit.Err(err))
+			}
+			replica, err := openDB(dsn)
+			if err != nil {
+				fatal("failed to connect to the replica of the shard", "shard", i, "addr", addr, logkit.Err(err))
+			}
+			dbs = append(dbs, replica)
+			registerDBStats(replica, fmt.Sprintf("shard%d_replica%d", i, j))
+			replicas = append(replicas, replica)
+		}
+		cluster := repo.NewCluster(db, replicas...)
+		lc.Go(func(ctx context.Context) {
+			cluster.HealthCheck(ctx, cfg.MySQL.HealthCheckInterval, cfg.MySQL.HealthCheckTimeout)
+		})
+		clusters = append(clusters, cluster)
+		healthHlr.Register(fmt.Sprintf("mysql_shard%d", i), cluster)
+		slog.Info("Connect to the DB of the shard successfully", "shard", i, "replicas", len(replicas))
+	}
+
+	// Init Cache
+	ring := redis.NewRing(&redis.RingOptions{Addrs: cfg.Redis.Addrs})
+	healthHlr.Register("redis", handler.PingerFunc(func(ctx context.Context) error {
+		return ring.ForEachShard(ctx, func(ctx context.Context, client *redis.Client) error {
+			return client.Ping(ctx).Err()
+		})
+	}))
+	c, nc, broadcaster := newCaches(cfg.Cache, ring)
+	lc.Go(broadcaster.Run)
+
+	// Init URL policy
+	rules, err := policyRules(cfg)
+	if err != nil {
+		fatal("failed to load the rules of the url policy", logkit.Err(err))
+	}
+	policy, err := policykit.New(rules)
+	if err != nil {
+		fatal("failed to init the url policy", logkit.Err(err))
+	}
+
+	// Init pages
+	templates, err := handler.LoadTemplates(cfg.App.TemplateDir)
+	if err != nil {
+		fatal("failed to load the templates", logkit.Err(err))
+	}
+	interstitial, err := handler.NewInterstitial(interstitialRules(cfg))
+	if err != nil {
+		fatal("failed to init the interstitial rules", logkit.Err(err))
+	}
+	fallbacks, err := handler.NewFallbacks(fallbackURLs(cfg))
+	if err != nil {
+		fatal("failed to init the fallback urls", logkit.Err(err))
+	}
+
+	// Init GeoIP
+	geoDB, err := geokit.Open(cfg.Geo.DBPath)
+	if err != nil {
+		fatal("failed to load the GeoIP database", logkit.Err(err))
+	}
+	lc.Go(func(ctx context.Context) {
+		geoDB.Run(ctx, cfg.Geo.DBReloadInterval)
+	})
+	proxies, err := geokit.NewProxies(cfg.Geo.TrustedProxies)
+	if err != nil {
+		fatal("failed to init the trusted proxies", logkit.Err(err))
+	}
+
+	// Init split links, the variants of the visitors are kept across the instances and the restarts by the shared secret
+	cookieSecret := []byte(cfg.Split.CookieSecret)
+	if len(cookieSecret) == 0 {
+		slog.Warn("split.cookie_secret is empty, generate a random one, which keeps the visitors on their variants within this instance only")
+		cookieSecret = make([]byte, 32)
+		if _, err := rand.Read(cookieSecret); err != nil {
+			fatal("failed to generate the cookie secret", logkit.Err(err))
+		}
+	}
+	splitter := handler.NewSplitter(handler.SplitConfig{
+		Secret: cookieSecret,
+		MaxAge: cfg.Split.CookieMaxAge,
+		Secure: strings.HasPrefix(cfg.App.Host, "https://"),
+	})
+
+	// Init URL scanner
+	var scanner usecase.URLScanner
+	if cfg.Scan.Mode != string(usecase.ScanModeNone) {
+		threatDB, err := scankit.Open(cfg.Scan.DBPath)
+		if err != nil {
+			fatal("failed to load the threat lists", logkit.Err(err))
+		}
+		lc.Go(func(ctx context.Context) {
+			threatDB.Run(ctx, cfg.Scan.DBReloadInterval)
+		})
+		scanner = threatDB
+		slog.Info("Load the threat lists successfully", "size", threatDB.Len())
+	}
+
+	// Hot reload the config
+	watcher.OnReload(func(cfg *config.Config) {
+		c.SetSettings(cacheSetting(cfg.Cache), paramTemplateCacheSetting(cfg.Cache))
+		nc.SetSettings(negativeCacheSetting(cfg.Cache))
+		if err := logkit.SetLevel(cfg.Log.Level); err != nil {
+			slog.Error("failed to set the log level", logkit.Err(err))
+		}
+		logkit.SetSampleRatio(cfg.Log.SampleRatio)
+		if rules, err := policyRules(cfg); err != nil {
+			slog.Error("failed to load the rules of the url policy", logkit.Err(err))
+		} else if err := policy.SetRules(rules); err != nil {
+			slog.Error("failed to set the rules of the url policy", logkit.Err(err))
+		}
+		if err := interstitial.SetRules(interstitialRules(cfg)); err != nil {
+			slog.Error("failed to set the interstitial rules", logkit.Err(err))
+		}
+		if err := fallbacks.SetURLs(fallbackURLs(cfg)); err != nil {
+			slog.Error("failed to set the fallback urls", logkit.Err(err))
+		}
+		if ok, err := geoDB.Reload(cfg.Geo.DBPath); err != nil {
+			slog.Error("failed to reload the GeoIP database, keep the current one", "path", cfg.Geo.DBPath, logkit.Err(err))
+		} else if ok {
+			slog.Info("reload the GeoIP database", "path", cfg.Geo.DBPath)
+		}
+		if err := proxies.Set(cfg.Geo.TrustedProxies); err != nil {
+			slog.Error("failed to set the trusted proxies", logkit.Err(err))
+		}
+		slog.Info("config reloaded", "cfg", cfg)
+	})
+	lc.Go(watcher.Run)
+
+	// DI
+	repoImpl, idGen, err := newRepository(cfg.MySQL, clusters)
+	if err != nil {
+		fatal("failed to init the repository", logkit.Err(err))
+	}
+	clickRecorder := usecase.NewBatchClickRecorder(repoImpl, usecase.ClickRecorderConfig{
+		BufferSize:    cfg.Click.BufferSize,
+		BatchSize:     cfg.Click.BatchSize,
+		FlushInterval: cfg.Click.FlushInterval,
+	})
+	lc.Go(clickRecorder.Run)
+	adminUC := usecase.NewShortUrlAdminUseCase(repoImpl, c, nc)
+	if scanner != nil {
+		rescanner := usecase.NewRescanner(repoImpl, scanner, adminUC, usecase.RescannerConfig{
+			Timeout:         cfg.Scan.Timeout,
+			Interval:        cfg.Scan.RescanInterval,
+			PendingInterval: cfg.Scan.PendingInterval,
+			BatchSize:       cfg.Scan.BatchSize,
+		})
+		lc.Go(rescanner.Run)
+	}
+	ucImpl := usecase.NewShortUrlUseCase(repoImpl, c, nc, idGen, policy, scanner, clickRecorder, usecase.Config{
+		AppHost:     cfg.App.Host,
+		ScanMode:    usecase.ScanMode(cfg.Scan.Mode),
+		ScanTimeout: cfg.Scan.Timeout,
+	})
+	paramTemplateUC := usecase.NewShortUrlParamTemplateUseCase(repoImpl, c)
+	hlrImpl := handler.NewShortUrlHandler(ucImpl, paramTemplateUC, templates, interstitial, fallbacks, handler.NewGeo(geoDB, proxies), splitter, handler.RedirectConfig{
+		Code:   cfg.Redirect.Code,
+		MaxAge: cfg.Redirect.MaxAge,
+	})
+	reportHlr := handler.NewReportHandler(usecase.NewShortUrlReportUseCase(repoImpl, ucImpl, adminUC))
+	paramTemplateHlr := handler.NewParamTemplateHandler(paramTemplateUC)
+	statsHlr := handler.NewStatsHandler(adminUC)
+
+	// Run admin server, which isn't exposed to the public
+	adminSrv := &http.Server{Addr: ":" + cfg.App.AdminPort, Handler: RegisterAdminGinRouter(reportHlr, paramTemplateHlr, statsHlr)}
+	go serve("admin", adminSrv)
+
+	// Run server
+	srv := &http.Server{Addr: ":" + cfg.App.Port, Handler: RegisterGinRouter(hlrImpl, reportHlr, healthHlr, proxies)}
+	go serve("API", srv)
+
+	// Shut down in order: stop taking the traffic, stop the producers, flush the pipelines, then close the stores
+	lc.Append("readiness", cfg.App.ShutdownDrainDelay+time.Second, func(ctx context.Context) error {
+		// turn not ready and keep serving for a while before the server stops accepting
+		healthHlr.Shutdown()
+		time.Sleep(cfg.App.ShutdownDrainDelay)
+		return nil
+	})
+	lc.Append("API server", cfg.App.ShutdownTimeout, srv.Shutdown)
+	lc.Append("admin server", SHUTDOWN_STEP_TIMEOUT, adminSrv.Shutdown)
+	lc.Append("background workers", SHUTDOWN_STEP_TIMEOUT, lc.StopWorkers)
+	lc.Append("tracing", SHUTDOWN_STEP_TIMEOUT, shutdownTracing)
+	lc.Append("redis", SHUTDOWN_STEP_TIMEOUT, func(ctx context.Context) error {
+		return ring.Close()
+	})
+	lc.Append("mysql", SHUTDOWN_STEP_TIMEOUT, func(ctx context.Context) error {
+		return closeDBs(dbs)
+	})
+
+	lc.Wait(ctx, syscall.SIGINT, syscall.SIGTERM)
+	slog.Info("Shutting down ...")
+	if err := lc.Shutdown(ctx); err != nil {
+		fatal("failed to shut down gracefully", logkit.Err(err))
+	}
+	slog.Info("Shut down gracefully")
+}
+
+// serve runs the server until it's shut down
+func serve(name string, srv *http.Server) {
+	slog.Info("Start server ...", "server", name, "addr", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fatal("failed to run server", "server", name, logkit.Err(err))
+	}
+}
+
+// fatal logs the error and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// shardAddrs returns the addresses of each shard, the primary goes first
+func shardAddrs(cfg config.MySQL) [][]string {
+	shards := cfg.Shards
+	if len(shards) == 0 {
+		shards = []string{strings.Join(append([]string{net.JoinHostPort(cfg.Host, cfg.Port)}, cfg.ReplicaAddrs...), "|")}
+	}
+
+	addrs := make([][]string, len(shards))
+	for i, shard := range shards {
+		addrs[i] = strings.Split(shard, "|")
+	}
+	return addrs
+}
+
+func mysqlDSN(cfg config.MySQL, addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(
 		"%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=UTC",
-		cfg.MySQL.User,
-		cfg.MySQL.Password,
-		cfg.MySQL.Host,
-		cfg.MySQL.Port,
-		cfg.MySQL.DB,
-	)
-	if cfg.App.Env == "dev" {
-		if err := migrationkit.GooseMigrate(dsn, MIGRATION_DIR); err != nil {
-			log.Fatalf("failed to connect to migrate database: %s", map[string]interface{}{"error": err.Error(), "dsn": dsn})
+		cfg.User,
+		cfg.Password,
+		host,
+		port,
+		cfg.DB,
+	), nil
+}
+
+// newRepository generates the repository over the clusters of the shards,
+// and the id generator which the sharding strategy can route
+func newRepository(cfg config.MySQL, clusters []*repo.Cluster) (usecase.Repository, usecase.IDGenerator, error) {
+	repoCfg := repo.Config{
+		CreateTimeout:        cfg.CreateTimeout,
+		GetTimeout:           cfg.GetTimeout,
+		ListTimeout:          cfg.ListTimeout,
+		ReadYourWritesWindow: cfg.ReadYourWritesWindow,
+		MissConfirmRate:      cfg.MissConfirmRate,
+	}
+	if len(clusters) == 1 {
+		return repo.NewShortUrlRepository(clusters[0], repoCfg), usecase.CRC32IDGenerator, nil
+	}
+
+	shardRepos := make([]usecase.Repository, len(clusters))
+	for i, cluster := range clusters {
+		shardRepos[i] = repo.NewShortUrlRepository(cluster, repoCfg)
+	}
+	strategy := repo.ShardStrategy(cfg.ShardStrategy)
+	repoImpl, err := repo.NewShardedShortUrlRepository(shardRepos, strategy)
+	if err != nil {
+		return nil, nil, err
+	}
+	if strategy == repo.ShardStrategyPrefix {
+		return repoImpl, usecase.NewShardedIDGenerator(len(clusters)), nil
+	}
+	return repoImpl, usecase.CRC32IDGenerator, nil
+}
+
+// newCaches generates the positive and the negative cache over the Redis ring, and the broadcaster evicting
+// the keys deleted on the other instances from their local cache
+func newCaches(cfg config.Cache, ring *redis.Ring) (*cachekit.Cache, *cachekit.Cache, *cachekit.Broadcaster) {
+	hooks := cachekit.Hooks{
+		OnCacheHit:   metrics.OnCacheHit,
+		OnCacheMiss:  metrics.OnCacheMiss,
+		OnTierLookup: metrics.OnCacheTierLookup,
+	}
+	rds := cache.NewRedis(ring)
+	c := cachekit.New(rds, cache.NewTinyLFU(cfg.Size), []cachekit.Setting{cacheSetting(cfg), paramTemplateCacheSetting(cfg)}, hooks)
+
+	// The negative cache has its own local cache, so that probing non-existent ids can't evict the existing ones.
+	// Its misses are recorded by the use case for the ids not found only, since all the ids are probed in it first.
+	nc := cachekit.New(rds, cache.NewTinyLFU(cfg.NegativeSize), []cachekit.Setting{negativeCacheSetting(cfg)}, cachekit.Hooks{
+		OnCacheHit:   metrics.OnCacheHit,
+		OnTierLookup: metrics.OnCacheTierLookup,
+	})
+
+	// The broadcaster has its own subscription, since the Redis adapter subscribes once
+	broadcaster := cachekit.NewBroadcaster(cache.NewRedis(ring), domain.CACHE_TOPIC_EVICT)
+	broadcaster.Register(c)
+	broadcaster.Register(nc)
+	return c, nc, broadcaster
+}
+
+// policyRules collects the rules of the url policy, including the patterns in the deny list file.
+// The host of the short urls and the hosts of the tenants are always the own hosts.
+func policyRules(cfg *config.Config) (policykit.Rules, error) {
+	denyHosts := slices.Clone(cfg.Policy.DenyHosts)
+	if cfg.Policy.DenyHostsFile != "" {
+		patterns, err := policykit.ReadPatternsFile(cfg.Policy.DenyHostsFile)
+		if err != nil {
+			return policykit.Rules{}, fmt.Errorf("failed to read the deny hosts file: %w", err)
+		}
+		denyHosts = append(denyHosts, patterns...)
+	}
+
+	appHost, err := url.Parse(cfg.App.Host)
+	if err != nil {
+		return policykit.Rules{}, err
+	}
+	ownHosts := append([]string{appHost.Hostname()}, cfg.Policy.OwnHosts...)
+	for _, host := range slices.Sorted(maps.Keys(cfg.Tenants)) {
+		// the tenants are keyed by the Host of the requests, which might carry the port
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		ownHosts = append(ownHosts, host)
+	}
+	return policykit.Rules{
+		DenyHosts: denyHosts,
+		OwnHosts:  ownHosts,
+	}, nil
+}
+
+func interstitialRules(cfg *config.Config) handler.InterstitialRules {
+	tenants := map[string]domain.Interstitial{}
+	for host, tenant := range cfg.Tenants {
+		if tenant.Interstitial != "" {
+			tenants[host] = domain.Interstitial(tenant.Interstitial)
 		}
-		log.Print("Migrate the DB successfully")
 	}
+	return handler.InterstitialRules{
+		Mode:       domain.Interstitial(cfg.Interstitial.Mode),
+		Tenants:    tenants,
+		AllowHosts: cfg.Interstitial.AllowHosts,
+		Delay:      cfg.Interstitial.Delay,
+	}
+}
+
+func fallbackURLs(cfg *config.Config) map[string]string {
+	urls := map[string]string{}
+	for host, tenant := range cfg.Tenants {
+		if tenant.FallbackURL != "" {
+			urls[host] = tenant.FallbackURL
+		}
+	}
+	return urls
+}
+
+func cacheSetting(cfg config.Cache) cachekit.Setting {
+	return cachekit.Setting{
+		Prefix:    domain.CACHE_PREFIX_SHORT_URL,
+		SharedTTL: time.Duration(cfg.SharedTTL) * time.Second,
+		LocalTTL:  time.Duration(cfg.LocalTTL) * time.Second,
+	}
+}
+
+// paramTemplateCacheSetting shares the TTLs of the short urls, the updates of the templates take effect
+// on the other instances once their local cache expires
+func paramTemplateCacheSetting(cfg config.Cache) cachekit.Setting {
+	return cachekit.Setting{
+		Prefix:    domain.CACHE_PREFIX_PARAM_TEMPLATE,
+		SharedTTL: time.Duration(cfg.SharedTTL) * time.Second,
+		LocalTTL:  time.Duration(cfg.LocalTTL) * time.Second,
+	}
+}
+
+func negativeCacheSetting(cfg config.Cache) cachekit.Setting {
+	return cachekit.Setting{
+		Prefix:    domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND,
+		SharedTTL: time.Duration(cfg.NegativeSharedTTL) * time.Second,
+		LocalTTL:  time.Duration(cfg.NegativeLocalTTL) * time.Second,
+	}
+}
 
-	// Init DB connection
+func openDB(dsn string) (*gorm.DB, error) {
 	sqlDB, err := sql.Open("mysql", dsn)
 	if err != nil {
-		log.Fatalf("failed to connect to DB: %s", err)
+		return nil, err
 	}
 	sqlDB.SetMaxIdleConns(100)
 	sqlDB.SetMaxOpenConns(500)
@@ -54,50 +512,62 @@ func main() {
 		Conn: sqlDB,
 	}), &gorm.Config{TranslateError: true})
 	if err != nil {
-		log.Fatalf("failed to init to Gorm client: %s", err)
+		return nil, fmt.Errorf("failed to init to Gorm client: %w", err)
+	}
+	return db, nil
+}
+
+// registerDBStats exports the stats of the connection pool
+func registerDBStats(db *gorm.DB, name string) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		slog.Error("failed to get sqlDB from gorm", logkit.Err(err))
+		return
 	}
-	defer func() {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(sqlDB, name))
+}
+
+func closeDBs(dbs []*gorm.DB) error {
+	var errs []error
+	for _, db := range dbs {
 		sqlDB, err := db.DB()
 		if err != nil {
-			log.Printf("failed to get sqlDB from gorm: %s", err)
-		} else {
-			sqlDB.Close()
+			errs = append(errs, fmt.Errorf("failed to get sqlDB from gorm: %w", err))
+			continue
+		}
+		if err := sqlDB.Close(); err != nil {
+			errs = append(errs, err)
 		}
-	}()
-	log.Print("Connect to the DB successfully")
-
-	// Init Cache
-	tinyLfu := cache.NewTinyLFU(cfg.Cache.Size)
-	rds := cache.NewRedis(redis.NewRing(&redis.RingOptions{Addrs: cfg.Redis.Addrs}))
-	cacheFactory := cache.NewFactory(rds, tinyLfu)
-
-	c := cacheFactory.NewCache([]cache.Setting{
-		{
-			Prefix: domain.CACHE_PREFIX_SHORT_URL,
-			CacheAttributes: map[cache.Type]cache.Attribute{
-				cache.SharedCacheType: {TTL: time.Duration(cfg.Cache.SharedTTL) * time.Second},
-				cache.LocalCacheType:  {TTL: time.Duration(cfg.Cache.LocalTTL) * time.Second},
-			},
-			MarshalFunc:   json.Marshal,
-			UnmarshalFunc: json.Unmarshal,
-		},
-	})
-
-	// DI
-	repoImpl := repo.NewShortUrlRepository(db)
-	ucImpl := usecase.NewShortUrlUseCase(repoImpl, c)
-	hlrImpl := handler.NewShortUrlHandler(ucImpl)
-
-	// Run server
-	log.Print("Start API server ...")
-	if err := endless.ListenAndServe(":"+cfg.App.Port, RegisterGinRouter(hlrImpl)); err != nil {
-		log.Fatalf("failed to connect to DB: %s", err)
 	}
+	return errors.Join(errs...)
 }
 
-func RegisterGinRouter(hlrImpl *handler.ShortUrlHandler) *gin.Engine {
-	r := gin.Default()
+func RegisterGinRouter(hlrImpl *handler.ShortUrlHandler, reportHlr *handler.ReportHandler, healthHlr *handler.HealthHandler, proxies *geokit.Proxies) *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery(), middleware.RequestID(), middleware.Tracing(), middleware.Metrics(), middleware.Logger(proxies))
+	r.GET("/healthz", healthHlr.Liveness)
+	r.GET("/readyz", healthHlr.Readiness)
 	r.POST("/api/v1/urls", hlrImpl.Create)
+	r.POST("/api/v1/reports", reportHlr.Create)
+	r.StaticFS(handler.STATIC_PATH, handler.Static())
 	r.GET("/:id", hlrImpl.Get)
+	r.HEAD("/:id", hlrImpl.Get)
+	r.GET("/:id/*path", hlrImpl.Get)
+	r.HEAD("/:id/*path", hlrImpl.Get)
+	return r
+}
+
+func RegisterAdminGinRouter(reportHlr *handler.ReportHandler, paramTemplateHlr *handler.ParamTemplateHandler, statsHlr *handler.StatsHandler) *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	r.GET("/api/v1/reports", reportHlr.List)
+	r.POST("/api/v1/reports/:id/resolve", reportHlr.Resolve)
+	r.POST("/api/v1/templates", paramTemplateHlr.Create)
+	r.GET("/api/v1/templates", paramTemplateHlr.List)
+	r.GET("/api/v1/templates/:name", paramTemplateHlr.Get)
+	r.PUT("/api/v1/templates/:name", paramTemplateHlr.Update)
+	r.DELETE("/api/v1/templates/:name", paramTemplateHlr.Delete)
+	r.GET("/api/v1/urls/:id/stats", statsHlr.Get)
 	return r
 }
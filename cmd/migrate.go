@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Hao1995/short-url/database"
+	"github.com/Hao1995/short-url/internal/config"
+	"github.com/Hao1995/short-url/pkg/migrationkit"
+
+	"github.com/pressly/goose/v3"
+	"gorm.io/gorm"
+)
+
+const migrateUsage = `Usage: app [-config path] migrate <command> [--dry-run]
+
+Run the embedded migrations against the primary of every shard.
+
+Commands:
+  up        apply all the pending migrations
+  down      roll back the latest applied migration
+  redo      roll back the latest applied migration and apply it again
+  status    print the state of every migration
+  version   print the version of the latest applied migration
+
+Flags:
+`
+
+func newMigrator(db *gorm.DB) (*migrationkit.Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sqlDB from gorm: %w", err)
+	}
+	return migrationkit.NewMigrator(sqlDB, database.Migrations(), migrationkit.Config{})
+}
+
+func migrateUp(ctx context.Context, db *gorm.DB) error {
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(ctx)
+	return err
+}
+
+// runMigrate runs the migrate subcommand and returns the exit code
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "print the SQL to be run by up, down and redo without running it")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), migrateUsage)
+		fs.PrintDefaults()
+	}
+
+	if len(args) == 0 {
+		fs.Usage()
+		return 2
+	}
+	cmd := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	switch cmd {
+	case "up", "down", "redo", "status", "version":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", cmd)
+		fs.Usage()
+		return 2
+	}
+
+	code := 0
+	for i, addrs := range shardAddrs(cfg.MySQL) {
+		fmt.Printf("== shard(%d) %s ==\n", i, addrs[0])
+		if err := migrateShard(ctx, cfg.MySQL, addrs[0], cmd, *dryRun, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "shard(%d): %s\n", i, err)
+			code = 1
+		}
+	}
+	return code
+}
+
+func migrateShard(ctx context.Context, cfg config.MySQL, addr string, cmd string, dryRun bool, w io.Writer) error {
+	dsn, err := mysqlDSN(cfg, addr)
+	if err != nil {
+		return err
+	}
+	db, err := openDB(dsn)
+	if err != nil {
+		return err
+	}
+	defer closeDBs([]*gorm.DB{db})
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	if dryRun && (cmd == "up" || cmd == "down" || cmd == "redo") {
+		var plans []*migrationkit.Plan
+		if cmd == "up" {
+			plans, err = migrator.PlanUp(ctx)
+		} else {
+			plans, err = migrator.PlanDown(ctx)
+		}
+		if err != nil {
+			return err
+		}
+		printPlans(w, plans, cmd)
+		return nil
+	}
+
+	switch cmd {
+	case "up":
+		results, err := migrator.Up(ctx)
+		printResults(w, results)
+		return err
+	case "down":
+		result, err := migrator.Down(ctx)
+		if result != nil {
+			printResults(w, []*goose.MigrationResult{result})
+		}
+		return err
+	case "redo":
+		results, err := migrator.Redo(ctx)
+		printResults(w, results)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "VERSION\tSTATE\tAPPLIED AT\tSOURCE")
+		for _, status := range statuses {
+			appliedAt := "-"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = status.AppliedAt.UTC().Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", status.Source.Version, status.State, appliedAt, status.Source.Path)
+		}
+		return tw.Flush()
+	case "version":
+		version, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, version)
+	}
+	return nil
+}
+
+func printPlans(w io.Writer, plans []*migrationkit.Plan, cmd string) {
+	if len(plans) == 0 {
+		fmt.Fprintln(w, "-- nothing to migrate")
+		return
+	}
+	for _, plan := range plans {
+		fmt.Fprintf(w, "-- %s %d (%s)\n%s\n\n", cmd, plan.Version, plan.Source, plan.SQL)
+	}
+	if cmd == "redo" {
+		fmt.Fprintln(w, "-- then apply the same migration again")
+	}
+}
+
+func printResults(w io.Writer, results []*goose.MigrationResult) {
+	if len(results) == 0 {
+		fmt.Fprintln(w, "no migration to apply")
+		return
+	}
+	for _, result := range results {
+		fmt.Fprintln(w, result.String())
+	}
+}
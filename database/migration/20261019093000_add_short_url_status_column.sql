@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `short_urls` ADD COLUMN `status` VARCHAR(16) NOT NULL DEFAULT 'Active' AFTER `target_id`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `short_urls` DROP COLUMN `status`;
+-- +goose StatementEnd
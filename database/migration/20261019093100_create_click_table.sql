@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `clicks` (
+	`id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+	`target_id` CHAR(8) NOT NULL,
+	`referer` VARCHAR(1024) NOT NULL,
+	`user_agent` VARCHAR(512) NOT NULL,
+	`clicked_at` DATETIME(3) NOT NULL,
+
+	INDEX `idx_target_id_clicked_at` (`target_id`, `clicked_at`)
+)
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `clicks`;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `short_urls` ADD COLUMN `creator` CHAR(64) NOT NULL DEFAULT '' AFTER `status`, ADD INDEX `idx_creator` (`creator`);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `short_urls` DROP INDEX `idx_creator`, DROP COLUMN `creator`;
+-- +goose StatementEnd
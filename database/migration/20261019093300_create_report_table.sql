@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `reports` (
+	`id` BIGINT UNSIGNED PRIMARY KEY AUTO_INCREMENT,
+	`target_id` CHAR(8) NOT NULL,
+	`reason` VARCHAR(1024) NOT NULL,
+	`contact` VARCHAR(255) NOT NULL,
+	`status` VARCHAR(16) NOT NULL DEFAULT 'Pending',
+	`created_at` DATETIME(3) NOT NULL,
+	`resolved_at` DATETIME(3) NULL,
+
+	INDEX `idx_status_created_at` (`status`, `created_at`),
+	INDEX `idx_target_id_status` (`target_id`, `status`)
+)
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `reports`;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `banned_creators` (
+	`creator` CHAR(64) PRIMARY KEY,
+	`reason` VARCHAR(255) NOT NULL,
+	`created_at` DATETIME(3) NOT NULL
+)
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `banned_creators`;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `short_urls` ADD COLUMN `interstitial` VARCHAR(16) NOT NULL DEFAULT '' AFTER `creator`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `short_urls` DROP COLUMN `interstitial`;
+-- +goose StatementEnd
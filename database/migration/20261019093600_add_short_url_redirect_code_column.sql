@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `short_urls` ADD COLUMN `redirect_code` SMALLINT UNSIGNED NOT NULL DEFAULT 0 AFTER `interstitial`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `short_urls` DROP COLUMN `redirect_code`;
+-- +goose StatementEnd
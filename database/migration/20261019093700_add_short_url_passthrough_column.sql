@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `short_urls` ADD COLUMN `passthrough` VARCHAR(16) NOT NULL DEFAULT '' AFTER `redirect_code`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `short_urls` DROP COLUMN `passthrough`;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `short_urls` ADD COLUMN `template` VARCHAR(64) NOT NULL DEFAULT '' AFTER `passthrough`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `short_urls` DROP COLUMN `template`;
+-- +goose StatementEnd
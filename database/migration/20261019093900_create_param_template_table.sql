@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `param_templates` (
+	`name` VARCHAR(64) PRIMARY KEY,
+	`query` VARCHAR(2048) NOT NULL,
+	`created_at` DATETIME(3) NOT NULL,
+	`updated_at` DATETIME(3) NOT NULL
+)
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE `param_templates`;
+-- +goose StatementEnd
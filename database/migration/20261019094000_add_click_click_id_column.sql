@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `clicks` ADD COLUMN `click_id` VARCHAR(16) NOT NULL DEFAULT '' AFTER `target_id`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `clicks` DROP COLUMN `click_id`;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `clicks` ADD COLUMN `rule` TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER `click_id`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `clicks` DROP COLUMN `rule`;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE `clicks` ADD COLUMN `variant` TINYINT UNSIGNED NOT NULL DEFAULT 0 AFTER `rule`;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE `clicks` DROP COLUMN `variant`;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+// Package database embeds the migrations, so that the binary migrates the DB without the files on the disk
+package database
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migration/*.sql
+var migrations embed.FS
+
+// Migrations returns the goose migrations at the root of the file system
+func Migrations() fs.FS {
+	fsys, err := fs.Sub(migrations, "migration")
+	if err != nil {
+		// the directory is embedded at compile time
+		panic(err)
+	}
+	return fsys
+}
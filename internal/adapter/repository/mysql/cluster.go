@@ -0,0 +1,220 @@
+package mysql
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Cluster routes the writes to the primary and the reads to the healthy replicas.
+// It falls back to the primary when there is no healthy replica.
+type Cluster struct {
+	primary  *gorm.DB
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+type replica struct {
+	db      *gorm.DB
+	healthy atomic.Bool
+}
+
+// NewCluster generates a cluster with one primary and zero or more replicas.
+// All the replicas are regarded as healthy until the first failed health check.
+func NewCluster(primary *gorm.DB, replicas ...*gorm.DB) *Cluster {
+	c := &Cluster{primary: primary}
+	for _, db := range replicas {
+		r := &replica{db: db}
+		r.healthy.Store(true)
+		c.replicas = append(c.replicas, r)
+	}
+	return c
+}
+
+// Primary returns the connection to the primary
+func (c *Cluster) Primary() *gorm.DB {
+	return c.primary
+}
+
+// Replica returns the connection to the next healthy replica in round-robin order.
+// The bool is false when the primary is returned because there is no healthy replica.
+func (c *Cluster) Replica() (*gorm.DB, bool) {
+	if r := c.pick(); r != nil {
+		return r.db, true
+	}
+	return c.primary, false
+}
+
+// MarkUnhealthy takes the replica out of rotation until the next successful health check
+func (c *Cluster) MarkUnhealthy(db *gorm.DB) {
+	for i, r := range c.replicas {
+		if r.db == db && r.healthy.CompareAndSwap(true, false) {
+			slog.Warn("mark the replica as unhealthy", "replica", i)
+		}
+	}
+}
+
+// HealthCheck pings all the replicas every interval until the context is done
+func (c *Cluster) HealthCheck(ctx context.Context, interval, timeout time.Duration) {
+	if len(c.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		c.check(ctx, timeout)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DBs returns the connections to the primary and all the replicas
+func (c *Cluster) DBs() []*gorm.DB {
+	dbs := []*gorm.DB{c.primary}
+	for _, r := range c.replicas {
+		dbs = append(dbs, r.db)
+	}
+	return dbs
+}
+
+// Ping checks the primary, the cluster is still able to serve without the replicas
+func (c *Cluster) Ping(ctx context.Context) error {
+	return ping(ctx, c.primary)
+}
+
+func (c *Cluster) pick() *replica {
+	n := uint64(len(c.replicas))
+	if n == 0 {
+		return nil
+	}
+
+	start := c.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		if r := c.replicas[(start+i)%n]; r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+func (c *Cluster) check(ctx context.Context, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for i, r := range c.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := withTimeout(ctx, timeout)
+			defer cancel()
+
+			healthy := ping(ctx, r.db) == nil
+			if r.healthy.Swap(healthy) != healthy {
+				slog.Info("replica health changed", "replica", i, "healthy", healthy)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// recentWrites remembers the ids written in the last window, so that reading them
+// goes to the primary instead of the replicas that might not have caught up yet.
+type recentWrites struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	ids     map[string]time.Time
+	sweptAt time.Time
+}
+
+func newRecentWrites(window time.Duration) *recentWrites {
+	return &recentWrites{
+		window: window,
+		ids:    map[string]time.Time{},
+	}
+}
+
+func (w *recentWrites) add(id string) {
+	if w.window <= 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	t := now()
+	w.ids[id] = t
+
+	// sweep the stale ids at most once per window
+	if t.Sub(w.sweptAt) < w.window {
+		return
+	}
+	for k, v := range w.ids {
+		if t.Sub(v) >= w.window {
+			delete(w.ids, k)
+		}
+	}
+	w.sweptAt = t
+}
+
+// missConfirmer bounds the misses of the replicas confirmed on the primary per second, so that probing the ids
+// not existing can't move the reads of the replicas to the primary
+type missConfirmer struct {
+	rate int
+
+	mu       sync.Mutex
+	windowAt time.Time
+	count    int
+}
+
+func newMissConfirmer(rate int) *missConfirmer {
+	return &missConfirmer{rate: rate}
+}
+
+// allow reports whether the miss can be confirmed within the budget of the current second
+func (c *missConfirmer) allow() bool {
+	if c.rate <= 0 {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := now()
+	if t.Sub(c.windowAt) >= time.Second {
+		c.windowAt, c.count = t, 0
+	}
+	if c.count >= c.rate {
+		return false
+	}
+	c.count++
+	return true
+}
+
+func (w *recentWrites) contains(id string) bool {
+	if w.window <= 0 {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	t, ok := w.ids[id]
+	return ok && now().Sub(t) < w.window
+}
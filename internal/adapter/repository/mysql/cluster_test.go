@@ -0,0 +1,204 @@
+package mysql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type ClusterTestSuite struct {
+	suite.Suite
+
+	now time.Time
+}
+
+func TestClusterTestSuite(t *testing.T) {
+	suite.Run(t, new(ClusterTestSuite))
+}
+
+func (s *ClusterTestSuite) SetupSuite() {
+	s.now = time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC)
+	now = func() time.Time {
+		return s.now
+	}
+}
+
+// unreachableDB returns a connection that never reaches a server
+func (s *ClusterTestSuite) unreachableDB() *gorm.DB {
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "root:password@tcp(127.0.0.1:1)/short_url?timeout=100ms",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DisableAutomaticPing: true})
+	s.Require().NoError(err)
+	return db
+}
+
+func (s *ClusterTestSuite) TestReplica() {
+	primary := s.unreachableDB()
+	replica1 := s.unreachableDB()
+	replica2 := s.unreachableDB()
+
+	for _, t := range []struct {
+		name       string
+		cluster    func() *Cluster
+		expDBs     []*gorm.DB
+		expReplica bool
+	}{
+		{
+			name: "no replica, read from the primary",
+			cluster: func() *Cluster {
+				return NewCluster(primary)
+			},
+			expDBs:     []*gorm.DB{primary, primary},
+			expReplica: false,
+		},
+		{
+			name: "read from the replicas in round-robin order",
+			cluster: func() *Cluster {
+				return NewCluster(primary, replica1, replica2)
+			},
+			expDBs:     []*gorm.DB{replica2, replica1, replica2},
+			expReplica: true,
+		},
+		{
+			name: "skip the unhealthy replica",
+			cluster: func() *Cluster {
+				c := NewCluster(primary, replica1, replica2)
+				c.MarkUnhealthy(replica2)
+				return c
+			},
+			expDBs:     []*gorm.DB{replica1, replica1},
+			expReplica: true,
+		},
+		{
+			name: "all replicas are unhealthy, fall back to the primary",
+			cluster: func() *Cluster {
+				c := NewCluster(primary, replica1, replica2)
+				c.MarkUnhealthy(replica1)
+				c.MarkUnhealthy(replica2)
+				return c
+			},
+			expDBs:     []*gorm.DB{primary, primary},
+			expReplica: false,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			c := t.cluster()
+			for _, expDB := range t.expDBs {
+				db, isReplica := c.Replica()
+				s.Same(expDB, db)
+				s.Equal(t.expReplica, isReplica)
+			}
+		})
+	}
+}
+
+func (s *ClusterTestSuite) TestHealthCheck() {
+	primary := s.unreachableDB()
+	replica := s.unreachableDB()
+	c := NewCluster(primary, replica)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	c.HealthCheck(ctx, time.Hour, 100*time.Millisecond)
+
+	db, isReplica := c.Replica()
+	s.Same(primary, db)
+	s.False(isReplica)
+}
+
+func (s *ClusterTestSuite) TestPing() {
+	c := NewCluster(s.unreachableDB())
+	s.Error(c.Ping(context.Background()))
+}
+
+func (s *ClusterTestSuite) TestRecentWrites() {
+	for _, t := range []struct {
+		name    string
+		window  time.Duration
+		elapsed time.Duration
+		exp     bool
+	}{
+		{
+			name:    "written within the window",
+			window:  5 * time.Second,
+			elapsed: 4 * time.Second,
+			exp:     true,
+		},
+		{
+			name:    "written before the window",
+			window:  5 * time.Second,
+			elapsed: 5 * time.Second,
+			exp:     false,
+		},
+		{
+			name:    "disabled",
+			window:  0,
+			elapsed: 0,
+			exp:     false,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			defer func() {
+				now = func() time.Time {
+					return s.now
+				}
+			}()
+
+			w := newRecentWrites(t.window)
+			w.add("testid1")
+
+			now = func() time.Time {
+				return s.now.Add(t.elapsed)
+			}
+			s.Equal(t.exp, w.contains("testid1"))
+			s.False(w.contains("testid2"))
+		})
+	}
+}
+
+func (s *ClusterTestSuite) TestMissConfirmer() {
+	for _, t := range []struct {
+		name    string
+		rate    int
+		elapsed time.Duration
+		exp     []bool
+	}{
+		{
+			name: "confirm within the rate of the second",
+			rate: 2,
+			exp:  []bool{true, true, false},
+		},
+		{
+			name:    "confirm again in the next second",
+			rate:    2,
+			elapsed: time.Second,
+			exp:     []bool{true, true, true},
+		},
+		{
+			name: "disabled",
+			rate: 0,
+			exp:  []bool{false, false, false},
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			defer func() {
+				now = func() time.Time {
+					return s.now
+				}
+			}()
+
+			c := newMissConfirmer(t.rate)
+			allowed := []bool{c.allow(), c.allow()}
+			now = func() time.Time {
+				return s.now.Add(t.elapsed)
+			}
+			allowed = append(allowed, c.allow())
+			s.Equal(t.exp, allowed)
+		})
+	}
+}
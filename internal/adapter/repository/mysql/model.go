@@ -6,9 +6,89 @@ import (
 
 // ShortUrl represents as table `short_urls`.
 type ShortUrl struct {
-	ID        uint `gorm:"primaryKey, autoIncrement"`
-	Url       string
-	TargetID  string `gorm:"uniqueIndex"`
+	ID           uint `gorm:"primaryKey, autoIncrement"`
+	Url          string
+	TargetID     string `gorm:"uniqueIndex"`
+	Status       string
+	Creator      string
+	Interstitial string
+	RedirectCode int
+	Passthrough  string
+	Template     string
+	// Rules, GeoRules and Variants are stored as JSON, which are NULL without them
+	Rules     []RedirectRule `gorm:"serializer:json"`
+	GeoRules  []GeoRule      `gorm:"serializer:json"`
+	Variants  []Variant      `gorm:"serializer:json"`
 	ExpireAt  time.Time
 	CreatedAt time.Time
 }
+
+// RedirectRule is the element of column `short_urls.rules`.
+type RedirectRule struct {
+	Platform string `json:"platform"`
+	Url      string `json:"url"`
+}
+
+// GeoRule is the element of column `short_urls.geo_rules`.
+type GeoRule struct {
+	Country   string `json:"country,omitempty"`
+	Continent string `json:"continent,omitempty"`
+	Url       string `json:"url"`
+}
+
+// Variant is the element of column `short_urls.variants`.
+type Variant struct {
+	Url    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// Click represents as table `clicks`.
+type Click struct {
+	ID        uint64 `gorm:"primaryKey, autoIncrement"`
+	TargetID  string
+	ClickID   string
+	Rule      int
+	Variant   int
+	Referer   string
+	UserAgent string
+	ClickedAt time.Time
+}
+
+// the max length of the columns of table `clicks`
+const (
+	maxRefererLen   = 1024
+	maxUserAgentLen = 512
+)
+
+// Report represents as table `reports`.
+type Report struct {
+	ID         uint64 `gorm:"primaryKey, autoIncrement"`
+	TargetID   string
+	Reason     string
+	Contact    string
+	Status     string
+	CreatedAt  time.Time
+	ResolvedAt *time.Time
+}
+
+// BannedCreator represents as table `banned_creators`, which lives in the first shard only.
+type BannedCreator struct {
+	Creator   string `gorm:"primaryKey"`
+	Reason    string
+	CreatedAt time.Time
+}
+
+// the max length of the columns of table `reports` and `banned_creators`
+const (
+	maxReasonLen    = 1024
+	maxContactLen   = 255
+	maxBanReasonLen = 255
+)
+
+// ParamTemplate represents as table `param_templates`, which lives in the first shard only.
+type ParamTemplate struct {
+	Name      string `gorm:"primaryKey"`
+	Query     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
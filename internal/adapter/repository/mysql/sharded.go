@@ -0,0 +1,295 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/usecase"
+	"github.com/Hao1995/short-url/pkg/shardkit"
+	"golang.org/x/sync/errgroup"
+)
+
+// ShardStrategy decides how the target id is routed to the shard
+type ShardStrategy string
+
+const (
+	// ShardStrategyHash routes by the crc32 checksum of the target id
+	ShardStrategyHash ShardStrategy = "hash"
+	// ShardStrategyPrefix routes by the shard number embedded in the target id, see usecase.NewShardedIDGenerator
+	ShardStrategyPrefix ShardStrategy = "prefix"
+)
+
+type ShardedShortUrlRepository struct {
+	shards   []usecase.Repository
+	strategy ShardStrategy
+}
+
+// NewShardedShortUrlRepository generates the repository routing the records across the shards by the target id
+func NewShardedShortUrlRepository(shards []usecase.Repository, strategy ShardStrategy) (usecase.Repository, error) {
+	if len(shards) == 0 || len(shards) > shardkit.MaxShards {
+		return nil, fmt.Errorf("the number of shards should be between 1 and %d, got %d", shardkit.MaxShards, len(shards))
+	}
+	if strategy != ShardStrategyHash && strategy != ShardStrategyPrefix {
+		return nil, fmt.Errorf("unknown shard strategy: %s", strategy)
+	}
+
+	return &ShardedShortUrlRepository{
+		shards:   shards,
+		strategy: strategy,
+	}, nil
+}
+
+// Create creates short_url record in the shard of the target id
+func (repo *ShardedShortUrlRepository) Create(ctx context.Context, createReqDto *domain.CreateReqDto) (string, error) {
+	shard, err := repo.shard(createReqDto.TargetID)
+	if err != nil {
+		return "", err
+	}
+	return shard.Create(ctx, createReqDto)
+}
+
+// Get gets short url record from the shard of the id
+func (repo *ShardedShortUrlRepository) Get(ctx context.Context, id string) (*domain.GetRespDto, error) {
+	shard, err := repo.shard(id)
+	if err != nil {
+		// an id which can't be routed never exists
+		return nil, domain.ErrRecordNotFound
+	}
+	return shard.Get(ctx, id)
+}
+
+// List fans out to all the shards, and merges the records from the newest one
+func (repo *ShardedShortUrlRepository) List(ctx context.Context, listReqDto *domain.ListReqDto) ([]*domain.ShortUrlDto, error) {
+	results := make([][]*domain.ShortUrlDto, len(repo.shards))
+	g, ctx := errgroup.WithContext(ctx)
+	for i, shard := range repo.shards {
+		g.Go(func() error {
+			objs, err := shard.List(ctx, listReqDto)
+			if err != nil {
+				return fmt.Errorf("shard(%d): %w", i, err)
+			}
+			results[i] = objs
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	objs := []*domain.ShortUrlDto{}
+	for _, result := range results {
+		objs = append(objs, result...)
+	}
+	sort.SliceStable(objs, func(i, j int) bool {
+		return objs[i].CreatedAt.After(objs[j].CreatedAt)
+	})
+	if listReqDto.Limit > 0 && len(objs) > listReqDto.Limit {
+		objs = objs[:listReqDto.Limit]
+	}
+	return objs, nil
+}
+
+// ListByStatus fans out to all the shards, and merges the records from the smallest id,
+// so that the pages of listReqDto.AfterID go through all the shards
+func (repo *ShardedShortUrlRepository) ListByStatus(ctx context.Context, listReqDto *domain.ListByStatusReqDto) ([]*domain.ShortUrlDto, error) {
+	results := make([][]*domain.ShortUrlDto, len(repo.shards))
+	g, ctx := errgroup.WithContext(ctx)
+	for i, shard := range repo.shards {
+		g.Go(func() error {
+			objs, err := shard.ListByStatus(ctx, listReqDto)
+			if err != nil {
+				return fmt.Errorf("shard(%d): %w", i, err)
+			}
+			results[i] = objs
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	objs := []*domain.ShortUrlDto{}
+	for _, result := range results {
+		objs = append(objs, result...)
+	}
+	sort.SliceStable(objs, func(i, j int) bool {
+		return objs[i].TargetID < objs[j].TargetID
+	})
+	if listReqDto.Limit > 0 && len(objs) > listReqDto.Limit {
+		objs = objs[:listReqDto.Limit]
+	}
+	return objs, nil
+}
+
+// Find gets the whole record from the shard of the id
+func (repo *ShardedShortUrlRepository) Find(ctx context.Context, id string) (*domain.ShortUrlDto, error) {
+	shard, err := repo.shard(id)
+	if err != nil {
+		return nil, domain.ErrRecordNotFound
+	}
+	return shard.Find(ctx, id)
+}
+
+// Update updates the record in the shard of the id
+func (repo *ShardedShortUrlRepository) Update(ctx context.Context, id string, updateReqDto *domain.UpdateReqDto) error {
+	shard, err := repo.shard(id)
+	if err != nil {
+		return domain.ErrRecordNotFound
+	}
+	return shard.Update(ctx, id, updateReqDto)
+}
+
+// CreateClicks creates the click records in the shards of their target ids, so that they live with their short urls
+func (repo *ShardedShortUrlRepository) CreateClicks(ctx context.Context, clickDtos []*domain.ClickDto) error {
+	batches := make([][]*domain.ClickDto, len(repo.shards))
+	for _, clickDto := range clickDtos {
+		i, err := repo.shardIndex(clickDto.TargetID)
+		if err != nil {
+			// the click of an id which can't be routed is never redirected
+			continue
+		}
+		batches[i] = append(batches[i], clickDto)
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	for i, batch := range batches {
+		if len(batch) == 0 {
+			continue
+		}
+		g.Go(func() error {
+			if err := repo.shards[i].CreateClicks(ctx, batch); err != nil {
+				return fmt.Errorf("shard(%d): %w", i, err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+// ListClicks lists the clicks from the shard of the id
+func (repo *ShardedShortUrlRepository) ListClicks(ctx context.Context, id string, limit int) ([]*domain.ClickDto, error) {
+	shard, err := repo.shard(id)
+	if err != nil {
+		return []*domain.ClickDto{}, nil
+	}
+	return shard.ListClicks(ctx, id, limit)
+}
+
+// CountClicksByVariant counts the clicks in the shard of the id
+func (repo *ShardedShortUrlRepository) CountClicksByVariant(ctx context.Context, id string) (map[int]int64, error) {
+	shard, err := repo.shard(id)
+	if err != nil {
+		return map[int]int64{}, nil
+	}
+	return shard.CountClicksByVariant(ctx, id)
+}
+
+// CreateReport creates the report in the shard of its target id
+func (repo *ShardedShortUrlRepository) CreateReport(ctx context.Context, reportDto *domain.ReportDto) error {
+	shard, err := repo.shard(reportDto.TargetID)
+	if err != nil {
+		return domain.ErrRecordNotFound
+	}
+	return shard.CreateReport(ctx, reportDto)
+}
+
+// ListReports fans out to all the shards, and merges the reports from the oldest one.
+// The ids of the reports are unique within their shards only.
+func (repo *ShardedShortUrlRepository) ListReports(ctx context.Context, listReqDto *domain.ListReportsReqDto) ([]*domain.ReportDto, error) {
+	results := make([][]*domain.ReportDto, len(repo.shards))
+	g, ctx := errgroup.WithContext(ctx)
+	for i, shard := range repo.shards {
+		g.Go(func() error {
+			objs, err := shard.ListReports(ctx, listReqDto)
+			if err != nil {
+				return fmt.Errorf("shard(%d): %w", i, err)
+			}
+			results[i] = objs
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	objs := []*domain.ReportDto{}
+	for _, result := range results {
+		objs = append(objs, result...)
+	}
+	sort.SliceStable(objs, func(i, j int) bool {
+		return objs[i].CreatedAt.Before(objs[j].CreatedAt)
+	})
+	if listReqDto.Limit > 0 && len(objs) > listReqDto.Limit {
+		objs = objs[:listReqDto.Limit]
+	}
+	return objs, nil
+}
+
+// ResolveReports resolves the reports in the shard of the id
+func (repo *ShardedShortUrlRepository) ResolveReports(ctx context.Context, id string, status domain.ReportStatus) (int, error) {
+	shard, err := repo.shard(id)
+	if err != nil {
+		return 0, nil
+	}
+	return shard.ResolveReports(ctx, id, status)
+}
+
+// BanCreator bans the creator in the first shard, which keeps the tables not sharded by the target id
+func (repo *ShardedShortUrlRepository) BanCreator(ctx context.Context, creator string, reason string) error {
+	return repo.shards[0].BanCreator(ctx, creator, reason)
+}
+
+// IsCreatorBanned looks up the banned creators in the first shard
+func (repo *ShardedShortUrlRepository) IsCreatorBanned(ctx context.Context, creator string) (bool, error) {
+	return repo.shards[0].IsCreatorBanned(ctx, creator)
+}
+
+// CreateParamTemplate creates the param template in the first shard, like the other tables not sharded by the target id
+func (repo *ShardedShortUrlRepository) CreateParamTemplate(ctx context.Context, templateDto *domain.ParamTemplateDto) error {
+	return repo.shards[0].CreateParamTemplate(ctx, templateDto)
+}
+
+// GetParamTemplate gets the param template from the first shard
+func (repo *ShardedShortUrlRepository) GetParamTemplate(ctx context.Context, name string) (*domain.ParamTemplateDto, error) {
+	return repo.shards[0].GetParamTemplate(ctx, name)
+}
+
+// ListParamTemplates lists the param templates in the first shard
+func (repo *ShardedShortUrlRepository) ListParamTemplates(ctx context.Context) ([]*domain.ParamTemplateDto, error) {
+	return repo.shards[0].ListParamTemplates(ctx)
+}
+
+// UpdateParamTemplate updates the param template in the first shard
+func (repo *ShardedShortUrlRepository) UpdateParamTemplate(ctx context.Context, templateDto *domain.ParamTemplateDto) error {
+	return repo.shards[0].UpdateParamTemplate(ctx, templateDto)
+}
+
+// DeleteParamTemplate deletes the param template in the first shard
+func (repo *ShardedShortUrlRepository) DeleteParamTemplate(ctx context.Context, name string) error {
+	return repo.shards[0].DeleteParamTemplate(ctx, name)
+}
+
+func (repo *ShardedShortUrlRepository) shard(id string) (usecase.Repository, error) {
+	i, err := repo.shardIndex(id)
+	if err != nil {
+		return nil, err
+	}
+	return repo.shards[i], nil
+}
+
+func (repo *ShardedShortUrlRepository) shardIndex(id string) (int, error) {
+	if repo.strategy == ShardStrategyHash {
+		return shardkit.Hash(id, len(repo.shards)), nil
+	}
+
+	i, err := shardkit.Prefix(id)
+	if err != nil {
+		return 0, err
+	}
+	if i >= len(repo.shards) {
+		return 0, fmt.Errorf("%w: shard(%d) out of range", shardkit.ErrInvalidID, i)
+	}
+	return i, nil
+}
@@ -0,0 +1,393 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	uc "github.com/Hao1995/short-url/internal/usecase"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+	"github.com/Hao1995/short-url/pkg/shardkit"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ShardedShortUrlTestSuite struct {
+	suite.Suite
+
+	now time.Time
+
+	shards []*usecase.Repository
+}
+
+func TestShardedShortUrlTestSuite(t *testing.T) {
+	suite.Run(t, new(ShardedShortUrlTestSuite))
+}
+
+func (s *ShardedShortUrlTestSuite) SetupSuite() {
+	s.now = time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC)
+}
+
+func (s *ShardedShortUrlTestSuite) SetupSubTest() {
+	s.shards = []*usecase.Repository{
+		usecase.NewRepository(s.T()),
+		usecase.NewRepository(s.T()),
+		usecase.NewRepository(s.T()),
+	}
+}
+
+func (s *ShardedShortUrlTestSuite) newImpl(strategy ShardStrategy) uc.Repository {
+	shards := make([]uc.Repository, len(s.shards))
+	for i, shard := range s.shards {
+		shards[i] = shard
+	}
+	impl, err := NewShardedShortUrlRepository(shards, strategy)
+	s.Require().NoError(err)
+	return impl
+}
+
+func (s *ShardedShortUrlTestSuite) TestCreate() {
+	for _, t := range []struct {
+		name     string
+		strategy ShardStrategy
+		req      *domain.CreateReqDto
+		setup    func(req *domain.CreateReqDto)
+		expID    string
+		expErr   error
+	}{
+		{
+			name:     "create record in the shard by hash",
+			strategy: ShardStrategyHash,
+			req:      &domain.CreateReqDto{Url: "https://example.com/whatever1", TargetID: "testid1"},
+			setup: func(req *domain.CreateReqDto) {
+				s.shards[shardkit.Hash("testid1", 3)].On("Create", mock.Anything, req).Once().Return("testid1", nil)
+			},
+			expID: "testid1",
+		},
+		{
+			name:     "create record in the shard embedded in the id",
+			strategy: ShardStrategyPrefix,
+			req:      &domain.CreateReqDto{Url: "https://example.com/whatever1", TargetID: uc.NewShardedIDGenerator(3)("https://example.com/whatever1")},
+			setup: func(req *domain.CreateReqDto) {
+				shard, err := shardkit.Prefix(req.TargetID)
+				s.Require().NoError(err)
+				s.shards[shard].On("Create", mock.Anything, req).Once().Return(req.TargetID, nil)
+			},
+			expID: uc.NewShardedIDGenerator(3)("https://example.com/whatever1"),
+		},
+		{
+			name:     "failed to create record due to the shard out of range",
+			strategy: ShardStrategyPrefix,
+			req:      &domain.CreateReqDto{Url: "https://example.com/whatever1", TargetID: "ff000001"},
+			expID:    "",
+			expErr:   shardkit.ErrInvalidID,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup(t.req)
+			}
+			id, err := s.newImpl(t.strategy).Create(context.Background(), t.req)
+			s.ErrorIs(err, t.expErr)
+			s.Equal(t.expID, id)
+		})
+	}
+}
+
+func (s *ShardedShortUrlTestSuite) TestGet() {
+	for _, t := range []struct {
+		name     string
+		strategy ShardStrategy
+		req      string
+		setup    func()
+		exp      *domain.GetRespDto
+		expErr   error
+	}{
+		{
+			name:     "get record from the shard by hash",
+			strategy: ShardStrategyHash,
+			req:      "testid1",
+			setup: func() {
+				s.shards[shardkit.Hash("testid1", 3)].On("Get", mock.Anything, "testid1").Once().Return(&domain.GetRespDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+				}, nil)
+			},
+			exp: &domain.GetRespDto{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+			},
+		},
+		{
+			name:     "get record from the shard embedded in the id",
+			strategy: ShardStrategyPrefix,
+			req:      "02000001",
+			setup: func() {
+				s.shards[2].On("Get", mock.Anything, "02000001").Once().Return(&domain.GetRespDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+				}, nil)
+			},
+			exp: &domain.GetRespDto{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+			},
+		},
+		{
+			name:     "record not found when the id can't be routed",
+			strategy: ShardStrategyPrefix,
+			req:      "zz000001",
+			exp:      nil,
+			expErr:   domain.ErrRecordNotFound,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			obj, err := s.newImpl(t.strategy).Get(context.Background(), t.req)
+			s.ErrorIs(err, t.expErr)
+			s.Equal(t.exp, obj)
+		})
+	}
+}
+
+func (s *ShardedShortUrlTestSuite) TestList() {
+	for _, t := range []struct {
+		name   string
+		req    *domain.ListReqDto
+		setup  func(req *domain.ListReqDto)
+		exp    []*domain.ShortUrlDto
+		expErr error
+	}{
+		{
+			name: "merge records from all shards from the newest one",
+			req:  &domain.ListReqDto{Url: "https://example.com/whatever1", Limit: 2},
+			setup: func(req *domain.ListReqDto) {
+				s.shards[0].On("List", mock.Anything, req).Once().Return([]*domain.ShortUrlDto{
+					{TargetID: "testid1", CreatedAt: s.now.Add(-1 * time.Hour)},
+				}, nil)
+				s.shards[1].On("List", mock.Anything, req).Once().Return([]*domain.ShortUrlDto{
+					{TargetID: "testid2", CreatedAt: s.now},
+					{TargetID: "testid3", CreatedAt: s.now.Add(-2 * time.Hour)},
+				}, nil)
+				s.shards[2].On("List", mock.Anything, req).Once().Return([]*domain.ShortUrlDto{}, nil)
+			},
+			exp: []*domain.ShortUrlDto{
+				{TargetID: "testid2", CreatedAt: s.now},
+				{TargetID: "testid1", CreatedAt: s.now.Add(-1 * time.Hour)},
+			},
+		},
+		{
+			name: "failed to list records due to one of the shards",
+			req:  &domain.ListReqDto{Url: "https://example.com/whatever1"},
+			setup: func(req *domain.ListReqDto) {
+				s.shards[0].On("List", mock.Anything, req).Maybe().Return([]*domain.ShortUrlDto{}, nil)
+				s.shards[1].On("List", mock.Anything, req).Once().Return(nil, errors.New("unknown error"))
+				s.shards[2].On("List", mock.Anything, req).Maybe().Return([]*domain.ShortUrlDto{}, nil)
+			},
+			exp:    nil,
+			expErr: errors.New("shard(1): unknown error"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup(t.req)
+			}
+			objs, err := s.newImpl(ShardStrategyHash).List(context.Background(), t.req)
+			if t.expErr != nil {
+				s.EqualError(err, t.expErr.Error())
+			} else {
+				s.NoError(err)
+			}
+			s.Equal(t.exp, objs)
+		})
+	}
+}
+
+func (s *ShardedShortUrlTestSuite) TestListByStatus() {
+	req := &domain.ListByStatusReqDto{Statuses: []domain.LinkStatus{domain.LinkStatusActive}, AfterID: "testid1", Limit: 2}
+	s.Suite.Run("merge records from all shards from the smallest id", func() {
+		s.shards[0].On("ListByStatus", mock.Anything, req).Once().Return([]*domain.ShortUrlDto{
+			{TargetID: "testid3"},
+		}, nil)
+		s.shards[1].On("ListByStatus", mock.Anything, req).Once().Return([]*domain.ShortUrlDto{
+			{TargetID: "testid2"},
+			{TargetID: "testid5"},
+		}, nil)
+		s.shards[2].On("ListByStatus", mock.Anything, req).Once().Return([]*domain.ShortUrlDto{
+			{TargetID: "testid4"},
+		}, nil)
+
+		objs, err := s.newImpl(ShardStrategyHash).ListByStatus(context.Background(), req)
+		s.NoError(err)
+		s.Equal([]*domain.ShortUrlDto{{TargetID: "testid2"}, {TargetID: "testid3"}}, objs)
+	})
+}
+
+func (s *ShardedShortUrlTestSuite) TestListReports() {
+	req := &domain.ListReportsReqDto{Status: domain.ReportStatusPending, Limit: 2}
+	s.Suite.Run("merge reports from all shards from the oldest one", func() {
+		s.shards[0].On("ListReports", mock.Anything, req).Once().Return([]*domain.ReportDto{
+			{TargetID: "testid3", CreatedAt: s.now.Add(2 * time.Second)},
+		}, nil)
+		s.shards[1].On("ListReports", mock.Anything, req).Once().Return([]*domain.ReportDto{
+			{TargetID: "testid1", CreatedAt: s.now},
+			{TargetID: "testid4", CreatedAt: s.now.Add(3 * time.Second)},
+		}, nil)
+		s.shards[2].On("ListReports", mock.Anything, req).Once().Return([]*domain.ReportDto{
+			{TargetID: "testid2", CreatedAt: s.now.Add(time.Second)},
+		}, nil)
+
+		objs, err := s.newImpl(ShardStrategyHash).ListReports(context.Background(), req)
+		s.NoError(err)
+		s.Equal([]*domain.ReportDto{
+			{TargetID: "testid1", CreatedAt: s.now},
+			{TargetID: "testid2", CreatedAt: s.now.Add(time.Second)},
+		}, objs)
+	})
+}
+
+func (s *ShardedShortUrlTestSuite) TestReports() {
+	s.Suite.Run("create and resolve the reports in the shard of the target id", func() {
+		s.shards[2].On("CreateReport", mock.Anything, &domain.ReportDto{TargetID: "02000001"}).Once().Return(nil)
+		s.shards[2].On("ResolveReports", mock.Anything, "02000001", domain.ReportStatusDismissed).Once().Return(1, nil)
+
+		impl := s.newImpl(ShardStrategyPrefix)
+		s.NoError(impl.CreateReport(context.Background(), &domain.ReportDto{TargetID: "02000001"}))
+		count, err := impl.ResolveReports(context.Background(), "02000001", domain.ReportStatusDismissed)
+		s.NoError(err)
+		s.Equal(1, count)
+	})
+	s.Suite.Run("report the id which can't be routed", func() {
+		err := s.newImpl(ShardStrategyPrefix).CreateReport(context.Background(), &domain.ReportDto{TargetID: "zz000001"})
+		s.Equal(domain.ErrRecordNotFound, err)
+	})
+	s.Suite.Run("ban the creators in the first shard", func() {
+		s.shards[0].On("BanCreator", mock.Anything, "whatever-creator", "whatever").Once().Return(nil)
+		s.shards[0].On("IsCreatorBanned", mock.Anything, "whatever-creator").Once().Return(true, nil)
+
+		impl := s.newImpl(ShardStrategyHash)
+		s.NoError(impl.BanCreator(context.Background(), "whatever-creator", "whatever"))
+		banned, err := impl.IsCreatorBanned(context.Background(), "whatever-creator")
+		s.NoError(err)
+		s.True(banned)
+	})
+}
+
+func (s *ShardedShortUrlTestSuite) TestParamTemplates() {
+	s.Suite.Run("manage the param templates in the first shard", func() {
+		obj := &domain.ParamTemplateDto{Name: "newsletter", Query: "utm_source=newsletter"}
+		s.shards[0].On("CreateParamTemplate", mock.Anything, obj).Once().Return(nil)
+		s.shards[0].On("GetParamTemplate", mock.Anything, "newsletter").Once().Return(obj, nil)
+		s.shards[0].On("ListParamTemplates", mock.Anything).Once().Return([]*domain.ParamTemplateDto{obj}, nil)
+		s.shards[0].On("UpdateParamTemplate", mock.Anything, obj).Once().Return(nil)
+		s.shards[0].On("DeleteParamTemplate", mock.Anything, "newsletter").Once().Return(nil)
+
+		impl := s.newImpl(ShardStrategyHash)
+		s.NoError(impl.CreateParamTemplate(context.Background(), obj))
+		got, err := impl.GetParamTemplate(context.Background(), "newsletter")
+		s.NoError(err)
+		s.Equal(obj, got)
+		objs, err := impl.ListParamTemplates(context.Background())
+		s.NoError(err)
+		s.Equal([]*domain.ParamTemplateDto{obj}, objs)
+		s.NoError(impl.UpdateParamTemplate(context.Background(), obj))
+		s.NoError(impl.DeleteParamTemplate(context.Background(), "newsletter"))
+	})
+}
+
+func (s *ShardedShortUrlTestSuite) TestCreateClicks() {
+	for _, t := range []struct {
+		name   string
+		req    []*domain.ClickDto
+		setup  func()
+		expErr error
+	}{
+		{
+			name: "create clicks in the shards of their target ids",
+			req: []*domain.ClickDto{
+				{TargetID: "00000001", ClickedAt: s.now},
+				{TargetID: "02000001", ClickedAt: s.now},
+				{TargetID: "00000002", ClickedAt: s.now},
+			},
+			setup: func() {
+				s.shards[0].On("CreateClicks", mock.Anything, []*domain.ClickDto{
+					{TargetID: "00000001", ClickedAt: s.now},
+					{TargetID: "00000002", ClickedAt: s.now},
+				}).Once().Return(nil)
+				s.shards[2].On("CreateClicks", mock.Anything, []*domain.ClickDto{
+					{TargetID: "02000001", ClickedAt: s.now},
+				}).Once().Return(nil)
+			},
+		},
+		{
+			name: "skip the clicks of the ids which can't be routed",
+			req: []*domain.ClickDto{
+				{TargetID: "zz000001", ClickedAt: s.now},
+				{TargetID: "01000001", ClickedAt: s.now},
+			},
+			setup: func() {
+				s.shards[1].On("CreateClicks", mock.Anything, []*domain.ClickDto{
+					{TargetID: "01000001", ClickedAt: s.now},
+				}).Once().Return(nil)
+			},
+		},
+		{
+			name: "failed to create clicks due to one of the shards",
+			req: []*domain.ClickDto{
+				{TargetID: "01000001", ClickedAt: s.now},
+			},
+			setup: func() {
+				s.shards[1].On("CreateClicks", mock.Anything, mock.Anything).Once().Return(errors.New("unknown error"))
+			},
+			expErr: errors.New("shard(1): unknown error"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			err := s.newImpl(ShardStrategyPrefix).CreateClicks(context.Background(), t.req)
+			if t.expErr != nil {
+				s.EqualError(err, t.expErr.Error())
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
+
+func (s *ShardedShortUrlTestSuite) TestUpdate() {
+	status := domain.LinkStatusDisabled
+	for _, t := range []struct {
+		name   string
+		req    string
+		setup  func()
+		expErr error
+	}{
+		{
+			name: "update record in the shard embedded in the id",
+			req:  "02000001",
+			setup: func() {
+				s.shards[2].On("Update", mock.Anything, "02000001", &domain.UpdateReqDto{Status: &status}).Once().Return(nil)
+			},
+		},
+		{
+			name:   "record not found when the id can't be routed",
+			req:    "zz000001",
+			expErr: domain.ErrRecordNotFound,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			err := s.newImpl(ShardStrategyPrefix).Update(context.Background(), t.req, &domain.UpdateReqDto{Status: &status})
+			s.ErrorIs(err, t.expErr)
+		})
+	}
+}
@@ -3,67 +3,807 @@ package mysql
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"time"
+	"unicode/utf8"
 
 	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/metrics"
 	"github.com/Hao1995/short-url/internal/usecase"
+	"github.com/Hao1995/short-url/pkg/logkit"
+	"github.com/Hao1995/short-url/pkg/tracekit"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
 	now = func() time.Time {
 		return time.Now().UTC()
 	}
+
+	tracer = otel.Tracer("github.com/Hao1995/short-url/internal/adapter/repository/mysql")
+)
+
+// the attributes of the spans
+const (
+	attrID      = attribute.Key("short_url.id")
+	attrReplica = attribute.Key("db.replica")
 )
 
+// Config contains the per-operation timeouts of the repository. A zero timeout means no deadline
+// other than the one carried by the caller's context.
+type Config struct {
+	CreateTimeout time.Duration
+	GetTimeout    time.Duration
+	ListTimeout   time.Duration
+
+	// ReadYourWritesWindow is how long the ids created by this instance are read from the primary
+	ReadYourWritesWindow time.Duration
+	// MissConfirmRate is how many misses of the replicas are confirmed on the primary per second, 0 disables it.
+	// The ids created by the other instances are missing on the lagging replicas, but so are the ones never created.
+	MissConfirmRate int
+}
+
 type ShortUrlRepository struct {
-	db *gorm.DB
+	cluster *Cluster
+	cfg     Config
+	recent  *recentWrites
+	confirm *missConfirmer
 }
 
 // NewShortUrlRepository generates the MySQL implementation of the ShortUrl repository interface
-func NewShortUrlRepository(db *gorm.DB) usecase.Repository {
+func NewShortUrlRepository(cluster *Cluster, cfg Config) usecase.Repository {
 	return &ShortUrlRepository{
-		db: db,
+		cluster: cluster,
+		cfg:     cfg,
+		recent:  newRecentWrites(cfg.ReadYourWritesWindow),
+		confirm: newMissConfirmer(cfg.MissConfirmRate),
 	}
 }
 
 // Create creates short_url record and return short url id
-func (repo *ShortUrlRepository) Create(ctx context.Context, CreateReqDto *domain.CreateReqDto) (string, error) {
+func (repo *ShortUrlRepository) Create(ctx context.Context, CreateReqDto *domain.CreateReqDto) (_ string, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.Create", attrID.String(CreateReqDto.TargetID))
+	defer func() {
+		// the duplicated key is resolved by the caller
+		if err != domain.ErrDuplicatedKey {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.CreateTimeout)
+	defer cancel()
+
+	status := CreateReqDto.Status
+	if status == "" {
+		status = domain.LinkStatusActive
+	}
 	record := ShortUrl{
-		Url:       CreateReqDto.Url,
-		TargetID:  CreateReqDto.TargetID,
-		ExpireAt:  CreateReqDto.ExpireAt,
-		CreatedAt: now(),
+		Url:          CreateReqDto.Url,
+		TargetID:     CreateReqDto.TargetID,
+		Status:       status.String(),
+		Creator:      CreateReqDto.Creator,
+		Interstitial: CreateReqDto.Interstitial.String(),
+		RedirectCode: CreateReqDto.RedirectCode,
+		Passthrough:  CreateReqDto.Passthrough.String(),
+		Template:     CreateReqDto.Template,
+		Rules:        toRuleRecords(CreateReqDto.Rules),
+		GeoRules:     toGeoRuleRecords(CreateReqDto.GeoRules),
+		Variants:     toVariantRecords(CreateReqDto.Variants),
+		ExpireAt:     CreateReqDto.ExpireAt,
+		CreatedAt:    now(),
 	}
 
-	if result := repo.db.Create(&record); result.Error != nil {
+	if result := repo.cluster.Primary().WithContext(ctx).Create(&record); result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
 			return "", domain.ErrDuplicatedKey
 		}
-		log.Printf("failed to create short_url: %s", result.Error)
-		return "", result.Error
+		slog.ErrorContext(ctx, "failed to create short_url", "id", CreateReqDto.TargetID, logkit.Err(result.Error))
+		return "", translateError(ctx, result.Error)
 	}
+	repo.recent.add(CreateReqDto.TargetID)
 
 	return CreateReqDto.TargetID, nil
 }
 
 // Get gets short url record by id
-func (repo *ShortUrlRepository) Get(ctx context.Context, id string) (*domain.GetRespDto, error) {
+func (repo *ShortUrlRepository) Get(ctx context.Context, id string) (_ *domain.GetRespDto, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.Get", attrID.String(id))
+	defer func() {
+		if err != domain.ErrRecordNotFound {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.GetTimeout)
+	defer cancel()
+
+	db, isReplica := repo.cluster.Primary(), false
+	if !repo.recent.contains(id) {
+		db, isReplica = repo.cluster.Replica()
+	}
+	span.SetAttributes(attrReplica.Bool(isReplica))
+
 	var record ShortUrl
-	result := repo.db.Where("target_id = ?", id).Select([]string{"url", "expire_at"}).First(&record)
+	result := first(ctx, db, id, &record)
+	if isReplica && result.Error != nil && !errors.Is(result.Error, gorm.ErrRecordNotFound) && ctx.Err() == nil {
+		// fail over to the primary, the replica is back after the next successful health check
+		slog.WarnContext(ctx, "failed to get short_url from the replica", "id", id, logkit.Err(result.Error))
+		repo.cluster.MarkUnhealthy(db)
+		span.AddEvent("fail over to the primary")
+		result = first(ctx, repo.cluster.Primary(), id, &record)
+	} else if isReplica && errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		// the links created by the other instances aren't in the recent writes, and the replica might not have caught up yet.
+		// Confirm the miss on the primary, otherwise the new link is cached as not found for all the instances.
+		// The confirmations are bounded, since the ids never created are missing as well, e.g. the probes.
+		if !repo.confirm.allow() {
+			metrics.MissConfirmations.WithLabelValues("skipped").Inc()
+		} else {
+			span.AddEvent("confirm the miss on the primary")
+			result = first(ctx, repo.cluster.Primary(), id, &record)
+			switch {
+			case result.Error == nil:
+				metrics.MissConfirmations.WithLabelValues("found").Inc()
+			case errors.Is(result.Error, gorm.ErrRecordNotFound):
+				metrics.MissConfirmations.WithLabelValues("not_found").Inc()
+			default:
+				metrics.MissConfirmations.WithLabelValues("error").Inc()
+			}
+		}
+	}
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, domain.ErrRecordNotFound
 		}
 
-		log.Printf("failed to get short_url by id(%s): %s", id, result.Error)
-		return nil, result.Error
+		slog.ErrorContext(ctx, "failed to get short_url", "id", id, logkit.Err(result.Error))
+		return nil, translateError(ctx, result.Error)
 	}
-	log.Printf("get url `%s` by id `%s`", record.Url, id)
+	logkit.Sampled().DebugContext(ctx, "get short_url", "id", id, "replica", isReplica)
 
 	return &domain.GetRespDto{
-		Url:      record.Url,
-		ExpireAt: record.ExpireAt,
+		Url:          record.Url,
+		ExpireAt:     record.ExpireAt,
+		LinkStatus:   domain.LinkStatus(record.Status),
+		Interstitial: domain.Interstitial(record.Interstitial),
+		RedirectCode: record.RedirectCode,
+		Passthrough:  domain.Passthrough(record.Passthrough),
+		Template:     record.Template,
+		Rules:        toRuleDtos(record.Rules),
+		GeoRules:     toGeoRuleDtos(record.GeoRules),
+		Variants:     toVariantDtos(record.Variants),
+		CreatedAt:    record.CreatedAt,
 	}, nil
 }
+
+// List lists short url records from the newest one
+func (repo *ShortUrlRepository) List(ctx context.Context, listReqDto *domain.ListReqDto) (_ []*domain.ShortUrlDto, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.List")
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.ListTimeout)
+	defer cancel()
+
+	db, _ := repo.cluster.Replica()
+	query := db.WithContext(ctx).Order("created_at DESC")
+	if listReqDto.Url != "" {
+		query = query.Where("url = ?", listReqDto.Url)
+	}
+	if listReqDto.Limit > 0 {
+		query = query.Limit(listReqDto.Limit)
+	}
+
+	var records []ShortUrl
+	if result := query.Find(&records); result.Error != nil {
+		slog.ErrorContext(ctx, "failed to list short_urls", logkit.Err(result.Error))
+		return nil, translateError(ctx, result.Error)
+	}
+
+	objs := make([]*domain.ShortUrlDto, len(records))
+	for i, record := range records {
+		objs[i] = toShortUrlDto(&record)
+	}
+	return objs, nil
+}
+
+// ListByStatus lists the unexpired short url records of the statuses from the smallest id after listReqDto.AfterID
+func (repo *ShortUrlRepository) ListByStatus(ctx context.Context, listReqDto *domain.ListByStatusReqDto) (_ []*domain.ShortUrlDto, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.ListByStatus")
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.ListTimeout)
+	defer cancel()
+
+	statuses := make([]string, len(listReqDto.Statuses))
+	for i, status := range listReqDto.Statuses {
+		statuses[i] = status.String()
+	}
+
+	// the primary decides the statuses, and the replica might bring back the links just disabled
+	query := repo.cluster.Primary().WithContext(ctx).
+		Where("target_id > ? AND status IN ? AND expire_at > ?", listReqDto.AfterID, statuses, now()).
+		Order("target_id")
+	if listReqDto.Limit > 0 {
+		query = query.Limit(listReqDto.Limit)
+	}
+
+	var records []ShortUrl
+	if result := query.Find(&records); result.Error != nil {
+		slog.ErrorContext(ctx, "failed to list short_urls by status", logkit.Err(result.Error))
+		return nil, translateError(ctx, result.Error)
+	}
+
+	objs := make([]*domain.ShortUrlDto, len(records))
+	for i, record := range records {
+		objs[i] = toShortUrlDto(&record)
+	}
+	return objs, nil
+}
+
+// Find gets the whole record by id from the primary
+func (repo *ShortUrlRepository) Find(ctx context.Context, id string) (_ *domain.ShortUrlDto, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.Find", attrID.String(id))
+	defer func() {
+		if err != domain.ErrRecordNotFound {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.GetTimeout)
+	defer cancel()
+
+	var record ShortUrl
+	if result := repo.cluster.Primary().WithContext(ctx).Where("target_id = ?", id).First(&record); result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrRecordNotFound
+		}
+		slog.ErrorContext(ctx, "failed to find short_url", "id", id, logkit.Err(result.Error))
+		return nil, translateError(ctx, result.Error)
+	}
+	return toShortUrlDto(&record), nil
+}
+
+// Update updates the fields set in updateReqDto of the record
+func (repo *ShortUrlRepository) Update(ctx context.Context, id string, updateReqDto *domain.UpdateReqDto) (err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.Update", attrID.String(id))
+	defer func() {
+		if err != domain.ErrRecordNotFound {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.CreateTimeout)
+	defer cancel()
+
+	fields := map[string]interface{}{}
+	if updateReqDto.Status != nil {
+		fields["status"] = updateReqDto.Status.String()
+	}
+	if updateReqDto.ExpireAt != nil {
+		fields["expire_at"] = updateReqDto.ExpireAt.UTC()
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	db := repo.cluster.Primary().WithContext(ctx)
+	result := db.Model(&ShortUrl{}).Where("target_id = ?", id).Updates(fields)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to update short_url", "id", id, logkit.Err(result.Error))
+		return translateError(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		// MySQL doesn't count the rows matched but unchanged, tell them from the missing one
+		var count int64
+		if err := db.Model(&ShortUrl{}).Where("target_id = ?", id).Count(&count).Error; err != nil {
+			return translateError(ctx, err)
+		} else if count == 0 {
+			return domain.ErrRecordNotFound
+		}
+	}
+	repo.recent.add(id)
+
+	slog.InfoContext(ctx, "update short_url", "id", id, "fields", fields)
+	return nil
+}
+
+// CreateClicks creates the click records in a batch
+func (repo *ShortUrlRepository) CreateClicks(ctx context.Context, clickDtos []*domain.ClickDto) (err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.CreateClicks")
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	if len(clickDtos) == 0 {
+		return nil
+	}
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.CreateTimeout)
+	defer cancel()
+
+	records := make([]Click, len(clickDtos))
+	for i, clickDto := range clickDtos {
+		records[i] = Click{
+			TargetID:  clickDto.TargetID,
+			ClickID:   clickDto.ClickID,
+			Rule:      clickDto.Rule,
+			Variant:   clickDto.Variant,
+			Referer:   truncate(clickDto.Referer, maxRefererLen),
+			UserAgent: truncate(clickDto.UserAgent, maxUserAgentLen),
+			ClickedAt: clickDto.ClickedAt.UTC(),
+		}
+	}
+	if result := repo.cluster.Primary().WithContext(ctx).Create(&records); result.Error != nil {
+		slog.ErrorContext(ctx, "failed to create clicks", "count", len(records), logkit.Err(result.Error))
+		return translateError(ctx, result.Error)
+	}
+	return nil
+}
+
+// ListClicks lists the clicks of the id from the newest one
+func (repo *ShortUrlRepository) ListClicks(ctx context.Context, id string, limit int) (_ []*domain.ClickDto, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.ListClicks", attrID.String(id))
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.ListTimeout)
+	defer cancel()
+
+	db, _ := repo.cluster.Replica()
+	query := db.WithContext(ctx).Where("target_id = ?", id).Order("clicked_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	var records []Click
+	if result := query.Find(&records); result.Error != nil {
+		slog.ErrorContext(ctx, "failed to list clicks", "id", id, logkit.Err(result.Error))
+		return nil, translateError(ctx, result.Error)
+	}
+
+	objs := make([]*domain.ClickDto, len(records))
+	for i, record := range records {
+		objs[i] = &domain.ClickDto{
+			TargetID:  record.TargetID,
+			ClickID:   record.ClickID,
+			Rule:      record.Rule,
+			Variant:   record.Variant,
+			Referer:   record.Referer,
+			UserAgent: record.UserAgent,
+			ClickedAt: record.ClickedAt,
+		}
+	}
+	return objs, nil
+}
+
+// CountClicksByVariant counts the clicks of the id by the variant
+func (repo *ShortUrlRepository) CountClicksByVariant(ctx context.Context, id string) (_ map[int]int64, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.CountClicksByVariant", attrID.String(id))
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.ListTimeout)
+	defer cancel()
+
+	var rows []struct {
+		Variant int
+		Clicks  int64
+	}
+	db, _ := repo.cluster.Replica()
+	result := db.WithContext(ctx).Model(&Click{}).Select("variant, COUNT(*) AS clicks").
+		Where("target_id = ?", id).Group("variant").Scan(&rows)
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to count clicks by variant", "id", id, logkit.Err(result.Error))
+		return nil, translateError(ctx, result.Error)
+	}
+
+	counts := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Variant] = row.Clicks
+	}
+	return counts, nil
+}
+
+// CreateReport creates the report record
+func (repo *ShortUrlRepository) CreateReport(ctx context.Context, reportDto *domain.ReportDto) (err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.CreateReport", attrID.String(reportDto.TargetID))
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.CreateTimeout)
+	defer cancel()
+
+	record := Report{
+		TargetID:  reportDto.TargetID,
+		Reason:    truncate(reportDto.Reason, maxReasonLen),
+		Contact:   truncate(reportDto.Contact, maxContactLen),
+		Status:    domain.ReportStatusPending.String(),
+		CreatedAt: now(),
+	}
+	if result := repo.cluster.Primary().WithContext(ctx).Create(&record); result.Error != nil {
+		slog.ErrorContext(ctx, "failed to create report", "id", reportDto.TargetID, logkit.Err(result.Error))
+		return translateError(ctx, result.Error)
+	}
+	reportDto.ID = record.ID
+	return nil
+}
+
+// ListReports lists the reports of the status from the oldest one
+func (repo *ShortUrlRepository) ListReports(ctx context.Context, listReqDto *domain.ListReportsReqDto) (_ []*domain.ReportDto, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.ListReports")
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.ListTimeout)
+	defer cancel()
+
+	// the queue is read by the operators right after they resolve the reports
+	query := repo.cluster.Primary().WithContext(ctx).Where("status = ?", listReqDto.Status.String()).Order("created_at")
+	if listReqDto.Limit > 0 {
+		query = query.Limit(listReqDto.Limit)
+	}
+
+	var records []Report
+	if result := query.Find(&records); result.Error != nil {
+		slog.ErrorContext(ctx, "failed to list reports", logkit.Err(result.Error))
+		return nil, translateError(ctx, result.Error)
+	}
+
+	objs := make([]*domain.ReportDto, len(records))
+	for i, record := range records {
+		objs[i] = &domain.ReportDto{
+			ID:         record.ID,
+			TargetID:   record.TargetID,
+			Reason:     record.Reason,
+			Contact:    record.Contact,
+			Status:     domain.ReportStatus(record.Status),
+			CreatedAt:  record.CreatedAt,
+			ResolvedAt: record.ResolvedAt,
+		}
+	}
+	return objs, nil
+}
+
+// ResolveReports sets the status of the pending reports of the id
+func (repo *ShortUrlRepository) ResolveReports(ctx context.Context, id string, status domain.ReportStatus) (_ int, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.ResolveReports", attrID.String(id))
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.CreateTimeout)
+	defer cancel()
+
+	result := repo.cluster.Primary().WithContext(ctx).Model(&Report{}).
+		Where("target_id = ? AND status = ?", id, domain.ReportStatusPending.String()).
+		Updates(map[string]interface{}{"status": status.String(), "resolved_at": now()})
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to resolve reports", "id", id, logkit.Err(result.Error))
+		return 0, translateError(ctx, result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// BanCreator bans the creator from creating the short urls, banning it again keeps the first reason
+func (repo *ShortUrlRepository) BanCreator(ctx context.Context, creator string, reason string) (err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.BanCreator")
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.CreateTimeout)
+	defer cancel()
+
+	record := BannedCreator{Creator: creator, Reason: truncate(reason, maxBanReasonLen), CreatedAt: now()}
+	if result := repo.cluster.Primary().WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&record); result.Error != nil {
+		slog.ErrorContext(ctx, "failed to ban creator", logkit.Err(result.Error))
+		return translateError(ctx, result.Error)
+	}
+	slog.InfoContext(ctx, "ban creator", "creator", creator)
+	return nil
+}
+
+// IsCreatorBanned reports whether the creator is banned, which is read from the primary so that the ban takes effect at once
+func (repo *ShortUrlRepository) IsCreatorBanned(ctx context.Context, creator string) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.IsCreatorBanned")
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.GetTimeout)
+	defer cancel()
+
+	var count int64
+	if result := repo.cluster.Primary().WithContext(ctx).Model(&BannedCreator{}).Where("creator = ?", creator).Count(&count); result.Error != nil {
+		slog.ErrorContext(ctx, "failed to check the banned creator", logkit.Err(result.Error))
+		return false, translateError(ctx, result.Error)
+	}
+	return count > 0, nil
+}
+
+// CreateParamTemplate creates the param template, or returns domain.ErrDuplicatedKey if the name is taken
+func (repo *ShortUrlRepository) CreateParamTemplate(ctx context.Context, templateDto *domain.ParamTemplateDto) (err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.CreateParamTemplate")
+	defer func() {
+		if err != domain.ErrDuplicatedKey {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.CreateTimeout)
+	defer cancel()
+
+	record := ParamTemplate{
+		Name:      templateDto.Name,
+		Query:     templateDto.Query,
+		CreatedAt: templateDto.CreatedAt,
+		UpdatedAt: templateDto.UpdatedAt,
+	}
+	if result := repo.cluster.Primary().WithContext(ctx).Create(&record); result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return domain.ErrDuplicatedKey
+		}
+		slog.ErrorContext(ctx, "failed to create param template", "name", templateDto.Name, logkit.Err(result.Error))
+		return translateError(ctx, result.Error)
+	}
+	return nil
+}
+
+// GetParamTemplate gets the param template by the name from the primary, which is cached by the caller
+func (repo *ShortUrlRepository) GetParamTemplate(ctx context.Context, name string) (_ *domain.ParamTemplateDto, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.GetParamTemplate")
+	defer func() {
+		if err != domain.ErrRecordNotFound {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.GetTimeout)
+	defer cancel()
+
+	var record ParamTemplate
+	if result := repo.cluster.Primary().WithContext(ctx).Where("name = ?", name).First(&record); result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrRecordNotFound
+		}
+		slog.ErrorContext(ctx, "failed to get param template", "name", name, logkit.Err(result.Error))
+		return nil, translateError(ctx, result.Error)
+	}
+	return toParamTemplateDto(&record), nil
+}
+
+// ListParamTemplates lists all the param templates by the name
+func (repo *ShortUrlRepository) ListParamTemplates(ctx context.Context) (_ []*domain.ParamTemplateDto, err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.ListParamTemplates")
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.ListTimeout)
+	defer cancel()
+
+	var records []ParamTemplate
+	if result := repo.cluster.Primary().WithContext(ctx).Order("name").Find(&records); result.Error != nil {
+		slog.ErrorContext(ctx, "failed to list param templates", logkit.Err(result.Error))
+		return nil, translateError(ctx, result.Error)
+	}
+
+	objs := make([]*domain.ParamTemplateDto, len(records))
+	for i := range records {
+		objs[i] = toParamTemplateDto(&records[i])
+	}
+	return objs, nil
+}
+
+// UpdateParamTemplate replaces the query of the param template
+func (repo *ShortUrlRepository) UpdateParamTemplate(ctx context.Context, templateDto *domain.ParamTemplateDto) (err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.UpdateParamTemplate")
+	defer func() {
+		if err != domain.ErrRecordNotFound {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.CreateTimeout)
+	defer cancel()
+
+	// updated_at always changes, so the rows affected tell the missing one
+	result := repo.cluster.Primary().WithContext(ctx).Model(&ParamTemplate{}).Where("name = ?", templateDto.Name).
+		Updates(map[string]interface{}{"query": templateDto.Query, "updated_at": templateDto.UpdatedAt})
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to update param template", "name", templateDto.Name, logkit.Err(result.Error))
+		return translateError(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteParamTemplate deletes the param template, the short urls attached to it are redirected without the params
+func (repo *ShortUrlRepository) DeleteParamTemplate(ctx context.Context, name string) (err error) {
+	ctx, span := startSpan(ctx, "ShortUrlRepository.DeleteParamTemplate")
+	defer func() {
+		if err != domain.ErrRecordNotFound {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	ctx, cancel := withTimeout(ctx, repo.cfg.CreateTimeout)
+	defer cancel()
+
+	result := repo.cluster.Primary().WithContext(ctx).Where("name = ?", name).Delete(&ParamTemplate{})
+	if result.Error != nil {
+		slog.ErrorContext(ctx, "failed to delete param template", "name", name, logkit.Err(result.Error))
+		return translateError(ctx, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrRecordNotFound
+	}
+	return nil
+}
+
+func toShortUrlDto(record *ShortUrl) *domain.ShortUrlDto {
+	return &domain.ShortUrlDto{
+		TargetID:     record.TargetID,
+		Url:          record.Url,
+		Status:       domain.LinkStatus(record.Status),
+		Creator:      record.Creator,
+		Interstitial: domain.Interstitial(record.Interstitial),
+		RedirectCode: record.RedirectCode,
+		Passthrough:  domain.Passthrough(record.Passthrough),
+		Template:     record.Template,
+		Rules:        toRuleDtos(record.Rules),
+		GeoRules:     toGeoRuleDtos(record.GeoRules),
+		Variants:     toVariantDtos(record.Variants),
+		ExpireAt:     record.ExpireAt,
+		CreatedAt:    record.CreatedAt,
+	}
+}
+
+func toRuleRecords(rules []domain.RedirectRule) []RedirectRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	records := make([]RedirectRule, len(rules))
+	for i, rule := range rules {
+		records[i] = RedirectRule{Platform: rule.Platform.String(), Url: rule.Url}
+	}
+	return records
+}
+
+func toRuleDtos(records []RedirectRule) []domain.RedirectRule {
+	if len(records) == 0 {
+		return nil
+	}
+	rules := make([]domain.RedirectRule, len(records))
+	for i, record := range records {
+		rules[i] = domain.RedirectRule{Platform: domain.Platform(record.Platform), Url: record.Url}
+	}
+	return rules
+}
+
+func toGeoRuleRecords(rules []domain.GeoRule) []GeoRule {
+	if len(rules) == 0 {
+		return nil
+	}
+	records := make([]GeoRule, len(rules))
+	for i, rule := range rules {
+		records[i] = GeoRule{Country: rule.Country, Continent: rule.Continent.String(), Url: rule.Url}
+	}
+	return records
+}
+
+func toGeoRuleDtos(records []GeoRule) []domain.GeoRule {
+	if len(records) == 0 {
+		return nil
+	}
+	rules := make([]domain.GeoRule, len(records))
+	for i, record := range records {
+		rules[i] = domain.GeoRule{Country: record.Country, Continent: domain.Continent(record.Continent), Url: record.Url}
+	}
+	return rules
+}
+
+func toVariantRecords(variants []domain.Variant) []Variant {
+	if len(variants) == 0 {
+		return nil
+	}
+	records := make([]Variant, len(variants))
+	for i, variant := range variants {
+		records[i] = Variant{Url: variant.Url, Weight: variant.Weight}
+	}
+	return records
+}
+
+func toVariantDtos(records []Variant) []domain.Variant {
+	if len(records) == 0 {
+		return nil
+	}
+	variants := make([]domain.Variant, len(records))
+	for i, record := range records {
+		variants[i] = domain.Variant{Url: record.Url, Weight: record.Weight}
+	}
+	return variants
+}
+
+func toParamTemplateDto(record *ParamTemplate) *domain.ParamTemplateDto {
+	return &domain.ParamTemplateDto{
+		Name:      record.Name,
+		Query:     record.Query,
+		CreatedAt: record.CreatedAt,
+		UpdatedAt: record.UpdatedAt,
+	}
+}
+
+// truncate cuts s to at most n bytes without breaking the UTF-8 characters
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// startSpan starts the client span of the query
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, semconv.DBSystemMySQL)...),
+	)
+}
+
+func first(ctx context.Context, db *gorm.DB, id string, record *ShortUrl) *gorm.DB {
+	return db.WithContext(ctx).Where("target_id = ?", id).Select([]string{"url", "status", "interstitial", "redirect_code", "passthrough", "template", "rules", "geo_rules", "variants", "expire_at", "created_at"}).First(record)
+}
+
+// withTimeout derives a context bounded by the given timeout, or returns the context as it is if the timeout is not set
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// translateError maps the errors caused by an exceeded deadline to domain.ErrTimeout.
+// The driver doesn't always return context.DeadlineExceeded (e.g. `invalid connection` when the deadline
+// hits in the middle of a query), so the context itself is checked as well.
+func translateError(ctx context.Context, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return domain.ErrTimeout
+	}
+	return err
+}
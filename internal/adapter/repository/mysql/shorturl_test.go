@@ -5,9 +5,11 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/Hao1995/short-url/database"
 	"github.com/Hao1995/short-url/internal/domain"
 	"github.com/Hao1995/short-url/internal/usecase"
 	"github.com/Hao1995/short-url/pkg/migrationkit"
@@ -19,13 +21,13 @@ import (
 )
 
 const (
-	DB_PASSWORD    = "password"
-	MIGRATION_PATH = "../../../../database/migration"
+	DB_PASSWORD = "password"
 )
 
 type ShortUrlTestSuite struct {
 	suite.Suite
 	dockertestClose func() error
+	dsn             string
 
 	now time.Time
 
@@ -39,34 +41,45 @@ func TestShortUrlTestSuite(t *testing.T) {
 
 func (s *ShortUrlTestSuite) SetupSuite() {
 	var err error
-	var dbDSN string
-	dbDSN, s.dockertestClose, err = ConnectToDockerTestDB()
+	s.dsn, s.dockertestClose, err = ConnectToDockerTestDB()
 	if err != nil {
 		log.Fatal("failed to connect to docker test DB", err)
 	}
 
-	if err := migrationkit.GooseMigrate(dbDSN, MIGRATION_PATH); err != nil {
-		log.Fatal("failed to migrate DB", err)
-	}
-
 	// Connect to DB
-	s.db, err = gorm.Open(mysql.Open(dbDSN), &gorm.Config{TranslateError: true})
+	s.db, err = gorm.Open(mysql.Open(s.dsn), &gorm.Config{TranslateError: true})
 	if err != nil {
 		log.Fatal("failed to init GORM connection", err)
 	}
 
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		log.Fatal("failed to get sqlDB from gorm", err)
+	}
+	migrator, err := migrationkit.NewMigrator(sqlDB, database.Migrations(), migrationkit.Config{})
+	if err != nil {
+		log.Fatal("failed to init migrator", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
+		log.Fatal("failed to migrate DB", err)
+	}
+
 	s.now = time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC)
 	now = func() time.Time {
 		return s.now
 	}
 
-	s.impl = NewShortUrlRepository(s.db)
+	s.impl = NewShortUrlRepository(NewCluster(s.db), Config{CreateTimeout: 3 * time.Second, GetTimeout: time.Second, ListTimeout: 3 * time.Second})
 }
 
 func (s *ShortUrlTestSuite) SetupTest() {}
 
 func (s *ShortUrlTestSuite) TearDownSubTest() {
 	s.db.Where("1=1").Delete(&ShortUrl{})
+	s.db.Where("1=1").Delete(&Click{})
+	s.db.Where("1=1").Delete(&Report{})
+	s.db.Where("1=1").Delete(&BannedCreator{})
+	s.db.Where("1=1").Delete(&ParamTemplate{})
 }
 
 func (s *ShortUrlTestSuite) TearDownTest() {}
@@ -151,11 +164,48 @@ func (s *ShortUrlTestSuite) TestGet() {
 			},
 			req: "testid1",
 			exp: &domain.GetRespDto{
-				Url:      "https://example.com/whatever1",
-				ExpireAt: s.now,
+				Url:       "https://example.com/whatever1",
+				ExpireAt:  s.now,
+				CreatedAt: s.now,
 			},
 			expErr: nil,
 		},
+		{
+			name: "get record with the redirect rules and the geo rules in order",
+			setup: func() {
+				_, err := s.impl.Create(context.Background(), &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					TargetID: "testid1",
+					ExpireAt: s.now,
+					Rules: []domain.RedirectRule{
+						{Platform: domain.PlatformIos, Url: "https://apps.example.com/whatever1"},
+						{Platform: domain.PlatformMobile, Url: "https://m.example.com/whatever1"},
+					},
+					GeoRules: []domain.GeoRule{{Continent: domain.ContinentEU, Url: "https://eu.example.com/whatever1"}},
+					Variants: []domain.Variant{
+						{Url: "https://a.example.com/whatever1", Weight: 70},
+						{Url: "https://b.example.com/whatever1", Weight: 30},
+					},
+				})
+				s.Require().NoError(err)
+			},
+			req: "testid1",
+			exp: &domain.GetRespDto{
+				Url:        "https://example.com/whatever1",
+				ExpireAt:   s.now,
+				LinkStatus: domain.LinkStatusActive,
+				Rules: []domain.RedirectRule{
+					{Platform: domain.PlatformIos, Url: "https://apps.example.com/whatever1"},
+					{Platform: domain.PlatformMobile, Url: "https://m.example.com/whatever1"},
+				},
+				GeoRules: []domain.GeoRule{{Continent: domain.ContinentEU, Url: "https://eu.example.com/whatever1"}},
+				Variants: []domain.Variant{
+					{Url: "https://a.example.com/whatever1", Weight: 70},
+					{Url: "https://b.example.com/whatever1", Weight: 30},
+				},
+				CreatedAt: s.now,
+			},
+		},
 		{
 			name:   "record not found",
 			req:    "testid1",
@@ -175,6 +225,311 @@ func (s *ShortUrlTestSuite) TestGet() {
 	}
 }
 
+func (s *ShortUrlTestSuite) TestGetFromAnotherInstance() {
+	// the replica never catches up, which is an empty schema of the same server
+	s.Require().NoError(s.db.Exec("CREATE DATABASE IF NOT EXISTS lagging_replica").Error)
+	replica, err := gorm.Open(mysql.Open(strings.Replace(s.dsn, "/mysql?", "/lagging_replica?", 1)), &gorm.Config{TranslateError: true})
+	s.Require().NoError(err)
+	sqlDB, err := replica.DB()
+	s.Require().NoError(err)
+	defer sqlDB.Close()
+	migrator, err := migrationkit.NewMigrator(sqlDB, database.Migrations(), migrationkit.Config{})
+	s.Require().NoError(err)
+	_, err = migrator.Up(context.Background())
+	s.Require().NoError(err)
+
+	cfg := Config{CreateTimeout: 3 * time.Second, GetTimeout: time.Second, ListTimeout: 3 * time.Second, ReadYourWritesWindow: time.Minute, MissConfirmRate: 2}
+	creator := NewShortUrlRepository(NewCluster(s.db, replica), cfg)
+	reader := NewShortUrlRepository(NewCluster(s.db, replica), cfg)
+
+	s.Suite.Run("read the link created by another instance from the primary", func() {
+		ctx := context.Background()
+		_, err := creator.Create(ctx, &domain.CreateReqDto{Url: "https://example.com/whatever1", TargetID: "testid1", ExpireAt: s.now})
+		s.Require().NoError(err)
+
+		obj, err := reader.Get(ctx, "testid1")
+		s.NoError(err)
+		s.Equal("https://example.com/whatever1", obj.Url)
+	})
+
+	s.Suite.Run("not found in both the replica and the primary", func() {
+		_, err := reader.Get(context.Background(), "testid2")
+		s.Equal(domain.ErrRecordNotFound, err)
+	})
+
+	s.Suite.Run("not confirmed on the primary over the rate", func() {
+		_, err := reader.Get(context.Background(), "testid1")
+		s.Equal(domain.ErrRecordNotFound, err)
+	})
+}
+
+func (s *ShortUrlTestSuite) TestList() {
+	for _, t := range []struct {
+		name   string
+		req    *domain.ListReqDto
+		setup  func()
+		exp    []*domain.ShortUrlDto
+		expErr error
+	}{
+		{
+			name: "list records by url from the newest one",
+			req:  &domain.ListReqDto{Url: "https://example.com/whatever1", Limit: 10},
+			setup: func() {
+				for _, shortUrl := range []ShortUrl{
+					{Url: "https://example.com/whatever1", TargetID: "testid1", ExpireAt: s.now, CreatedAt: s.now.Add(-1 * time.Hour)},
+					{Url: "https://example.com/whatever1", TargetID: "testid2", ExpireAt: s.now, CreatedAt: s.now},
+					{Url: "https://example.com/whatever2", TargetID: "testid3", ExpireAt: s.now, CreatedAt: s.now},
+				} {
+					s.Suite.Nil(s.db.Create(&shortUrl).Error)
+				}
+			},
+			exp: []*domain.ShortUrlDto{
+				{TargetID: "testid2", Url: "https://example.com/whatever1", ExpireAt: s.now, CreatedAt: s.now},
+				{TargetID: "testid1", Url: "https://example.com/whatever1", ExpireAt: s.now, CreatedAt: s.now.Add(-1 * time.Hour)},
+			},
+		},
+		{
+			name: "list records with limit",
+			req:  &domain.ListReqDto{Limit: 1},
+			setup: func() {
+				for _, shortUrl := range []ShortUrl{
+					{Url: "https://example.com/whatever1", TargetID: "testid1", ExpireAt: s.now, CreatedAt: s.now.Add(-1 * time.Hour)},
+					{Url: "https://example.com/whatever2", TargetID: "testid2", ExpireAt: s.now, CreatedAt: s.now},
+				} {
+					s.Suite.Nil(s.db.Create(&shortUrl).Error)
+				}
+			},
+			exp: []*domain.ShortUrlDto{
+				{TargetID: "testid2", Url: "https://example.com/whatever2", ExpireAt: s.now, CreatedAt: s.now},
+			},
+		},
+		{
+			name: "no record",
+			req:  &domain.ListReqDto{Url: "https://example.com/whatever1"},
+			exp:  []*domain.ShortUrlDto{},
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			ctx := context.Background()
+			if t.setup != nil {
+				t.setup()
+			}
+			objs, err := s.impl.List(ctx, t.req)
+			s.ErrorIs(err, t.expErr)
+			s.Equal(t.exp, objs)
+		})
+	}
+}
+
+func (s *ShortUrlTestSuite) TestListByStatus() {
+	s.Suite.Run("list the unexpired records of the statuses after the id", func() {
+		for _, shortUrl := range []ShortUrl{
+			{Url: "https://example.com/whatever1", TargetID: "testid1", Status: "Active", ExpireAt: s.now.Add(time.Hour), CreatedAt: s.now},
+			{Url: "https://example.com/whatever2", TargetID: "testid2", Status: "PendingReview", ExpireAt: s.now.Add(time.Hour), CreatedAt: s.now},
+			{Url: "https://example.com/whatever3", TargetID: "testid3", Status: "Disabled", ExpireAt: s.now.Add(time.Hour), CreatedAt: s.now},
+			{Url: "https://example.com/whatever4", TargetID: "testid4", Status: "Active", ExpireAt: s.now, CreatedAt: s.now},
+			{Url: "https://example.com/whatever5", TargetID: "testid5", Status: "Active", ExpireAt: s.now.Add(time.Hour), CreatedAt: s.now},
+		} {
+			s.Suite.Nil(s.db.Create(&shortUrl).Error)
+		}
+
+		objs, err := s.impl.ListByStatus(context.Background(), &domain.ListByStatusReqDto{
+			Statuses: []domain.LinkStatus{domain.LinkStatusActive, domain.LinkStatusPendingReview},
+			AfterID:  "testid1",
+			Limit:    10,
+		})
+		s.NoError(err)
+		s.Equal([]*domain.ShortUrlDto{
+			{TargetID: "testid2", Url: "https://example.com/whatever2", Status: domain.LinkStatusPendingReview, ExpireAt: s.now.Add(time.Hour), CreatedAt: s.now},
+			{TargetID: "testid5", Url: "https://example.com/whatever5", Status: domain.LinkStatusActive, ExpireAt: s.now.Add(time.Hour), CreatedAt: s.now},
+		}, objs)
+	})
+}
+
+func (s *ShortUrlTestSuite) TestReports() {
+	s.Suite.Run("create, list and resolve the reports", func() {
+		for _, id := range []string{"testid1", "testid1", "testid2"} {
+			s.NoError(s.impl.CreateReport(context.Background(), &domain.ReportDto{TargetID: id, Reason: "phishing"}))
+		}
+
+		count, err := s.impl.ResolveReports(context.Background(), "testid1", domain.ReportStatusDismissed)
+		s.NoError(err)
+		s.Equal(2, count)
+
+		objs, err := s.impl.ListReports(context.Background(), &domain.ListReportsReqDto{Status: domain.ReportStatusPending, Limit: 10})
+		s.NoError(err)
+		s.Len(objs, 1)
+		s.Equal("testid2", objs[0].TargetID)
+		s.Equal(domain.ReportStatusPending, objs[0].Status)
+		s.Nil(objs[0].ResolvedAt)
+
+		objs, err = s.impl.ListReports(context.Background(), &domain.ListReportsReqDto{Status: domain.ReportStatusDismissed, Limit: 10})
+		s.NoError(err)
+		s.Len(objs, 2)
+		s.Equal(&s.now, objs[0].ResolvedAt)
+	})
+}
+
+func (s *ShortUrlTestSuite) TestBanCreator() {
+	s.Suite.Run("ban the creator twice", func() {
+		banned, err := s.impl.IsCreatorBanned(context.Background(), "whatever-creator")
+		s.NoError(err)
+		s.False(banned)
+
+		s.NoError(s.impl.BanCreator(context.Background(), "whatever-creator", "whatever"))
+		s.NoError(s.impl.BanCreator(context.Background(), "whatever-creator", "whatever"))
+
+		banned, err = s.impl.IsCreatorBanned(context.Background(), "whatever-creator")
+		s.NoError(err)
+		s.True(banned)
+	})
+}
+
+func (s *ShortUrlTestSuite) TestParamTemplates() {
+	s.Suite.Run("create, update, list and delete the param templates", func() {
+		obj := &domain.ParamTemplateDto{Name: "newsletter", Query: "utm_source=newsletter", CreatedAt: s.now, UpdatedAt: s.now}
+		s.NoError(s.impl.CreateParamTemplate(context.Background(), obj))
+		s.Equal(domain.ErrDuplicatedKey, s.impl.CreateParamTemplate(context.Background(), obj))
+
+		updatedAt := s.now.Add(time.Hour)
+		s.NoError(s.impl.UpdateParamTemplate(context.Background(), &domain.ParamTemplateDto{Name: "newsletter", Query: "utm_source=mail", UpdatedAt: updatedAt}))
+		s.Equal(domain.ErrRecordNotFound, s.impl.UpdateParamTemplate(context.Background(), &domain.ParamTemplateDto{Name: "whatever", UpdatedAt: updatedAt}))
+
+		got, err := s.impl.GetParamTemplate(context.Background(), "newsletter")
+		s.NoError(err)
+		s.Equal(&domain.ParamTemplateDto{Name: "newsletter", Query: "utm_source=mail", CreatedAt: s.now, UpdatedAt: updatedAt}, got)
+
+		objs, err := s.impl.ListParamTemplates(context.Background())
+		s.NoError(err)
+		s.Len(objs, 1)
+
+		s.NoError(s.impl.DeleteParamTemplate(context.Background(), "newsletter"))
+		s.Equal(domain.ErrRecordNotFound, s.impl.DeleteParamTemplate(context.Background(), "newsletter"))
+		_, err = s.impl.GetParamTemplate(context.Background(), "newsletter")
+		s.Equal(domain.ErrRecordNotFound, err)
+	})
+}
+
+func (s *ShortUrlTestSuite) TestUpdate() {
+	disabled := domain.LinkStatusDisabled
+	expireAt := s.now.Add(time.Hour)
+
+	for _, t := range []struct {
+		name   string
+		req    *domain.UpdateReqDto
+		setup  func()
+		exp    *domain.ShortUrlDto
+		expErr error
+	}{
+		{
+			name: "update the status and the expiry",
+			req:  &domain.UpdateReqDto{Status: &disabled, ExpireAt: &expireAt},
+			setup: func() {
+				s.Suite.Nil(s.db.Create(&ShortUrl{Url: "https://example.com/whatever1", TargetID: "testid1", Status: "Active", ExpireAt: s.now, CreatedAt: s.now}).Error)
+			},
+			exp: &domain.ShortUrlDto{TargetID: "testid1", Url: "https://example.com/whatever1", Status: domain.LinkStatusDisabled, ExpireAt: expireAt, CreatedAt: s.now},
+		},
+		{
+			name: "update the record with the same values",
+			req:  &domain.UpdateReqDto{Status: &disabled},
+			setup: func() {
+				s.Suite.Nil(s.db.Create(&ShortUrl{Url: "https://example.com/whatever1", TargetID: "testid1", Status: "Disabled", ExpireAt: s.now, CreatedAt: s.now}).Error)
+			},
+			exp: &domain.ShortUrlDto{TargetID: "testid1", Url: "https://example.com/whatever1", Status: domain.LinkStatusDisabled, ExpireAt: s.now, CreatedAt: s.now},
+		},
+		{
+			name:   "record not found",
+			req:    &domain.UpdateReqDto{Status: &disabled},
+			exp:    nil,
+			expErr: domain.ErrRecordNotFound,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			ctx := context.Background()
+			if t.setup != nil {
+				t.setup()
+			}
+			s.ErrorIs(s.impl.Update(ctx, "testid1", t.req), t.expErr)
+
+			obj, err := s.impl.Find(ctx, "testid1")
+			if t.exp != nil {
+				s.NoError(err)
+			}
+			s.Equal(t.exp, obj)
+		})
+	}
+}
+
+func (s *ShortUrlTestSuite) TestClicks() {
+	s.Suite.Run("list the clicks created from the newest one", func() {
+		ctx := context.Background()
+		s.NoError(s.impl.CreateClicks(ctx, []*domain.ClickDto{
+			{TargetID: "testid1", Referer: "https://example.com/", UserAgent: "whatever-agent", ClickedAt: s.now.Add(-1 * time.Second)},
+			{TargetID: "testid1", Rule: 2, Referer: strings.Repeat("a", maxRefererLen+1), ClickedAt: s.now},
+			{TargetID: "testid1", Variant: 1, ClickedAt: s.now.Add(-2 * time.Second)},
+			{TargetID: "testid2", ClickedAt: s.now},
+		}))
+
+		objs, err := s.impl.ListClicks(ctx, "testid1", 10)
+		s.NoError(err)
+		s.Equal([]*domain.ClickDto{
+			{TargetID: "testid1", Rule: 2, Referer: strings.Repeat("a", maxRefererLen), ClickedAt: s.now},
+			{TargetID: "testid1", Referer: "https://example.com/", UserAgent: "whatever-agent", ClickedAt: s.now.Add(-1 * time.Second)},
+			{TargetID: "testid1", Variant: 1, ClickedAt: s.now.Add(-2 * time.Second)},
+		}, objs)
+
+		counts, err := s.impl.CountClicksByVariant(ctx, "testid1")
+		s.NoError(err)
+		s.Equal(map[int]int64{0: 2, 1: 1}, counts)
+	})
+}
+
+func (s *ShortUrlTestSuite) TestTimeout() {
+	// the deadline is always exceeded before the query is sent
+	impl := NewShortUrlRepository(NewCluster(s.db), Config{CreateTimeout: time.Nanosecond, GetTimeout: time.Nanosecond})
+
+	for _, t := range []struct {
+		name   string
+		run    func(ctx context.Context) error
+		expErr error
+	}{
+		{
+			name: "create record timeout",
+			run: func(ctx context.Context) error {
+				_, err := impl.Create(ctx, &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					TargetID: "testid1",
+					ExpireAt: s.now,
+				})
+				return err
+			},
+			expErr: domain.ErrTimeout,
+		},
+		{
+			name: "get record timeout",
+			run: func(ctx context.Context) error {
+				_, err := impl.Get(ctx, "testid1")
+				return err
+			},
+			expErr: domain.ErrTimeout,
+		},
+		{
+			name: "get record with a canceled context",
+			run: func(ctx context.Context) error {
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+				_, err := s.impl.Get(ctx, "testid1")
+				return err
+			},
+			expErr: context.Canceled,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			s.ErrorIs(t.run(context.Background()), t.expErr)
+		})
+	}
+}
+
 func ConnectToDockerTestDB() (string, func() error, error) {
 	// Set up test db
 	pool, err := dockertest.NewPool("")
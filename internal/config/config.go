@@ -0,0 +1,350 @@
+// Package config loads the typed configuration of the service from a YAML or TOML file, overridden by the env vars,
+// and validates it at startup. The defaults live in Default instead of the tags, so that they apply to both sources.
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Hao1995/short-url/pkg/logkit"
+
+	"github.com/BurntSushi/toml"
+	"github.com/caarlos0/env/v11"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	App    App    `yaml:"app" toml:"app" envPrefix:"APP_"`
+	MySQL  MySQL  `yaml:"mysql" toml:"mysql" envPrefix:"MYSQL_"`
+	Redis  Redis  `yaml:"redis" toml:"redis" envPrefix:"REDIS_"`
+	Cache  Cache  `yaml:"cache" toml:"cache" envPrefix:"CACHE_"`
+	Click  Click  `yaml:"click" toml:"click" envPrefix:"CLICK_"`
+	Policy Policy `yaml:"policy" toml:"policy" envPrefix:"POLICY_"`
+	Scan   Scan   `yaml:"scan" toml:"scan" envPrefix:"SCAN_"`
+	Trace  Trace  `yaml:"trace" toml:"trace" envPrefix:"TRACE_"`
+	Log    Log    `yaml:"log" toml:"log" envPrefix:"LOG_"`
+
+	Redirect     Redirect     `yaml:"redirect" toml:"redirect" envPrefix:"REDIRECT_"`
+	Interstitial Interstitial `yaml:"interstitial" toml:"interstitial" envPrefix:"INTERSTITIAL_"`
+	Geo          Geo          `yaml:"geo" toml:"geo" envPrefix:"GEO_"`
+	Split        Split        `yaml:"split" toml:"split" envPrefix:"SPLIT_"`
+	// Tenants are the settings by the host serving the short urls, which are set by the file only
+	Tenants map[string]Tenant `yaml:"tenants" toml:"tenants"`
+}
+
+type App struct {
+	Name string `yaml:"name" toml:"name" env:"NAME"`
+	// Host is the scheme and the host of the short urls, e.g. https://sho.rt
+	Host string `yaml:"host" toml:"host" env:"HOST"`
+	Port string `yaml:"port" toml:"port" env:"PORT"`
+	// AdminPort serves the endpoints for the operators, e.g. /metrics
+	AdminPort string `yaml:"admin_port" toml:"admin_port" env:"ADMIN_PORT"`
+	Env       string `yaml:"env" toml:"env" env:"ENV"`
+
+	// ReadinessTimeout bounds the ping of each dependency on the readiness probe
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout" toml:"readiness_timeout" env:"READINESS_TIMEOUT"`
+	// ShutdownDrainDelay is how long the instance keeps serving after it turns not ready, so that the load balancers drain it
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay" toml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	// ShutdownTimeout bounds draining the in-flight requests after the server stops accepting
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+
+	// TemplateDir contains the HTML templates overriding the embedded ones of the same names, e.g. interstitial.html
+	TemplateDir string `yaml:"template_dir" toml:"template_dir" env:"TEMPLATE_DIR"`
+}
+
+type MySQL struct {
+	Host     string `yaml:"host" toml:"host" env:"HOST"`
+	Port     string `yaml:"port" toml:"port" env:"PORT"`
+	User     string `yaml:"user" toml:"user" env:"USER"`
+	Password string `yaml:"password" toml:"password" env:"PASSWORD"`
+	DB       string `yaml:"db" toml:"db" env:"DB"`
+
+	CreateTimeout time.Duration `yaml:"create_timeout" toml:"create_timeout" env:"CREATE_TIMEOUT"`
+	GetTimeout    time.Duration `yaml:"get_timeout" toml:"get_timeout" env:"GET_TIMEOUT"`
+	ListTimeout   time.Duration `yaml:"list_timeout" toml:"list_timeout" env:"LIST_TIMEOUT"`
+
+	// ReplicaAddrs are the `host:port` of the read replicas, which share the credentials of the primary
+	ReplicaAddrs         []string      `yaml:"replica_addrs" toml:"replica_addrs" env:"REPLICA_ADDRS" envSeparator:","`
+	ReadYourWritesWindow time.Duration `yaml:"read_your_writes_window" toml:"read_your_writes_window" env:"READ_YOUR_WRITES_WINDOW"`
+	HealthCheckInterval  time.Duration `yaml:"health_check_interval" toml:"health_check_interval" env:"HEALTH_CHECK_INTERVAL"`
+	HealthCheckTimeout   time.Duration `yaml:"health_check_timeout" toml:"health_check_timeout" env:"HEALTH_CHECK_TIMEOUT"`
+
+	// MissConfirmRate is how many misses of the replicas each instance confirms on the primary per second, 0 disables it
+	MissConfirmRate int `yaml:"miss_confirm_rate" toml:"miss_confirm_rate" env:"MISS_CONFIRM_RATE"`
+
+	// Shards are the clusters the links are sharded across, each of them is `primary|replica|...` in `host:port`.
+	// The cluster of Host, Port and ReplicaAddrs is the only shard if it's empty.
+	Shards        []string `yaml:"shards" toml:"shards" env:"SHARDS" envSeparator:","`
+	ShardStrategy string   `yaml:"shard_strategy" toml:"shard_strategy" env:"SHARD_STRATEGY"`
+}
+
+// LogValue keeps the password out of the logs
+func (m MySQL) LogValue() slog.Value {
+	type plain MySQL
+	p := plain(m)
+	p.Password = logkit.Redacted
+	return slog.AnyValue(p)
+}
+
+type Redis struct {
+	Addrs map[string]string `yaml:"addrs" toml:"addrs" env:"ADDRS" envSeparator:"-" envKeyValSeparator:"|"`
+}
+
+type Cache struct {
+	Size      int `yaml:"size" toml:"size" env:"SIZE"`
+	LocalTTL  int `yaml:"local_ttl" toml:"local_ttl" env:"LOCAL_TTL"`
+	SharedTTL int `yaml:"shared_ttl" toml:"shared_ttl" env:"SHARED_TTL"`
+
+	// the negative cache keeps the ids not found
+	NegativeSize      int `yaml:"negative_size" toml:"negative_size" env:"NEGATIVE_SIZE"`
+	NegativeLocalTTL  int `yaml:"negative_local_ttl" toml:"negative_local_ttl" env:"NEGATIVE_LOCAL_TTL"`
+	NegativeSharedTTL int `yaml:"negative_shared_ttl" toml:"negative_shared_ttl" env:"NEGATIVE_SHARED_TTL"`
+}
+
+// Click is the config of recording the clicks in the background
+type Click struct {
+	// BufferSize is the number of clicks waiting to be stored, the clicks beyond it are dropped
+	BufferSize    int           `yaml:"buffer_size" toml:"buffer_size" env:"BUFFER_SIZE"`
+	BatchSize     int           `yaml:"batch_size" toml:"batch_size" env:"BATCH_SIZE"`
+	FlushInterval time.Duration `yaml:"flush_interval" toml:"flush_interval" env:"FLUSH_INTERVAL"`
+}
+
+// Policy is the rules of the destination urls, see policykit.Matcher for the syntax of the host patterns
+type Policy struct {
+	// DenyHosts are the host patterns rejected, e.g. `example.com`, `*.example.com` or `/^ads[0-9]*\.example\.com$/`
+	DenyHosts []string `yaml:"deny_hosts" toml:"deny_hosts" env:"DENY_HOSTS" envSeparator:","`
+	// DenyHostsFile contains more patterns to reject, one per line, and the lines starting with `#` are skipped
+	DenyHostsFile string `yaml:"deny_hosts_file" toml:"deny_hosts_file" env:"DENY_HOSTS_FILE"`
+	// OwnHosts serve the short urls besides the host of app.host, which are rejected to avoid the redirect loops
+	OwnHosts []string `yaml:"own_hosts" toml:"own_hosts" env:"OWN_HOSTS" envSeparator:","`
+}
+
+// Scan is the config of scanning the destination urls against the local threat lists, see scankit.LocalDB
+type Scan struct {
+	// Mode is one of `none`, `sync` (scan on creating) and `async` (create the links waiting for the review)
+	Mode string `yaml:"mode" toml:"mode" env:"MODE"`
+	// Timeout bounds the scan of each url
+	Timeout time.Duration `yaml:"timeout" toml:"timeout" env:"TIMEOUT"`
+	// DBPath is the file of the threat lists, which is required unless the mode is `none`
+	DBPath string `yaml:"db_path" toml:"db_path" env:"DB_PATH"`
+	// DBReloadInterval is how often the file is checked for the changes
+	DBReloadInterval time.Duration `yaml:"db_reload_interval" toml:"db_reload_interval" env:"DB_RELOAD_INTERVAL"`
+	// RescanInterval is how often all the active links are scanned again, 0 disables it
+	RescanInterval time.Duration `yaml:"rescan_interval" toml:"rescan_interval" env:"RESCAN_INTERVAL"`
+	// PendingInterval is how often the links waiting for the review are scanned, 0 disables it
+	PendingInterval time.Duration `yaml:"pending_interval" toml:"pending_interval" env:"PENDING_INTERVAL"`
+	// BatchSize is the number of links listed at once on rescanning
+	BatchSize int `yaml:"batch_size" toml:"batch_size" env:"BATCH_SIZE"`
+}
+
+// Redirect is the config of the redirect responses
+type Redirect struct {
+	// Code is one of 301, 302, 307 and 308, which is used by the links without their own
+	Code int `yaml:"code" toml:"code" env:"CODE"`
+	// MaxAge caps how long the permanent redirects are cached by the browsers and the CDNs, which never outlive the links
+	MaxAge time.Duration `yaml:"max_age" toml:"max_age" env:"MAX_AGE"`
+}
+
+// Interstitial is the config of the warning page shown before redirecting to the destination
+type Interstitial struct {
+	// Mode is one of `never`, `unverified` (unless the destination host is allowlisted) and `always`,
+	// which is overridden by the tenants and the links
+	Mode string `yaml:"mode" toml:"mode" env:"MODE"`
+	// AllowHosts are the host patterns of the verified destinations, see policykit.Matcher for the syntax
+	AllowHosts []string `yaml:"allow_hosts" toml:"allow_hosts" env:"ALLOW_HOSTS" envSeparator:","`
+	// Delay is the countdown before redirecting automatically, 0 waits for the visitors to continue
+	Delay time.Duration `yaml:"delay" toml:"delay" env:"DELAY"`
+}
+
+// Geo is the config of locating the visitors for the geo rules of the links
+type Geo struct {
+	// DBPath is the MaxMind-format .mmdb file, e.g. GeoLite2-Country, the links with the geo rules are rejected if it's empty
+	DBPath string `yaml:"db_path" toml:"db_path" env:"DB_PATH"`
+	// DBReloadInterval is how often the file is checked for the changes
+	DBReloadInterval time.Duration `yaml:"db_reload_interval" toml:"db_reload_interval" env:"DB_RELOAD_INTERVAL"`
+	// TrustedProxies are the ips or the CIDRs of the proxies in front, e.g. `10.0.0.0/8`.
+	// The client ip is the peer unless it's one of them, then the X-Forwarded-For they appended is trusted.
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES" envSeparator:","`
+}
+
+// Split is the config of the split links, which send the visitors to the variants by weight
+type Split struct {
+	// CookieSecret signs the cookies keeping the visitors on their variants, which must be shared by all the instances.
+	// A random one is generated at startup if it's empty, then the visitors are assigned again by each instance.
+	CookieSecret string `yaml:"cookie_secret" toml:"cookie_secret" env:"COOKIE_SECRET"`
+	// CookieMaxAge is how long the visitors stay on their variants
+	CookieMaxAge time.Duration `yaml:"cookie_max_age" toml:"cookie_max_age" env:"COOKIE_MAX_AGE"`
+}
+
+// LogValue keeps the cookie secret out of the logs
+func (s Split) LogValue() slog.Value {
+	type plain Split
+	p := plain(s)
+	if p.CookieSecret != "" {
+		p.CookieSecret = logkit.Redacted
+	}
+	return slog.AnyValue(p)
+}
+
+// Tenant is the settings of a host serving the short urls
+type Tenant struct {
+	// Interstitial overrides interstitial.mode for the tenant if it isn't empty
+	Interstitial string `yaml:"interstitial" toml:"interstitial"`
+	// FallbackURL is redirected to instead of the error pages of the links not found or expired, if it isn't empty
+	FallbackURL string `yaml:"fallback_url" toml:"fallback_url"`
+}
+
+type Trace struct {
+	// Exporter is one of `otlp`, `stdout` and `none`
+	Exporter     string  `yaml:"exporter" toml:"exporter" env:"EXPORTER"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"OTLP_ENDPOINT"`
+	OTLPInsecure bool    `yaml:"otlp_insecure" toml:"otlp_insecure" env:"OTLP_INSECURE"`
+	SampleRatio  float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"SAMPLE_RATIO"`
+}
+
+type Log struct {
+	// Level is one of `debug`, `info`, `warn` and `error`
+	Level string `yaml:"level" toml:"level" env:"LEVEL"`
+	// SampleRatio is the ratio of the logs kept on the hot paths, e.g. redirecting. The warnings and errors are always kept.
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"SAMPLE_RATIO"`
+}
+
+// LogValue logs the config section by section, so that each section can hide its own secrets
+func (c Config) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("app", c.App),
+		slog.Any("mysql", c.MySQL),
+		slog.Any("redis", c.Redis),
+		slog.Any("cache", c.Cache),
+		slog.Any("click", c.Click),
+		slog.Any("policy", c.Policy),
+		slog.Any("scan", c.Scan),
+		slog.Any("trace", c.Trace),
+		slog.Any("log", c.Log),
+		slog.Any("redirect", c.Redirect),
+		slog.Any("interstitial", c.Interstitial),
+		slog.Any("geo", c.Geo),
+		slog.Any("split", c.Split),
+		slog.Any("tenants", c.Tenants),
+	)
+}
+
+// Default returns the config used when neither the file nor the env vars set the fields
+func Default() Config {
+	return Config{
+		App: App{
+			Name:               "short_url",
+			Host:               "http://localhost",
+			Port:               "8080",
+			AdminPort:          "9090",
+			Env:                "dev",
+			ReadinessTimeout:   time.Second,
+			ShutdownDrainDelay: 5 * time.Second,
+			ShutdownTimeout:    10 * time.Second,
+		},
+		MySQL: MySQL{
+			Host:                 "mysql",
+			Port:                 "3306",
+			User:                 "root",
+			Password:             "root",
+			DB:                   "short_url",
+			CreateTimeout:        3 * time.Second,
+			GetTimeout:           time.Second,
+			ListTimeout:          10 * time.Second,
+			ReadYourWritesWindow: 5 * time.Second,
+			MissConfirmRate:      20,
+			HealthCheckInterval:  5 * time.Second,
+			HealthCheckTimeout:   time.Second,
+			ShardStrategy:        "hash",
+		},
+		Redis: Redis{
+			Addrs: map[string]string{"server1": "redis:6379"},
+		},
+		Cache: Cache{
+			Size:              10000,
+			LocalTTL:          600,
+			SharedTTL:         3600,
+			NegativeSize:      10000,
+			NegativeLocalTTL:  5,
+			NegativeSharedTTL: 30,
+		},
+		Click: Click{
+			BufferSize:    10000,
+			BatchSize:     100,
+			FlushInterval: time.Second,
+		},
+		Scan: Scan{
+			Mode:             "none",
+			Timeout:          500 * time.Millisecond,
+			DBReloadInterval: time.Minute,
+			RescanInterval:   24 * time.Hour,
+			PendingInterval:  30 * time.Second,
+			BatchSize:        500,
+		},
+		Redirect: Redirect{
+			Code:   302,
+			MaxAge: 24 * time.Hour,
+		},
+		Interstitial: Interstitial{
+			Mode:  "never",
+			Delay: 5 * time.Second,
+		},
+		Geo: Geo{
+			DBReloadInterval: time.Minute,
+		},
+		Split: Split{
+			CookieMaxAge: 30 * 24 * time.Hour,
+		},
+		Trace: Trace{
+			Exporter:     "none",
+			OTLPEndpoint: "otel-collector:4318",
+			OTLPInsecure: true,
+			SampleRatio:  1,
+		},
+		Log: Log{
+			Level:       "info",
+			SampleRatio: 0.1,
+		},
+	}
+}
+
+// Load reads the config file, if the path isn't empty, over the defaults, then overrides it by the env vars
+// and validates the result. The format of the file is decided by its extension.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := decodeFile(path, &cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := env.Parse(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse env: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return &cfg, nil
+}
+
+func decodeFile(path string, cfg *Config) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, cfg)
+	case ".toml":
+		err = toml.Unmarshal(b, cfg)
+	default:
+		return fmt.Errorf("unsupported config file format: %s", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decode config file(%s): %w", path, err)
+	}
+	return nil
+}
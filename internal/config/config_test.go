@@ -0,0 +1,256 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ConfigTestSuite struct {
+	suite.Suite
+	dir string
+}
+
+func TestConfigTestSuite(t *testing.T) {
+	suite.Run(t, new(ConfigTestSuite))
+}
+
+func (s *ConfigTestSuite) SetupSubTest() {
+	s.dir = s.T().TempDir()
+}
+
+func (s *ConfigTestSuite) writeFile(name, content string) string {
+	path := filepath.Join(s.dir, name)
+	s.Require().NoError(os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func (s *ConfigTestSuite) TestLoad() {
+	for _, t := range []struct {
+		name   string
+		file   string
+		body   string
+		env    map[string]string
+		exp    func(cfg *Config)
+		expErr string
+	}{
+		{
+			name: "load the defaults without the file",
+			exp:  func(cfg *Config) {},
+		},
+		{
+			name: "load the yaml file over the defaults",
+			file: "config.yaml",
+			body: `
+app:
+  host: https://sho.rt
+mysql:
+  get_timeout: 500ms
+  shards: ["mysql1:3306|mysql1-replica:3306", "mysql2:3306"]
+cache:
+  local_ttl: 60
+`,
+			exp: func(cfg *Config) {
+				cfg.App.Host = "https://sho.rt"
+				cfg.MySQL.GetTimeout = 500 * time.Millisecond
+				cfg.MySQL.Shards = []string{"mysql1:3306|mysql1-replica:3306", "mysql2:3306"}
+				cfg.Cache.LocalTTL = 60
+			},
+		},
+		{
+			name: "load the toml file over the defaults",
+			file: "config.toml",
+			body: `
+[app]
+host = "https://sho.rt"
+
+[mysql]
+get_timeout = "500ms"
+`,
+			exp: func(cfg *Config) {
+				cfg.App.Host = "https://sho.rt"
+				cfg.MySQL.GetTimeout = 500 * time.Millisecond
+			},
+		},
+		{
+			name: "override the file by the env vars",
+			file: "config.yaml",
+			body: `
+app:
+  host: https://sho.rt
+`,
+			env: map[string]string{"APP_HOST": "https://env.sho.rt", "CACHE_LOCAL_TTL": "30"},
+			exp: func(cfg *Config) {
+				cfg.App.Host = "https://env.sho.rt"
+				cfg.Cache.LocalTTL = 30
+			},
+		},
+		{
+			name: "load the tenants from the file",
+			file: "config.yaml",
+			body: `
+interstitial:
+  mode: unverified
+  allow_hosts: ["*.example.com"]
+tenants:
+  go.example.com:
+    interstitial: always
+    fallback_url: https://example.com/home
+`,
+			exp: func(cfg *Config) {
+				cfg.Interstitial.Mode = "unverified"
+				cfg.Interstitial.AllowHosts = []string{"*.example.com"}
+				cfg.Tenants = map[string]Tenant{"go.example.com": {Interstitial: "always", FallbackURL: "https://example.com/home"}}
+			},
+		},
+		{
+			name:   "unsupported file format",
+			file:   "config.json",
+			body:   `{}`,
+			expErr: "unsupported config file format: .json",
+		},
+		{
+			name: "report all the invalid fields",
+			file: "config.yaml",
+			body: `
+app:
+  host: sho.rt
+mysql:
+  shard_strategy: range
+  health_check_interval: 0s
+policy:
+  deny_hosts: ["/[/"]
+log:
+  level: verbose
+redirect:
+  code: 303
+geo:
+  trusted_proxies: ["10.0.0.0/33"]
+split:
+  cookie_secret: secret
+tenants:
+  go.example.com:
+    interstitial: sometimes
+    fallback_url: example.com/home
+`,
+			expErr: strings.Join([]string{
+				"invalid config: app.host: must be an http(s) url without path, got \"sho.rt\"",
+				"mysql.health_check_interval: must be positive",
+				"mysql.shard_strategy: must be one of `hash` and `prefix`, got \"range\"",
+				"policy.deny_hosts: invalid pattern \"/[/\": error parsing regexp: missing closing ]: `[`",
+				"redirect.code: must be one of 301, 302, 307 and 308, got 303",
+				"geo.trusted_proxies[0]: must be an ip or a CIDR, got \"10.0.0.0/33\"",
+				"split.cookie_secret: must be at least 32 bytes",
+				"tenants[go.example.com].interstitial: must be one of `never`, `unverified` and `always`, got \"sometimes\"",
+				"tenants[go.example.com].fallback_url: must be an http(s) url, got \"example.com/home\"",
+				"log.level: must be one of `debug`, `info`, `warn` and `error`, got \"verbose\"",
+			}, "\n"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			for k, v := range t.env {
+				s.T().Setenv(k, v)
+			}
+			path := ""
+			if t.file != "" {
+				path = s.writeFile(t.file, t.body)
+			}
+
+			cfg, err := Load(path)
+			if t.expErr != "" {
+				s.EqualError(err, t.expErr)
+				return
+			}
+			s.Require().NoError(err)
+
+			exp := Default()
+			t.exp(&exp)
+			s.Equal(&exp, cfg)
+		})
+	}
+}
+
+func (s *ConfigTestSuite) TestReload() {
+	for _, t := range []struct {
+		name      string
+		body      string
+		expCalled bool
+		expErr    bool
+		exp       func(cfg *Config)
+	}{
+		{
+			name: "apply the reloadable fields",
+			body: `
+cache:
+  local_ttl: 60
+log:
+  level: debug
+policy:
+  deny_hosts: ["*.evil.com"]
+geo:
+  trusted_proxies: ["10.0.0.0/8"]
+tenants:
+  go.example.com:
+    fallback_url: https://example.com/home
+`,
+			expCalled: true,
+			exp: func(cfg *Config) {
+				cfg.Cache.LocalTTL = 60
+				cfg.Log.Level = "debug"
+				cfg.Policy.DenyHosts = []string{"*.evil.com"}
+				cfg.Geo.TrustedProxies = []string{"10.0.0.0/8"}
+				cfg.Tenants = map[string]Tenant{"go.example.com": {FallbackURL: "https://example.com/home"}}
+			},
+		},
+		{
+			name: "keep the fields required restarting",
+			body: `
+cache:
+  size: 1
+  local_ttl: 60
+mysql:
+  host: mysql2
+`,
+			expCalled: true,
+			exp: func(cfg *Config) {
+				cfg.Cache.LocalTTL = 60
+			},
+		},
+		{
+			name: "keep the current config if the new one is invalid",
+			body: `
+cache:
+  local_ttl: -1
+`,
+			expCalled: false,
+			expErr:    true,
+			exp:       func(cfg *Config) {},
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			path := s.writeFile("config.yaml", "")
+			cfg, err := Load(path)
+			s.Require().NoError(err)
+
+			w := NewWatcher(path, cfg, 0)
+			called := false
+			w.OnReload(func(cfg *Config) {
+				called = true
+				s.Same(w.Current(), cfg)
+			})
+
+			s.writeFile("config.yaml", t.body)
+			err = w.Reload()
+			s.Equal(t.expErr, err != nil)
+			s.Equal(t.expCalled, called)
+
+			exp := Default()
+			t.exp(&exp)
+			s.Equal(&exp, w.Current())
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Hao1995/short-url/pkg/logkit"
+)
+
+// applyReloadable copies the fields which are safe to change without restarting
+func applyReloadable(dst *Config, src *Config) {
+	dst.Cache.LocalTTL = src.Cache.LocalTTL
+	dst.Cache.SharedTTL = src.Cache.SharedTTL
+	dst.Cache.NegativeLocalTTL = src.Cache.NegativeLocalTTL
+	dst.Cache.NegativeSharedTTL = src.Cache.NegativeSharedTTL
+	dst.Log = src.Log
+	dst.Policy = src.Policy
+	dst.Interstitial = src.Interstitial
+	dst.Geo = src.Geo
+	dst.Tenants = src.Tenants
+}
+
+// Watcher reloads the config on SIGHUP or when the modification time of the file changes.
+// Only the reloadable fields are applied, the changes of the others are warned and take effect after restarting.
+type Watcher struct {
+	path     string
+	interval time.Duration
+
+	mu          sync.Mutex
+	current     *Config
+	modTime     time.Time
+	subscribers []func(cfg *Config)
+}
+
+// NewWatcher generates the watcher of the config loaded from the path. The file is polled every interval,
+// and polling is disabled if the interval isn't positive.
+func NewWatcher(path string, cfg *Config, interval time.Duration) *Watcher {
+	w := &Watcher{
+		path:     path,
+		interval: interval,
+		current:  cfg,
+	}
+	w.modTime, _ = w.stat()
+	return w
+}
+
+// OnReload registers the function called with the config reloaded, it's not safe to call after Run
+func (w *Watcher) OnReload(fn func(cfg *Config)) {
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// Current returns the config applied currently
+func (w *Watcher) Current() *Config {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Run blocks and reloads the config until the context is done
+func (w *Watcher) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if w.path != "" && w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			slog.Info("config. reload on SIGHUP")
+			w.Reload()
+		case <-tick:
+			if modTime, err := w.stat(); err == nil && !modTime.Equal(w.modTime) {
+				slog.Info("config. reload on the file changed", "path", w.path)
+				w.Reload()
+			}
+		}
+	}
+}
+
+// Reload loads the config again and applies the reloadable fields. The current config is kept if the new one is invalid.
+func (w *Watcher) Reload() error {
+	cfg, err := w.reload()
+	if err != nil {
+		slog.Error("config. failed to reload, keep the current config", logkit.Err(err))
+		return err
+	}
+
+	// the subscribers are called without the lock, so that they can read the current config
+	for _, fn := range w.subscribers {
+		fn(cfg)
+	}
+	return nil
+}
+
+func (w *Watcher) reload() (*Config, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.modTime, _ = w.stat()
+	loaded, err := Load(w.path)
+	if err != nil {
+		return nil, err
+	}
+
+	next := *w.current
+	applyReloadable(&next, loaded)
+	if !reflect.DeepEqual(&next, loaded) {
+		slog.Warn("config. the fields not reloadable are changed, which take effect after restarting")
+	}
+	w.current = &next
+	return w.current, nil
+}
+
+func (w *Watcher) stat() (time.Time, error) {
+	if w.path == "" {
+		return time.Time{}, os.ErrNotExist
+	}
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
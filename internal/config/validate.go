@@ -0,0 +1,192 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/pkg/geokit"
+	"github.com/Hao1995/short-url/pkg/policykit"
+	"github.com/Hao1995/short-url/pkg/shardkit"
+)
+
+// minCookieSecretLen is the length of the secret signing the cookies by HMAC-SHA256 at least
+const minCookieSecretLen = 32
+
+var redirectCodes = []int{http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect}
+
+// validator collects the errors of all the invalid fields, so that they can be fixed at once
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, field string, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validator) checkAddr(addr string, field string) {
+	host, port, err := net.SplitHostPort(addr)
+	v.check(err == nil && host != "" && validPort(port), field, "must be `host:port`, got %q", addr)
+}
+
+// Validate returns the errors of all the invalid fields joined
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// app
+	v.check(c.App.Name != "", "app.name", "must not be empty")
+	u, err := url.Parse(c.App.Host)
+	v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && strings.Trim(u.Path, "/") == "",
+		"app.host", "must be an http(s) url without path, got %q", c.App.Host)
+	v.check(validPort(c.App.Port), "app.port", "must be a port number, got %q", c.App.Port)
+	v.check(validPort(c.App.AdminPort), "app.admin_port", "must be a port number, got %q", c.App.AdminPort)
+	v.check(c.App.Port != c.App.AdminPort, "app.admin_port", "must differ from app.port")
+	v.check(c.App.Env != "", "app.env", "must not be empty")
+	v.check(c.App.ReadinessTimeout > 0, "app.readiness_timeout", "must be positive")
+	v.check(c.App.ShutdownDrainDelay >= 0, "app.shutdown_drain_delay", "must not be negative")
+	v.check(c.App.ShutdownTimeout > 0, "app.shutdown_timeout", "must be positive")
+
+	// mysql
+	if len(c.MySQL.Shards) == 0 {
+		v.checkAddr(net.JoinHostPort(c.MySQL.Host, c.MySQL.Port), "mysql.host, mysql.port")
+		for i, addr := range c.MySQL.ReplicaAddrs {
+			v.checkAddr(addr, fmt.Sprintf("mysql.replica_addrs[%d]", i))
+		}
+	}
+	for i, shard := range c.MySQL.Shards {
+		for j, addr := range strings.Split(shard, "|") {
+			v.checkAddr(addr, fmt.Sprintf("mysql.shards[%d][%d]", i, j))
+		}
+	}
+	v.check(len(c.MySQL.Shards) <= shardkit.MaxShards, "mysql.shards", "must not exceed %d shards", shardkit.MaxShards)
+	v.check(c.MySQL.User != "", "mysql.user", "must not be empty")
+	v.check(c.MySQL.DB != "", "mysql.db", "must not be empty")
+	v.check(c.MySQL.CreateTimeout >= 0, "mysql.create_timeout", "must not be negative")
+	v.check(c.MySQL.GetTimeout >= 0, "mysql.get_timeout", "must not be negative")
+	v.check(c.MySQL.ListTimeout >= 0, "mysql.list_timeout", "must not be negative")
+	v.check(c.MySQL.ReadYourWritesWindow >= 0, "mysql.read_your_writes_window", "must not be negative")
+	v.check(c.MySQL.MissConfirmRate >= 0, "mysql.miss_confirm_rate", "must not be negative")
+	v.check(c.MySQL.HealthCheckInterval > 0, "mysql.health_check_interval", "must be positive")
+	v.check(c.MySQL.HealthCheckTimeout > 0, "mysql.health_check_timeout", "must be positive")
+	v.check(c.MySQL.ShardStrategy == "hash" || c.MySQL.ShardStrategy == "prefix",
+		"mysql.shard_strategy", "must be one of `hash` and `prefix`, got %q", c.MySQL.ShardStrategy)
+
+	// redis
+	v.check(len(c.Redis.Addrs) > 0, "redis.addrs", "must not be empty")
+	for _, name := range slices.Sorted(maps.Keys(c.Redis.Addrs)) {
+		v.checkAddr(c.Redis.Addrs[name], fmt.Sprintf("redis.addrs[%s]", name))
+	}
+
+	// cache
+	v.check(c.Cache.Size > 0, "cache.size", "must be positive")
+	v.check(c.Cache.LocalTTL >= 0, "cache.local_ttl", "must not be negative")
+	v.check(c.Cache.SharedTTL >= 0, "cache.shared_ttl", "must not be negative")
+	v.check(c.Cache.NegativeSize > 0, "cache.negative_size", "must be positive")
+	v.check(c.Cache.NegativeLocalTTL >= 0, "cache.negative_local_ttl", "must not be negative")
+	v.check(c.Cache.NegativeSharedTTL >= 0, "cache.negative_shared_ttl", "must not be negative")
+
+	// click
+	v.check(c.Click.BufferSize > 0, "click.buffer_size", "must be positive")
+	v.check(c.Click.BatchSize > 0, "click.batch_size", "must be positive")
+	v.check(c.Click.FlushInterval > 0, "click.flush_interval", "must be positive")
+
+	// policy
+	_, err = policykit.NewMatcher(c.Policy.DenyHosts)
+	v.check(err == nil, "policy.deny_hosts", "%v", err)
+	if c.Policy.DenyHostsFile != "" {
+		_, err = os.Stat(c.Policy.DenyHostsFile)
+		v.check(err == nil, "policy.deny_hosts_file", "%v", err)
+	}
+	_, err = policykit.NewMatcher(c.Policy.OwnHosts)
+	v.check(err == nil, "policy.own_hosts", "%v", err)
+
+	// scan
+	v.check(c.Scan.Mode == "none" || c.Scan.Mode == "sync" || c.Scan.Mode == "async",
+		"scan.mode", "must be one of `none`, `sync` and `async`, got %q", c.Scan.Mode)
+	if c.Scan.Mode != "none" {
+		_, err = os.Stat(c.Scan.DBPath)
+		v.check(c.Scan.DBPath != "" && err == nil, "scan.db_path", "must be an existing file with the %s mode, got %q", c.Scan.Mode, c.Scan.DBPath)
+	}
+	v.check(c.Scan.Timeout > 0, "scan.timeout", "must be positive")
+	v.check(c.Scan.DBReloadInterval > 0, "scan.db_reload_interval", "must be positive")
+	v.check(c.Scan.RescanInterval >= 0, "scan.rescan_interval", "must not be negative")
+	v.check(c.Scan.PendingInterval >= 0, "scan.pending_interval", "must not be negative")
+	v.check(c.Scan.BatchSize > 0, "scan.batch_size", "must be positive")
+
+	// redirect
+	v.check(slices.Contains(redirectCodes, c.Redirect.Code), "redirect.code", "must be one of 301, 302, 307 and 308, got %d", c.Redirect.Code)
+	v.check(c.Redirect.MaxAge >= 0, "redirect.max_age", "must not be negative")
+
+	// interstitial
+	v.check(validInterstitial(c.Interstitial.Mode), "interstitial.mode", "must be one of `never`, `unverified` and `always`, got %q", c.Interstitial.Mode)
+	_, err = policykit.NewMatcher(c.Interstitial.AllowHosts)
+	v.check(err == nil, "interstitial.allow_hosts", "%v", err)
+	v.check(c.Interstitial.Delay >= 0, "interstitial.delay", "must not be negative")
+	if c.App.TemplateDir != "" {
+		info, err := os.Stat(c.App.TemplateDir)
+		v.check(err == nil && info.IsDir(), "app.template_dir", "must be an existing directory, got %q", c.App.TemplateDir)
+	}
+
+	// geo
+	if c.Geo.DBPath != "" {
+		_, err = os.Stat(c.Geo.DBPath)
+		v.check(err == nil, "geo.db_path", "%v", err)
+	}
+	v.check(c.Geo.DBReloadInterval > 0, "geo.db_reload_interval", "must be positive")
+	for i, cidr := range c.Geo.TrustedProxies {
+		_, err := geokit.ParsePrefix(cidr)
+		v.check(err == nil, fmt.Sprintf("geo.trusted_proxies[%d]", i), "must be an ip or a CIDR, got %q", cidr)
+	}
+
+	// split
+	v.check(c.Split.CookieSecret == "" || len(c.Split.CookieSecret) >= minCookieSecretLen, "split.cookie_secret",
+		"must be at least %d bytes", minCookieSecretLen)
+	v.check(c.Split.CookieMaxAge > 0, "split.cookie_max_age", "must be positive")
+
+	// tenants
+	for _, host := range slices.Sorted(maps.Keys(c.Tenants)) {
+		_, err := policykit.NormalizeHost(host)
+		v.check(err == nil, fmt.Sprintf("tenants[%s]", host), "must be a host, %v", err)
+		mode := c.Tenants[host].Interstitial
+		v.check(mode == "" || validInterstitial(mode), fmt.Sprintf("tenants[%s].interstitial", host),
+			"must be one of `never`, `unverified` and `always`, got %q", mode)
+		if fallback := c.Tenants[host].FallbackURL; fallback != "" {
+			u, err := url.Parse(fallback)
+			v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", fmt.Sprintf("tenants[%s].fallback_url", host),
+				"must be an http(s) url, got %q", fallback)
+		}
+	}
+
+	// trace
+	v.check(c.Trace.Exporter == "none" || c.Trace.Exporter == "stdout" || c.Trace.Exporter == "otlp",
+		"trace.exporter", "must be one of `otlp`, `stdout` and `none`, got %q", c.Trace.Exporter)
+	v.check(c.Trace.Exporter != "otlp" || c.Trace.OTLPEndpoint != "", "trace.otlp_endpoint", "must not be empty with the otlp exporter")
+	v.check(c.Trace.SampleRatio >= 0 && c.Trace.SampleRatio <= 1, "trace.sample_ratio", "must be within [0, 1]")
+
+	// log
+	var level slog.Level
+	v.check(level.UnmarshalText([]byte(c.Log.Level)) == nil, "log.level", "must be one of `debug`, `info`, `warn` and `error`, got %q", c.Log.Level)
+	v.check(c.Log.SampleRatio >= 0 && c.Log.SampleRatio <= 1, "log.sample_ratio", "must be within [0, 1]")
+
+	return errors.Join(v.errs...)
+}
+
+func validInterstitial(s string) bool {
+	return domain.Interstitial(s).IsValid()
+}
+
+func validPort(s string) bool {
+	port, err := strconv.Atoi(s)
+	return err == nil && port > 0 && port <= 65535
+}
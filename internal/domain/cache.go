@@ -1,5 +1,10 @@
 package domain
 
 const (
-	CACHE_PREFIX_SHORT_URL = "short_url/"
+	CACHE_PREFIX_SHORT_URL           = "short_url/"
+	CACHE_PREFIX_SHORT_URL_NOT_FOUND = "short_url_not_found/"
+	CACHE_PREFIX_PARAM_TEMPLATE      = "param_template/"
+
+	// CACHE_TOPIC_EVICT carries the keys deleted on an instance, which the other ones evict from their local cache
+	CACHE_TOPIC_EVICT = "short_url/evict"
 )
@@ -7,18 +7,199 @@ type CreateReqDto struct {
 	Url      string
 	TargetID string
 	ExpireAt time.Time
+	Status   LinkStatus
+	// APIKey identifies the creator, which is optional and only its hash is stored as the Creator
+	APIKey  string
+	Creator string
+	// Interstitial overrides the interstitial mode of the tenant for the link, which is inherited if empty
+	Interstitial Interstitial
+	// RedirectCode is one of 301, 302, 307 and 308, the default one is used if it's 0
+	RedirectCode int
+	// Passthrough forwards the path and the query after the id to the destination, which is off if empty
+	Passthrough Passthrough
+	// Template is the name of the param template appended to the destination, which is optional
+	Template string
+	// Rules send the visitors of the platforms to their own destinations, the first matched one wins
+	Rules []RedirectRule
+	// GeoRules send the visitors located in the countries or the continents to their own destinations,
+	// which are matched after Rules and the first matched one wins
+	GeoRules []GeoRule
+	// Variants split the visitors matching none of the rules across the destinations by weight instead of the url
+	Variants []Variant
 }
 
 type CreateRespDto struct {
 	TargetID string
 	ShortUrl string
+	Status   LinkStatus
 }
 
-// ENUM(Normal, NotFound, Expired)
+// ENUM(Normal, NotFound, Expired, Disabled, PendingReview)
 type GetRespStatus string
 
+// LinkStatus is the status of the short url managed by the operators and the scanner.
+// The PendingReview ones are redirected after the destinations are scanned.
+// ENUM(Active, Disabled, PendingReview)
+type LinkStatus string
+
+// Interstitial decides whether the redirect shows the warning page of the destination host before leaving.
+// The unverified ones show it unless the destination host is allowlisted.
+// ENUM(never, unverified, always)
+type Interstitial string
+
+// Passthrough forwards the path and the query after the id to the destination,
+// the value decides the query parameters existing in both:
+// keep the ones of the destination, override them by the ones of the request, or append the latter to the former.
+// ENUM(keep, override, append)
+type Passthrough string
+
+// Platform is what the redirect rules match on the User-Agent of the visitors.
+// The mobile ones are iOS and Android, and the desktop ones are Windows, macOS and Linux.
+// ENUM(ios, android, mobile, windows, macos, linux, desktop)
+type Platform string
+
+// RedirectRule sends the visitors of the platform to the url rather than the one of the short url
+type RedirectRule struct {
+	Platform Platform `json:"platform"`
+	Url      string   `json:"url"`
+}
+
+// Continent is the two-letter code of the continents in the GeoIP databases
+// ENUM(AF, AN, AS, EU, NA, OC, SA)
+type Continent string
+
+// GeoRule sends the visitors located in the country or the continent to the url rather than the one of the short url.
+// Only one of Country, the ISO 3166-1 alpha-2 code e.g. TW, and Continent is set.
+type GeoRule struct {
+	Country   string    `json:"country,omitempty"`
+	Continent Continent `json:"continent,omitempty"`
+	Url       string    `json:"url"`
+}
+
+// Variant is one of the destinations the split link sends the visitors to, in proportion to its weight among them
+type Variant struct {
+	Url    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
 type GetRespDto struct {
-	Status   GetRespStatus
-	Url      string
-	ExpireAt time.Time
+	Status       GetRespStatus
+	Url          string
+	ExpireAt     time.Time
+	LinkStatus   LinkStatus   `json:",omitempty"`
+	Interstitial Interstitial `json:",omitempty"`
+	RedirectCode int          `json:",omitempty"`
+	Passthrough  Passthrough  `json:",omitempty"`
+	Template     string       `json:",omitempty"`
+	// Rules and GeoRules are cached along with the url, which are matched in order on redirecting
+	Rules    []RedirectRule `json:",omitempty"`
+	GeoRules []GeoRule      `json:",omitempty"`
+	Variants []Variant      `json:",omitempty"`
+	// CreatedAt is shown on the preview, which is zero in the copies cached before it's added
+	CreatedAt time.Time `json:",omitempty"`
+}
+
+// CacheExpiry implements cachekit.Expirer, the cached copy is useless once the link expires
+func (dto *GetRespDto) CacheExpiry() time.Time {
+	return dto.ExpireAt
+}
+
+type ListReqDto struct {
+	Url   string
+	Limit int
+}
+
+// ListByStatusReqDto pages through the unexpired short urls of the statuses by the id
+type ListByStatusReqDto struct {
+	Statuses []LinkStatus
+	// AfterID is the last id of the previous page
+	AfterID string
+	Limit   int
+}
+
+type ShortUrlDto struct {
+	TargetID     string
+	Url          string
+	Status       LinkStatus
+	Creator      string
+	Interstitial Interstitial
+	RedirectCode int
+	Passthrough  Passthrough
+	Template     string
+	Rules        []RedirectRule
+	GeoRules     []GeoRule
+	Variants     []Variant
+	ExpireAt     time.Time
+	CreatedAt    time.Time
+}
+
+// UpdateReqDto updates the fields which are not nil
+type UpdateReqDto struct {
+	Status   *LinkStatus
+	ExpireAt *time.Time
+}
+
+type ClickDto struct {
+	TargetID string
+	// ClickID identifies the click in the params rendered by the template, which is empty without the template
+	ClickID string
+	// Rule is the 1-based position of the redirect rule matched, which is 0 for the url of the short url
+	Rule int
+	// Variant is the 1-based position of the variant the visitor is assigned to, which is 0 if a rule or the url is used
+	Variant   int
+	Referer   string
+	UserAgent string
+	ClickedAt time.Time
+}
+
+// ReportStatus is the status of an abuse report, which is Pending until the operators resolve it
+// ENUM(Pending, Dismissed, Disabled, Banned)
+type ReportStatus string
+
+// VariantStatsDto is the clicks of the short url broken down by the variant.
+// Variant 0 is the clicks sent to the url or the rules of the short url.
+type VariantStatsDto struct {
+	Variant int
+	Url     string
+	Weight  int
+	Clicks  int64
+}
+
+// ReportAction is how the operators resolve the pending reports of a short url.
+// Disable disables the short url, and Ban bans the API key of its creator as well.
+// ENUM(Dismiss, Disable, Ban)
+type ReportAction string
+
+type ReportDto struct {
+	ID         uint64
+	TargetID   string
+	Reason     string
+	Contact    string
+	Status     ReportStatus
+	CreatedAt  time.Time
+	ResolvedAt *time.Time
+}
+
+type ListReportsReqDto struct {
+	Status ReportStatus
+	Limit  int
+}
+
+// ParamTemplateDto is the query appended to the destinations of the short urls attached to it by the name.
+// The values can be the placeholders of ParamTemplateData in text/template, e.g. `utm_source=newsletter&utm_campaign={{.Date}}`.
+type ParamTemplateDto struct {
+	Name      string
+	Query     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ParamTemplateData are the placeholders of the param templates
+type ParamTemplateData struct {
+	// ID is the id of the short url
+	ID string
+	// Date is the date of the click in UTC, e.g. 2025-02-10
+	Date string
+	// ClickID identifies the click, which is recorded with it
+	ClickID string
 }
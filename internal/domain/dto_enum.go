@@ -11,6 +11,70 @@ import (
 	"fmt"
 )
 
+const (
+	// ContinentAF is a Continent of type AF.
+	ContinentAF Continent = "AF"
+	// ContinentAN is a Continent of type AN.
+	ContinentAN Continent = "AN"
+	// ContinentAS is a Continent of type AS.
+	ContinentAS Continent = "AS"
+	// ContinentEU is a Continent of type EU.
+	ContinentEU Continent = "EU"
+	// ContinentNA is a Continent of type NA.
+	ContinentNA Continent = "NA"
+	// ContinentOC is a Continent of type OC.
+	ContinentOC Continent = "OC"
+	// ContinentSA is a Continent of type SA.
+	ContinentSA Continent = "SA"
+)
+
+var ErrInvalidContinent = errors.New("not a valid Continent")
+
+// String implements the Stringer interface.
+func (x Continent) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x Continent) IsValid() bool {
+	_, err := ParseContinent(string(x))
+	return err == nil
+}
+
+var _ContinentValue = map[string]Continent{
+	"AF": ContinentAF,
+	"AN": ContinentAN,
+	"AS": ContinentAS,
+	"EU": ContinentEU,
+	"NA": ContinentNA,
+	"OC": ContinentOC,
+	"SA": ContinentSA,
+}
+
+// ParseContinent attempts to convert a string to a Continent.
+func ParseContinent(name string) (Continent, error) {
+	if x, ok := _ContinentValue[name]; ok {
+		return x, nil
+	}
+	return Continent(""), fmt.Errorf("%s is %w", name, ErrInvalidContinent)
+}
+
+// MarshalText implements the text marshaller method.
+func (x Continent) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *Continent) UnmarshalText(text []byte) error {
+	tmp, err := ParseContinent(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
 const (
 	// GetRespStatusNormal is a GetRespStatus of type Normal.
 	GetRespStatusNormal GetRespStatus = "Normal"
@@ -18,6 +82,10 @@ const (
 	GetRespStatusNotFound GetRespStatus = "NotFound"
 	// GetRespStatusExpired is a GetRespStatus of type Expired.
 	GetRespStatusExpired GetRespStatus = "Expired"
+	// GetRespStatusDisabled is a GetRespStatus of type Disabled.
+	GetRespStatusDisabled GetRespStatus = "Disabled"
+	// GetRespStatusPendingReview is a GetRespStatus of type PendingReview.
+	GetRespStatusPendingReview GetRespStatus = "PendingReview"
 )
 
 var ErrInvalidGetRespStatus = errors.New("not a valid GetRespStatus")
@@ -35,9 +103,11 @@ func (x GetRespStatus) IsValid() bool {
 }
 
 var _GetRespStatusValue = map[string]GetRespStatus{
-	"Normal":   GetRespStatusNormal,
-	"NotFound": GetRespStatusNotFound,
-	"Expired":  GetRespStatusExpired,
+	"Normal":        GetRespStatusNormal,
+	"NotFound":      GetRespStatusNotFound,
+	"Expired":       GetRespStatusExpired,
+	"Disabled":      GetRespStatusDisabled,
+	"PendingReview": GetRespStatusPendingReview,
 }
 
 // ParseGetRespStatus attempts to convert a string to a GetRespStatus.
@@ -62,3 +132,330 @@ func (x *GetRespStatus) UnmarshalText(text []byte) error {
 	*x = tmp
 	return nil
 }
+
+const (
+	// InterstitialNever is a Interstitial of type never.
+	InterstitialNever Interstitial = "never"
+	// InterstitialUnverified is a Interstitial of type unverified.
+	InterstitialUnverified Interstitial = "unverified"
+	// InterstitialAlways is a Interstitial of type always.
+	InterstitialAlways Interstitial = "always"
+)
+
+var ErrInvalidInterstitial = errors.New("not a valid Interstitial")
+
+// String implements the Stringer interface.
+func (x Interstitial) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x Interstitial) IsValid() bool {
+	_, err := ParseInterstitial(string(x))
+	return err == nil
+}
+
+var _InterstitialValue = map[string]Interstitial{
+	"never":      InterstitialNever,
+	"unverified": InterstitialUnverified,
+	"always":     InterstitialAlways,
+}
+
+// ParseInterstitial attempts to convert a string to a Interstitial.
+func ParseInterstitial(name string) (Interstitial, error) {
+	if x, ok := _InterstitialValue[name]; ok {
+		return x, nil
+	}
+	return Interstitial(""), fmt.Errorf("%s is %w", name, ErrInvalidInterstitial)
+}
+
+// MarshalText implements the text marshaller method.
+func (x Interstitial) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *Interstitial) UnmarshalText(text []byte) error {
+	tmp, err := ParseInterstitial(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// LinkStatusActive is a LinkStatus of type Active.
+	LinkStatusActive LinkStatus = "Active"
+	// LinkStatusDisabled is a LinkStatus of type Disabled.
+	LinkStatusDisabled LinkStatus = "Disabled"
+	// LinkStatusPendingReview is a LinkStatus of type PendingReview.
+	LinkStatusPendingReview LinkStatus = "PendingReview"
+)
+
+var ErrInvalidLinkStatus = errors.New("not a valid LinkStatus")
+
+// String implements the Stringer interface.
+func (x LinkStatus) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x LinkStatus) IsValid() bool {
+	_, err := ParseLinkStatus(string(x))
+	return err == nil
+}
+
+var _LinkStatusValue = map[string]LinkStatus{
+	"Active":        LinkStatusActive,
+	"Disabled":      LinkStatusDisabled,
+	"PendingReview": LinkStatusPendingReview,
+}
+
+// ParseLinkStatus attempts to convert a string to a LinkStatus.
+func ParseLinkStatus(name string) (LinkStatus, error) {
+	if x, ok := _LinkStatusValue[name]; ok {
+		return x, nil
+	}
+	return LinkStatus(""), fmt.Errorf("%s is %w", name, ErrInvalidLinkStatus)
+}
+
+// MarshalText implements the text marshaller method.
+func (x LinkStatus) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *LinkStatus) UnmarshalText(text []byte) error {
+	tmp, err := ParseLinkStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// PassthroughKeep is a Passthrough of type keep.
+	PassthroughKeep Passthrough = "keep"
+	// PassthroughOverride is a Passthrough of type override.
+	PassthroughOverride Passthrough = "override"
+	// PassthroughAppend is a Passthrough of type append.
+	PassthroughAppend Passthrough = "append"
+)
+
+var ErrInvalidPassthrough = errors.New("not a valid Passthrough")
+
+// String implements the Stringer interface.
+func (x Passthrough) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x Passthrough) IsValid() bool {
+	_, err := ParsePassthrough(string(x))
+	return err == nil
+}
+
+var _PassthroughValue = map[string]Passthrough{
+	"keep":     PassthroughKeep,
+	"override": PassthroughOverride,
+	"append":   PassthroughAppend,
+}
+
+// ParsePassthrough attempts to convert a string to a Passthrough.
+func ParsePassthrough(name string) (Passthrough, error) {
+	if x, ok := _PassthroughValue[name]; ok {
+		return x, nil
+	}
+	return Passthrough(""), fmt.Errorf("%s is %w", name, ErrInvalidPassthrough)
+}
+
+// MarshalText implements the text marshaller method.
+func (x Passthrough) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *Passthrough) UnmarshalText(text []byte) error {
+	tmp, err := ParsePassthrough(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// PlatformIos is a Platform of type ios.
+	PlatformIos Platform = "ios"
+	// PlatformAndroid is a Platform of type android.
+	PlatformAndroid Platform = "android"
+	// PlatformMobile is a Platform of type mobile.
+	PlatformMobile Platform = "mobile"
+	// PlatformWindows is a Platform of type windows.
+	PlatformWindows Platform = "windows"
+	// PlatformMacos is a Platform of type macos.
+	PlatformMacos Platform = "macos"
+	// PlatformLinux is a Platform of type linux.
+	PlatformLinux Platform = "linux"
+	// PlatformDesktop is a Platform of type desktop.
+	PlatformDesktop Platform = "desktop"
+)
+
+var ErrInvalidPlatform = errors.New("not a valid Platform")
+
+// String implements the Stringer interface.
+func (x Platform) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x Platform) IsValid() bool {
+	_, err := ParsePlatform(string(x))
+	return err == nil
+}
+
+var _PlatformValue = map[string]Platform{
+	"ios":     PlatformIos,
+	"android": PlatformAndroid,
+	"mobile":  PlatformMobile,
+	"windows": PlatformWindows,
+	"macos":   PlatformMacos,
+	"linux":   PlatformLinux,
+	"desktop": PlatformDesktop,
+}
+
+// ParsePlatform attempts to convert a string to a Platform.
+func ParsePlatform(name string) (Platform, error) {
+	if x, ok := _PlatformValue[name]; ok {
+		return x, nil
+	}
+	return Platform(""), fmt.Errorf("%s is %w", name, ErrInvalidPlatform)
+}
+
+// MarshalText implements the text marshaller method.
+func (x Platform) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *Platform) UnmarshalText(text []byte) error {
+	tmp, err := ParsePlatform(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// ReportActionDismiss is a ReportAction of type Dismiss.
+	ReportActionDismiss ReportAction = "Dismiss"
+	// ReportActionDisable is a ReportAction of type Disable.
+	ReportActionDisable ReportAction = "Disable"
+	// ReportActionBan is a ReportAction of type Ban.
+	ReportActionBan ReportAction = "Ban"
+)
+
+var ErrInvalidReportAction = errors.New("not a valid ReportAction")
+
+// String implements the Stringer interface.
+func (x ReportAction) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x ReportAction) IsValid() bool {
+	_, err := ParseReportAction(string(x))
+	return err == nil
+}
+
+var _ReportActionValue = map[string]ReportAction{
+	"Dismiss": ReportActionDismiss,
+	"Disable": ReportActionDisable,
+	"Ban":     ReportActionBan,
+}
+
+// ParseReportAction attempts to convert a string to a ReportAction.
+func ParseReportAction(name string) (ReportAction, error) {
+	if x, ok := _ReportActionValue[name]; ok {
+		return x, nil
+	}
+	return ReportAction(""), fmt.Errorf("%s is %w", name, ErrInvalidReportAction)
+}
+
+// MarshalText implements the text marshaller method.
+func (x ReportAction) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *ReportAction) UnmarshalText(text []byte) error {
+	tmp, err := ParseReportAction(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+const (
+	// ReportStatusPending is a ReportStatus of type Pending.
+	ReportStatusPending ReportStatus = "Pending"
+	// ReportStatusDismissed is a ReportStatus of type Dismissed.
+	ReportStatusDismissed ReportStatus = "Dismissed"
+	// ReportStatusDisabled is a ReportStatus of type Disabled.
+	ReportStatusDisabled ReportStatus = "Disabled"
+	// ReportStatusBanned is a ReportStatus of type Banned.
+	ReportStatusBanned ReportStatus = "Banned"
+)
+
+var ErrInvalidReportStatus = errors.New("not a valid ReportStatus")
+
+// String implements the Stringer interface.
+func (x ReportStatus) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x ReportStatus) IsValid() bool {
+	_, err := ParseReportStatus(string(x))
+	return err == nil
+}
+
+var _ReportStatusValue = map[string]ReportStatus{
+	"Pending":   ReportStatusPending,
+	"Dismissed": ReportStatusDismissed,
+	"Disabled":  ReportStatusDisabled,
+	"Banned":    ReportStatusBanned,
+}
+
+// ParseReportStatus attempts to convert a string to a ReportStatus.
+func ParseReportStatus(name string) (ReportStatus, error) {
+	if x, ok := _ReportStatusValue[name]; ok {
+		return x, nil
+	}
+	return ReportStatus(""), fmt.Errorf("%s is %w", name, ErrInvalidReportStatus)
+}
+
+// MarshalText implements the text marshaller method.
+func (x ReportStatus) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *ReportStatus) UnmarshalText(text []byte) error {
+	tmp, err := ParseReportStatus(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
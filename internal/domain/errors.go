@@ -3,7 +3,14 @@ package domain
 import "errors"
 
 var (
-	ErrDuplicatedKey  = errors.New("duplicated key")
-	ErrExpired        = errors.New("expired")
-	ErrRecordNotFound = errors.New("record not found")
+	ErrCreatorBanned   = errors.New("creator banned")
+	ErrDuplicatedKey   = errors.New("duplicated key")
+	ErrExpired         = errors.New("expired")
+	ErrInvalidRule     = errors.New("invalid redirect rule")
+	ErrInvalidTemplate = errors.New("invalid template")
+	ErrInvalidVariant  = errors.New("invalid variant")
+	ErrNoCreator       = errors.New("no creator")
+	ErrRecordNotFound  = errors.New("record not found")
+	ErrTimeout         = errors.New("timeout")
+	ErrUnknownTemplate = errors.New("unknown template")
 )
@@ -0,0 +1,112 @@
+// Package metrics defines the Prometheus collectors of the service.
+package metrics
+
+import (
+	"github.com/Hao1995/short-url/pkg/cachekit"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "short_url"
+
+var (
+	// HTTPRequests counts the requests by the method, the route and the status code
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "The number of HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes the latency of the requests by the method, the route and the status code
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "The latency of HTTP requests by method, route and status.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"method", "route", "status"})
+
+	// CacheLookups counts the cache lookups by the cache prefix and the result (hit or miss).
+	// The hit ratio of the positive and the negative cache is hit / (hit + miss) of each prefix.
+	// The misses of the negative cache are the ids not found only, rather than all the ids probed in it.
+	CacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "The number of cache lookups by prefix and result.",
+	}, []string{"prefix", "result"})
+
+	// CacheTierLookups counts the lookups of each cache tier. The result is hit or miss for the local and the shared tier,
+	// and success or error for the loader.
+	CacheTierLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_tier_lookups_total",
+		Help:      "The number of lookups by cache prefix, tier and result.",
+	}, []string{"prefix", "tier", "result"})
+
+	// CreateCollisions counts the retries of creating a short url due to the duplicated id
+	CreateCollisions = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "create_collisions_total",
+		Help:      "The number of retries of creating a short url due to id collision.",
+	})
+
+	// URLRejections counts the urls rejected on creating the short urls by the reason
+	URLRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "url_rejections_total",
+		Help:      "The number of urls rejected by reason.",
+	}, []string{"reason"})
+
+	// Scans counts the destinations scanned against the threat lists by the result, which is clean, flagged or error
+	Scans = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scans_total",
+		Help:      "The number of urls scanned by result.",
+	}, []string{"result"})
+
+	// Reports counts the abuse reports by the status, which is Pending on reported, or the status resolved to
+	Reports = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reports_total",
+		Help:      "The number of abuse reports by status.",
+	}, []string{"status"})
+
+	// MissConfirmations counts the misses of the replicas confirmed on the primary by the result, which is found,
+	// not_found, error or skipped (over mysql.miss_confirm_rate). The ones found are the links the replicas lag behind.
+	MissConfirmations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "replica_miss_confirmations_total",
+		Help:      "The number of replica misses confirmed on the primary by result.",
+	}, []string{"result"})
+
+	// Clicks counts the clicks by the result of recording them, which is stored, dropped (the buffer is full) or failed
+	Clicks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "clicks_total",
+		Help:      "The number of clicks by the result of recording.",
+	}, []string{"result"})
+)
+
+// OnCacheHit is the callback of cachekit on cache hitted
+func OnCacheHit(prefix string, key string, count int) {
+	CacheLookups.WithLabelValues(prefix, "hit").Add(float64(count))
+}
+
+// OnCacheMiss is the callback of cachekit on cache missed
+func OnCacheMiss(prefix string, key string, count int) {
+	CacheLookups.WithLabelValues(prefix, "miss").Add(float64(count))
+}
+
+// OnCacheTierLookup is the callback of cachekit on looking up each tier
+func OnCacheTierLookup(prefix string, tier cachekit.Tier, ok bool) {
+	result := "miss"
+	switch {
+	case tier == cachekit.TierLoader && ok:
+		result = "success"
+	case tier == cachekit.TierLoader:
+		result = "error"
+	case ok:
+		result = "hit"
+	}
+	CacheTierLookups.WithLabelValues(prefix, string(tier), result).Inc()
+}
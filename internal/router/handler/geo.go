@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/router/handler/request"
+	"github.com/Hao1995/short-url/pkg/geokit"
+	"github.com/Hao1995/short-url/pkg/logkit"
+)
+
+// Geo locates the visitors for the geo rules by the client ip behind the trusted proxies
+type Geo struct {
+	db      *geokit.DB
+	proxies *geokit.Proxies
+}
+
+// NewGeo generates the locator of the visitors, the database and the proxies can be replaced at runtime
+func NewGeo(db *geokit.DB, proxies *geokit.Proxies) *Geo {
+	return &Geo{
+		db:      db,
+		proxies: proxies,
+	}
+}
+
+// Enabled reports whether the visitors can be located, the links with the geo rules are rejected otherwise
+// since their rules would never be matched
+func (g *Geo) Enabled() bool {
+	return g.db.Loaded()
+}
+
+// Locate returns the location of the visitor, which is empty if it's unknown
+func (g *Geo) Locate(ctx context.Context, r *http.Request) geokit.Location {
+	addr := g.proxies.ClientIP(r)
+	location, err := g.db.Lookup(addr)
+	if err != nil {
+		logkit.Sampled().WarnContext(ctx, "handler.Geo.Locate. failed to look up the client ip", "ip", addr.String(), logkit.Err(err))
+	}
+	return location
+}
+
+// matchGeoRule returns the first rule matching the country or the continent, or nil if none of them does
+func matchGeoRule(rules []domain.GeoRule, location geokit.Location) *domain.GeoRule {
+	for i, rule := range rules {
+		if (rule.Country != "" && rule.Country == location.Country) ||
+			(rule.Continent != "" && rule.Continent.String() == location.Continent) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// geoRules converts the geo rules of the request to the domain ones in order
+func geoRules(reqs []request.GeoRuleRequest) []domain.GeoRule {
+	if len(reqs) == 0 {
+		return nil
+	}
+	rules := make([]domain.GeoRule, len(reqs))
+	for i, req := range reqs {
+		rules[i] = domain.GeoRule{Country: req.Country, Continent: domain.Continent(req.Continent), Url: req.Url}
+	}
+	return rules
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Hao1995/short-url/pkg/logkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HealthStatusUp           = "up"
+	HealthStatusDown         = "down"
+	HealthStatusShuttingDown = "shutting_down"
+)
+
+// Pinger checks whether a dependency is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// PingerFunc adapts a function to Pinger
+type PingerFunc func(ctx context.Context) error
+
+func (f PingerFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+type dependency struct {
+	name   string
+	pinger Pinger
+}
+
+type HealthHandler struct {
+	timeout      time.Duration
+	deps         []dependency
+	shuttingDown atomic.Bool
+}
+
+// NewHealthHandler generates the handler of the liveness and the readiness probes.
+// Each dependency is pinged within the timeout on the readiness probe.
+func NewHealthHandler(timeout time.Duration) *HealthHandler {
+	return &HealthHandler{
+		timeout: timeout,
+	}
+}
+
+// Register adds the dependency checked by the readiness probe, it's not safe to call after serving
+func (hlr *HealthHandler) Register(name string, pinger Pinger) {
+	hlr.deps = append(hlr.deps, dependency{name: name, pinger: pinger})
+}
+
+// Shutdown turns the readiness probe not ready, so that the load balancers drain the instance before it exits
+func (hlr *HealthHandler) Shutdown() {
+	hlr.shuttingDown.Store(true)
+}
+
+// Liveness responds as long as the process is able to serve
+func (hlr *HealthHandler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": HealthStatusUp})
+}
+
+// Readiness responds whether the instance is able to serve the requests, with the status of each dependency.
+// The errors are logged rather than responded, since the probe is reachable from the public.
+func (hlr *HealthHandler) Readiness(c *gin.Context) {
+	if hlr.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": HealthStatusShuttingDown})
+		return
+	}
+
+	ctx := c.Request.Context()
+	statuses := make([]string, len(hlr.deps))
+	var wg sync.WaitGroup
+	for i, dep := range hlr.deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, hlr.timeout)
+			defer cancel()
+
+			statuses[i] = HealthStatusUp
+			if err := dep.pinger.Ping(ctx); err != nil {
+				slog.WarnContext(ctx, "handler.Readiness. dependency is down", "dependency", dep.name, logkit.Err(err))
+				statuses[i] = HealthStatusDown
+			}
+		}()
+	}
+	wg.Wait()
+
+	code, status := http.StatusOK, HealthStatusUp
+	deps := gin.H{}
+	for i, dep := range hlr.deps {
+		deps[dep.name] = gin.H{"status": statuses[i]}
+		if statuses[i] != HealthStatusUp {
+			code, status = http.StatusServiceUnavailable, HealthStatusDown
+		}
+	}
+	c.JSON(code, gin.H{"status": status, "dependencies": deps})
+}
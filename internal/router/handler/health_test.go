@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mocks "github.com/Hao1995/short-url/mocks/internal_/router/handler"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthHandlerTestSuite struct {
+	suite.Suite
+	ginEngine *gin.Engine
+
+	mysql *mocks.Pinger
+	redis *mocks.Pinger
+	impl  *HealthHandler
+}
+
+func TestHealthHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthHandlerTestSuite))
+}
+
+func (s *HealthHandlerTestSuite) SetupSubTest() {
+	s.mysql = mocks.NewPinger(s.T())
+	s.redis = mocks.NewPinger(s.T())
+	s.impl = NewHealthHandler(100 * time.Millisecond)
+	s.impl.Register("mysql_shard0", s.mysql)
+	s.impl.Register("redis", s.redis)
+
+	r := gin.New()
+	r.GET("/healthz", s.impl.Liveness)
+	r.GET("/readyz", s.impl.Readiness)
+	s.ginEngine = r
+}
+
+func (s *HealthHandlerTestSuite) TestLiveness() {
+	s.Suite.Run("alive regardless of the dependencies", func() {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/healthz", nil)
+		s.ginEngine.ServeHTTP(w, req)
+
+		s.Equal(http.StatusOK, w.Code)
+		s.JSONEq(`{"status":"up"}`, w.Body.String())
+	})
+}
+
+func (s *HealthHandlerTestSuite) TestReadiness() {
+	for _, t := range []struct {
+		name    string
+		setup   func()
+		expCode int
+		expResp string
+	}{
+		{
+			name: "ready when all the dependencies are up",
+			setup: func() {
+				s.mysql.On("Ping", mock.Anything).Once().Return(nil)
+				s.redis.On("Ping", mock.Anything).Once().Return(nil)
+			},
+			expCode: http.StatusOK,
+			expResp: `{"status":"up","dependencies":{"mysql_shard0":{"status":"up"},"redis":{"status":"up"}}}`,
+		},
+		{
+			name: "not ready when one of the dependencies is down",
+			setup: func() {
+				s.mysql.On("Ping", mock.Anything).Once().Return(nil)
+				s.redis.On("Ping", mock.Anything).Once().Return(errors.New("connection refused"))
+			},
+			expCode: http.StatusServiceUnavailable,
+			expResp: `{"status":"down","dependencies":{"mysql_shard0":{"status":"up"},"redis":{"status":"down"}}}`,
+		},
+		{
+			name: "ping the dependencies within the timeout",
+			setup: func() {
+				s.mysql.On("Ping", mock.Anything).Once().Return(func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				})
+				s.redis.On("Ping", mock.Anything).Once().Return(nil)
+			},
+			expCode: http.StatusServiceUnavailable,
+			expResp: `{"status":"down","dependencies":{"mysql_shard0":{"status":"down"},"redis":{"status":"up"}}}`,
+		},
+		{
+			name: "not ready while shutting down",
+			setup: func() {
+				s.impl.Shutdown()
+			},
+			expCode: http.StatusServiceUnavailable,
+			expResp: `{"status":"shutting_down"}`,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/readyz", nil)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.JSONEq(t.expResp, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/pkg/policykit"
+)
+
+// InterstitialRules decides which redirects show the interstitial page.
+// The mode of the link overrides the one of the tenant, which overrides Mode.
+type InterstitialRules struct {
+	Mode domain.Interstitial
+	// Tenants are the modes by the host serving the short urls
+	Tenants map[string]domain.Interstitial
+	// AllowHosts are the host patterns of the verified destinations, see policykit.Matcher for the syntax
+	AllowHosts []string
+	// Delay is the countdown before redirecting automatically, 0 waits for the visitors to continue
+	Delay time.Duration
+}
+
+type compiledInterstitial struct {
+	mode    domain.Interstitial
+	tenants map[string]domain.Interstitial
+	allow   *policykit.Matcher
+	delay   time.Duration
+}
+
+// Interstitial decides whether the redirect shows the interstitial page, whose rules can be replaced at runtime
+type Interstitial struct {
+	compiled atomic.Pointer[compiledInterstitial]
+}
+
+// NewInterstitial compiles the rules
+func NewInterstitial(rules InterstitialRules) (*Interstitial, error) {
+	i := &Interstitial{}
+	if err := i.SetRules(rules); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// SetRules compiles the rules and replaces the current ones, which are kept if the new ones are invalid
+func (i *Interstitial) SetRules(rules InterstitialRules) error {
+	allow, err := policykit.NewMatcher(rules.AllowHosts)
+	if err != nil {
+		return fmt.Errorf("allow hosts: %w", err)
+	}
+	tenants := make(map[string]domain.Interstitial, len(rules.Tenants))
+	for host, mode := range rules.Tenants {
+		normalized, err := policykit.NormalizeHost(host)
+		if err != nil {
+			return fmt.Errorf("tenant %q: %w", host, err)
+		}
+		tenants[normalized] = mode
+	}
+	i.compiled.Store(&compiledInterstitial{
+		mode:    rules.Mode,
+		tenants: tenants,
+		allow:   allow,
+		delay:   rules.Delay,
+	})
+	return nil
+}
+
+// Show reports whether the link of the mode served by the tenant host shows the interstitial page before leaving for dest
+func (i *Interstitial) Show(tenant string, link domain.Interstitial, dest *url.URL) bool {
+	compiled := i.compiled.Load()
+	mode := link
+	if mode == "" {
+		if host, ok := tenantHost(tenant); ok {
+			mode = compiled.tenants[host]
+		}
+	}
+	if mode == "" {
+		mode = compiled.mode
+	}
+
+	switch mode {
+	case domain.InterstitialAlways:
+		return true
+	case domain.InterstitialUnverified:
+		host, err := policykit.NormalizeHost(dest.Hostname())
+		if err != nil {
+			return true
+		}
+		_, ok := compiled.allow.Match(host)
+		return !ok
+	default:
+		return false
+	}
+}
+
+// Delay returns the countdown before redirecting automatically
+func (i *Interstitial) Delay() time.Duration {
+	return i.compiled.Load().delay
+}
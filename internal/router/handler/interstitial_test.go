@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"bytes"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Hao1995/short-url/internal/domain"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type InterstitialTestSuite struct {
+	suite.Suite
+}
+
+func TestInterstitialTestSuite(t *testing.T) {
+	suite.Run(t, new(InterstitialTestSuite))
+}
+
+func (s *InterstitialTestSuite) TestShow() {
+	impl, err := NewInterstitial(InterstitialRules{
+		Mode: domain.InterstitialUnverified,
+		Tenants: map[string]domain.Interstitial{
+			"Go.Example.com": domain.InterstitialAlways,
+			"trusted.sho.rt": domain.InterstitialNever,
+		},
+		AllowHosts: []string{"example.com", "*.example.com"},
+	})
+	s.Require().NoError(err)
+
+	for _, t := range []struct {
+		name   string
+		tenant string
+		link   domain.Interstitial
+		dest   string
+		exp    bool
+	}{
+		{
+			name:   "skip the allowlisted destination",
+			tenant: "sho.rt",
+			dest:   "https://www.Example.com/whatever1",
+			exp:    false,
+		},
+		{
+			name:   "show the unverified destination",
+			tenant: "sho.rt",
+			dest:   "https://example.org/whatever1",
+			exp:    true,
+		},
+		{
+			name:   "follow the tenant with the port",
+			tenant: "go.example.com:8080",
+			dest:   "https://www.example.com/whatever1",
+			exp:    true,
+		},
+		{
+			name:   "follow the tenant never showing it",
+			tenant: "trusted.sho.rt",
+			dest:   "https://example.org/whatever1",
+			exp:    false,
+		},
+		{
+			name:   "follow the link over the tenant",
+			tenant: "go.example.com",
+			link:   domain.InterstitialNever,
+			dest:   "https://example.org/whatever1",
+			exp:    false,
+		},
+		{
+			name:   "follow the link over the mode",
+			tenant: "sho.rt",
+			link:   domain.InterstitialAlways,
+			dest:   "https://example.com/whatever1",
+			exp:    true,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			dest, err := url.Parse(t.dest)
+			s.Require().NoError(err)
+			s.Equal(t.exp, impl.Show(t.tenant, t.link, dest))
+		})
+	}
+}
+
+func (s *InterstitialTestSuite) TestSetRules() {
+	impl, err := NewInterstitial(InterstitialRules{Mode: domain.InterstitialAlways})
+	s.Require().NoError(err)
+
+	s.Error(impl.SetRules(InterstitialRules{Mode: domain.InterstitialNever, AllowHosts: []string{"/[/"}}))
+	s.True(impl.Show("sho.rt", "", &url.URL{Host: "example.com"}), "keep the rules if the new ones are invalid")
+
+	s.NoError(impl.SetRules(InterstitialRules{Mode: domain.InterstitialNever}))
+	s.False(impl.Show("sho.rt", "", &url.URL{Host: "example.com"}))
+}
+
+func (s *InterstitialTestSuite) TestLoadTemplates() {
+	s.Suite.Run("override the embedded templates of the same names", func() {
+		dir := s.T().TempDir()
+		s.Require().NoError(os.WriteFile(filepath.Join(dir, "removed.html"), []byte("gone: {{.ID}}"), 0o644))
+
+		tmpl, err := LoadTemplates(dir)
+		s.Require().NoError(err)
+
+		var buf bytes.Buffer
+		s.Require().NoError(tmpl.ExecuteTemplate(&buf, "removed.html", map[string]string{"ID": "testid1"}))
+		s.Equal("gone: testid1", buf.String())
+		s.NotNil(tmpl.Lookup("interstitial.html"))
+	})
+	s.Suite.Run("reject the unknown templates", func() {
+		dir := s.T().TempDir()
+		s.Require().NoError(os.WriteFile(filepath.Join(dir, "whatever.html"), []byte("whatever"), 0o644))
+
+		_, err := LoadTemplates(dir)
+		s.Error(err)
+	})
+}
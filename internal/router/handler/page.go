@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// STATIC_PATH serves the assets of the pages, which never collides with the short url ids
+const STATIC_PATH = "/-/static"
+
+// contentSecurityPolicy allows nothing but the assets under STATIC_PATH, so the pages carry no inline scripts and styles
+const contentSecurityPolicy = "default-src 'none'; script-src 'self'; style-src 'self'; img-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+var (
+	//go:embed templates
+	templateFS embed.FS
+
+	//go:embed static
+	staticFS embed.FS
+)
+
+// LoadTemplates parses the embedded templates, then the ones of the same names in dir override them if dir isn't empty
+func LoadTemplates(dir string) (*template.Template, error) {
+	tmpl, err := template.ParseFS(templateFS, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+	if dir == "" {
+		return tmpl, nil
+	}
+
+	names, err := fs.Glob(os.DirFS(dir), "*.html")
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if tmpl.Lookup(name) == nil {
+			return nil, fmt.Errorf("unknown template %q in %s", name, dir)
+		}
+	}
+	if len(names) == 0 {
+		return tmpl, nil
+	}
+	return tmpl.ParseFS(os.DirFS(dir), names...)
+}
+
+// Static serves the embedded assets of the pages
+func Static() http.FileSystem {
+	sub, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		panic(err)
+	}
+	return http.FS(sub)
+}
+
+// renderPage renders the template of the name with the content security policy
+func renderPage(c *gin.Context, code int, tmpl *template.Template, name string, data any) {
+	c.Header("Content-Security-Policy", contentSecurityPolicy)
+	c.Render(code, render.HTML{Template: tmpl, Name: name, Data: data})
+}
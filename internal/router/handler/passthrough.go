@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Hao1995/short-url/internal/domain"
+)
+
+var errDotSegment = errors.New("dot segment in the forwarded path")
+
+// forwardedPath returns the escaped path after the id, e.g. `/extra/path` of `/abc123/extra/path`, or empty if there's none
+func forwardedPath(r *http.Request) string {
+	_, path, ok := strings.Cut(strings.TrimPrefix(r.URL.EscapedPath(), "/"), "/")
+	if !ok {
+		return ""
+	}
+	return "/" + path
+}
+
+// passthrough appends the escaped path to the one of dest, and appends the query to the one of dest by the rule.
+// Only the path and the query are changed, so the scheme and the host of dest stay the same.
+func passthrough(dest *url.URL, rule domain.Passthrough, path string, query url.Values) (*url.URL, error) {
+	if dest.Opaque != "" || dest.Host == "" {
+		return nil, fmt.Errorf("destination %q without the host", dest.Redacted())
+	}
+
+	forwarded := *dest
+	if path != "" {
+		// the dot segments would climb up the path of the destination once the browsers resolve them
+		for _, segment := range strings.Split(path, "/") {
+			if unescaped, err := url.PathUnescape(segment); err != nil {
+				return nil, err
+			} else if unescaped == "." || unescaped == ".." {
+				return nil, errDotSegment
+			}
+		}
+		rawPath := strings.TrimSuffix(dest.EscapedPath(), "/") + path
+		unescaped, err := url.PathUnescape(rawPath)
+		if err != nil {
+			return nil, err
+		}
+		forwarded.Path, forwarded.RawPath = unescaped, rawPath
+	}
+
+	if len(query) > 0 {
+		// the query of the destination is kept as it is, as the re-encoded one might not be understood by it, e.g. `?flag` to `?flag=`
+		values := dest.Query()
+		appended := url.Values{}
+		for key, vals := range query {
+			switch rule {
+			case domain.PassthroughKeep:
+				if _, ok := values[key]; !ok {
+					appended[key] = vals
+				}
+			case domain.PassthroughOverride, domain.PassthroughAppend:
+				appended[key] = vals
+			default:
+				return nil, fmt.Errorf("%w: %q", domain.ErrInvalidPassthrough, rule)
+			}
+		}
+		rawQuery := dest.RawQuery
+		if rule == domain.PassthroughOverride {
+			rawQuery = dropParams(rawQuery, appended)
+		}
+		forwarded.RawQuery = joinQuery(rawQuery, appended.Encode())
+	}
+
+	// make sure the browsers resolve the result to the same origin
+	resolved, err := url.Parse(forwarded.String())
+	if err != nil {
+		return nil, err
+	}
+	if resolved.Scheme != dest.Scheme || resolved.Host != dest.Host {
+		return nil, fmt.Errorf("forwarded url %q leaves the origin of the destination", resolved.Redacted())
+	}
+	return &forwarded, nil
+}
+
+// dropParams drops the parameters of the keys from the raw query, and leaves the others as they are
+func dropParams(rawQuery string, keys url.Values) string {
+	var kept []string
+	for _, param := range strings.Split(rawQuery, "&") {
+		rawKey, _, _ := strings.Cut(param, "=")
+		if key, err := url.QueryUnescape(rawKey); err == nil {
+			if _, ok := keys[key]; ok {
+				continue
+			}
+		}
+		kept = append(kept, param)
+	}
+	return strings.Join(kept, "&")
+}
+
+// joinQuery joins the raw queries with `&`, skipping the empty ones
+func joinQuery(rawQuery, appended string) string {
+	if rawQuery == "" {
+		return appended
+	}
+	if appended == "" {
+		return rawQuery
+	}
+	return rawQuery + "&" + appended
+}
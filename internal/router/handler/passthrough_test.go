@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/Hao1995/short-url/internal/domain"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PassthroughTestSuite struct {
+	suite.Suite
+}
+
+func TestPassthroughTestSuite(t *testing.T) {
+	suite.Run(t, new(PassthroughTestSuite))
+}
+
+func (s *PassthroughTestSuite) TestPassthrough() {
+	for _, t := range []struct {
+		name   string
+		dest   string
+		rule   domain.Passthrough
+		path   string
+		query  url.Values
+		exp    string
+		expErr bool
+	}{
+		{
+			name: "append the path to the one with the trailing slash",
+			dest: "https://example.com/app/#top",
+			rule: domain.PassthroughKeep,
+			path: "/extra/path",
+			exp:  "https://example.com/app/extra/path#top",
+		},
+		{
+			name: "keep the escaped path",
+			dest: "https://example.com/a%2Fb",
+			rule: domain.PassthroughKeep,
+			path: "/c%2Fd/%E4%B8%AD",
+			exp:  "https://example.com/a%2Fb/c%2Fd/%E4%B8%AD",
+		},
+		{
+			name: "stay on the host with the path of double slashes",
+			dest: "https://example.com",
+			rule: domain.PassthroughKeep,
+			path: "//evil.example.org/whatever1",
+			exp:  "https://example.com//evil.example.org/whatever1",
+		},
+		{
+			name:  "keep the query parameters of the destination",
+			dest:  "https://example.com/?a=1",
+			rule:  domain.PassthroughKeep,
+			query: url.Values{"a": {"2"}, "b": {"3"}},
+			exp:   "https://example.com/?a=1&b=3",
+		},
+		{
+			name:  "override the query parameters of the destination",
+			dest:  "https://example.com/?a=1",
+			rule:  domain.PassthroughOverride,
+			query: url.Values{"a": {"2"}, "b": {"3"}},
+			exp:   "https://example.com/?a=2&b=3",
+		},
+		{
+			name:  "append the query parameters to the ones of the destination",
+			dest:  "https://example.com/?a=1",
+			rule:  domain.PassthroughAppend,
+			query: url.Values{"a": {"2"}, "b": {"&x=y"}},
+			exp:   "https://example.com/?a=1&a=2&b=%26x%3Dy",
+		},
+		{
+			name:  "keep the raw query of the destination",
+			dest:  "https://example.com/?z=1&flag&a=%7e",
+			rule:  domain.PassthroughAppend,
+			query: url.Values{"b": {"2"}},
+			exp:   "https://example.com/?z=1&flag&a=%7e&b=2",
+		},
+		{
+			name:  "override the parameters only in the raw query of the destination",
+			dest:  "https://example.com/?z=1&flag&a=%7e&a=3",
+			rule:  domain.PassthroughOverride,
+			query: url.Values{"a": {"2"}},
+			exp:   "https://example.com/?z=1&flag&a=2",
+		},
+		{
+			name:   "reject the escaped dot segments",
+			dest:   "https://example.com/app/",
+			rule:   domain.PassthroughKeep,
+			path:   "/%2E%2E/admin",
+			expErr: true,
+		},
+		{
+			name:   "reject the destination without the host",
+			dest:   "https:",
+			rule:   domain.PassthroughKeep,
+			path:   "//evil.example.org",
+			expErr: true,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			dest, err := url.Parse(t.dest)
+			s.Require().NoError(err)
+
+			forwarded, err := passthrough(dest, t.rule, t.path, t.query)
+			if t.expErr {
+				s.Error(err)
+				return
+			}
+			s.Require().NoError(err)
+			s.Equal(t.exp, forwarded.String())
+			s.Equal(t.dest, dest.String(), "leave the destination as it is")
+		})
+	}
+}
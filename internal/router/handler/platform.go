@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/router/handler/request"
+)
+
+// platformOf parses the operating system of the User-Agent, which is empty if it's none of the known ones.
+// Android is checked before Linux and iOS before macOS, since their User-Agents mention the latter as well.
+func platformOf(userAgent string) domain.Platform {
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"), strings.Contains(userAgent, "iPod"):
+		return domain.PlatformIos
+	case strings.Contains(userAgent, "Android"):
+		return domain.PlatformAndroid
+	case strings.Contains(userAgent, "Windows"):
+		return domain.PlatformWindows
+	case strings.Contains(userAgent, "Macintosh"), strings.Contains(userAgent, "Mac OS X"):
+		return domain.PlatformMacos
+	case strings.Contains(userAgent, "Linux"), strings.Contains(userAgent, "X11"):
+		return domain.PlatformLinux
+	}
+	return ""
+}
+
+// matchRule returns the first rule matching the platform and its 1-based position, or 0 if none of them does
+func matchRule(rules []domain.RedirectRule, platform domain.Platform) (*domain.RedirectRule, int) {
+	if platform == "" {
+		return nil, 0
+	}
+	for i, rule := range rules {
+		switch rule.Platform {
+		case platform:
+		case domain.PlatformMobile:
+			if platform != domain.PlatformIos && platform != domain.PlatformAndroid {
+				continue
+			}
+		case domain.PlatformDesktop:
+			if platform != domain.PlatformWindows && platform != domain.PlatformMacos && platform != domain.PlatformLinux {
+				continue
+			}
+		default:
+			continue
+		}
+		return &rules[i], i + 1
+	}
+	return nil, 0
+}
+
+// redirectRules converts the rules of the request to the domain ones in order
+func redirectRules(reqs []request.RedirectRuleRequest) []domain.RedirectRule {
+	if len(reqs) == 0 {
+		return nil
+	}
+	rules := make([]domain.RedirectRule, len(reqs))
+	for i, req := range reqs {
+		rules[i] = domain.RedirectRule{Platform: domain.Platform(req.Platform), Url: req.Url}
+	}
+	return rules
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/Hao1995/short-url/internal/domain"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PlatformTestSuite struct {
+	suite.Suite
+}
+
+func TestPlatformTestSuite(t *testing.T) {
+	suite.Run(t, new(PlatformTestSuite))
+}
+
+func (s *PlatformTestSuite) TestPlatformOf() {
+	for _, t := range []struct {
+		name      string
+		userAgent string
+		exp       domain.Platform
+	}{
+		{
+			name:      "iPad mentioning Mac OS X",
+			userAgent: "Mozilla/5.0 (iPad; CPU OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148",
+			exp:       domain.PlatformIos,
+		},
+		{
+			name:      "Android mentioning Linux",
+			userAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36",
+			exp:       domain.PlatformAndroid,
+		},
+		{
+			name:      "Windows",
+			userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			exp:       domain.PlatformWindows,
+		},
+		{
+			name:      "macOS",
+			userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_5) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Safari/605.1.15",
+			exp:       domain.PlatformMacos,
+		},
+		{
+			name:      "Linux",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0",
+			exp:       domain.PlatformLinux,
+		},
+		{
+			name:      "unknown",
+			userAgent: "curl/8.7.1",
+			exp:       "",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			s.Equal(t.exp, platformOf(t.userAgent))
+		})
+	}
+}
+
+func (s *PlatformTestSuite) TestMatchRule() {
+	rules := []domain.RedirectRule{
+		{Platform: domain.PlatformIos, Url: "https://apps.example.com/"},
+		{Platform: domain.PlatformMobile, Url: "https://m.example.com/"},
+		{Platform: domain.PlatformDesktop, Url: "https://www.example.com/"},
+	}
+	for _, t := range []struct {
+		name     string
+		platform domain.Platform
+		exp      *domain.RedirectRule
+		expNo    int
+	}{
+		{
+			name:     "the first matched rule wins",
+			platform: domain.PlatformIos,
+			exp:      &rules[0],
+			expNo:    1,
+		},
+		{
+			name:     "Android is mobile",
+			platform: domain.PlatformAndroid,
+			exp:      &rules[1],
+			expNo:    2,
+		},
+		{
+			name:     "Linux is desktop",
+			platform: domain.PlatformLinux,
+			exp:      &rules[2],
+			expNo:    3,
+		},
+		{
+			name:     "unknown platform",
+			platform: "",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			rule, no := matchRule(rules, t.platform)
+			s.Equal(t.exp, rule)
+			s.Equal(t.expNo, no)
+		})
+	}
+}
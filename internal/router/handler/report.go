@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/router/handler/request"
+	"github.com/Hao1995/short-url/internal/usecase"
+	"github.com/Hao1995/short-url/pkg/logkit"
+	"github.com/Hao1995/short-url/pkg/tracekit"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultReportListLimit = 100
+
+var ErrConflict = errors.New("conflict")
+
+type ReportHandler struct {
+	uc usecase.ReportUseCase
+}
+
+func NewReportHandler(uc usecase.ReportUseCase) *ReportHandler {
+	return &ReportHandler{
+		uc: uc,
+	}
+}
+
+// Create queues the abuse report of the short url, which is public
+func (hlr *ReportHandler) Create(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ReportHandler.Create")
+	defer span.End()
+
+	var req request.ReportCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		slog.InfoContext(ctx, "handler.Create. failed to bind json", logkit.Err(err))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+		return
+	}
+
+	reportDto := &domain.ReportDto{TargetID: req.ID, Reason: req.Reason, Contact: req.Contact}
+	if err := hlr.uc.Report(ctx, reportDto); errors.Is(err, domain.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ErrNotFound.Error()})
+		return
+	} else if err != nil {
+		tracekit.RecordError(span, err)
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"id":     reportDto.TargetID,
+		"status": reportDto.Status,
+	})
+}
+
+// List lists the reports of the status from the oldest one, which are the pending ones by default
+func (hlr *ReportHandler) List(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ReportHandler.List")
+	defer span.End()
+
+	var req request.ReportListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		slog.InfoContext(ctx, "handler.List. failed to bind query", logkit.Err(err))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+		return
+	}
+	status := domain.ReportStatusPending
+	if req.Status != "" {
+		var err error
+		if status, err = domain.ParseReportStatus(req.Status); err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+			return
+		}
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultReportListLimit
+	}
+
+	objs, err := hlr.uc.List(ctx, &domain.ListReportsReqDto{Status: status, Limit: req.Limit})
+	if err != nil {
+		tracekit.RecordError(span, err)
+		abortWithError(c, err)
+		return
+	}
+
+	reports := make([]gin.H, len(objs))
+	for i, obj := range objs {
+		reports[i] = gin.H{
+			"id":         obj.TargetID,
+			"reason":     obj.Reason,
+			"contact":    obj.Contact,
+			"status":     obj.Status,
+			"createdAt":  obj.CreatedAt,
+			"resolvedAt": obj.ResolvedAt,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"reports": reports})
+}
+
+// Resolve takes the action on the reported short url, and resolves all of its pending reports
+func (hlr *ReportHandler) Resolve(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ReportHandler.Resolve")
+	defer span.End()
+
+	var uri request.ReportResolveUriRequest
+	var req request.ReportResolveRequest
+	if err := errors.Join(c.ShouldBindUri(&uri), c.ShouldBindJSON(&req)); err != nil {
+		slog.InfoContext(ctx, "handler.Resolve. failed to bind request", logkit.Err(err))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+		return
+	}
+	action, err := domain.ParseReportAction(req.Action)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+		return
+	}
+
+	status, err := hlr.uc.Resolve(ctx, uri.ID, action)
+	switch {
+	case errors.Is(err, domain.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": ErrNotFound.Error()})
+		return
+	case errors.Is(err, domain.ErrNoCreator):
+		// the short url created without the api key has no creator to ban
+		c.JSON(http.StatusConflict, gin.H{"error": ErrConflict.Error(), "reason": err.Error()})
+		return
+	case err != nil:
+		tracekit.RecordError(span, err)
+		abortWithError(c, err)
+		return
+	}
+
+	slog.InfoContext(ctx, "handler.Resolve. success resolve the reports", "id", uri.ID, "action", action.String())
+	c.JSON(http.StatusOK, gin.H{
+		"id":     uri.ID,
+		"status": status,
+	})
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/router/handler/request"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+	"github.com/gin-gonic/gin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReportHandlerTestSuite struct {
+	suite.Suite
+	ginEngine *gin.Engine
+
+	now time.Time
+
+	uc   *usecase.ReportUseCase
+	impl *ReportHandler
+}
+
+func TestReportHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ReportHandlerTestSuite))
+}
+
+func (s *ReportHandlerTestSuite) SetupSuite() {
+	s.now = time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC)
+
+	s.uc = usecase.NewReportUseCase(s.T())
+	s.impl = NewReportHandler(s.uc)
+
+	r := gin.Default()
+	r.POST("/api/v1/reports", s.impl.Create)
+	r.GET("/api/v1/reports", s.impl.List)
+	r.POST("/api/v1/reports/:id/resolve", s.impl.Resolve)
+	s.ginEngine = r
+}
+
+func (s *ReportHandlerTestSuite) TestCreate() {
+	for _, t := range []struct {
+		name    string
+		req     *request.ReportCreateRequest
+		setup   func()
+		expCode int
+		expResp string
+	}{
+		{
+			name: "queue the report successfully",
+			req:  &request.ReportCreateRequest{ID: "testid1", Reason: "phishing", Contact: "someone@example.com"},
+			setup: func() {
+				s.uc.On("Report", mock.Anything, &domain.ReportDto{TargetID: "testid1", Reason: "phishing", Contact: "someone@example.com"}).
+					Once().
+					Run(func(args mock.Arguments) {
+						args.Get(1).(*domain.ReportDto).Status = domain.ReportStatusPending
+					}).
+					Return(nil)
+			},
+			expCode: 202,
+			expResp: "{\"id\":\"testid1\",\"status\":\"Pending\"}",
+		},
+		{
+			name:    "missing reason",
+			req:     &request.ReportCreateRequest{ID: "testid1"},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "unprocessable entity"),
+		},
+		{
+			name:    "reason too long",
+			req:     &request.ReportCreateRequest{ID: "testid1", Reason: strings.Repeat("a", 1025)},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "unprocessable entity"),
+		},
+		{
+			name: "short url not found, return 404",
+			req:  &request.ReportCreateRequest{ID: "testid1", Reason: "phishing"},
+			setup: func() {
+				s.uc.On("Report", mock.Anything, &domain.ReportDto{TargetID: "testid1", Reason: "phishing"}).
+					Once().
+					Return(domain.ErrRecordNotFound)
+			},
+			expCode: 404,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "not found"),
+		},
+		{
+			name: "failed to queue the report",
+			req:  &request.ReportCreateRequest{ID: "testid1", Reason: "phishing"},
+			setup: func() {
+				s.uc.On("Report", mock.Anything, &domain.ReportDto{TargetID: "testid1", Reason: "phishing"}).
+					Once().
+					Return(errors.New("whatever"))
+			},
+			expCode: 500,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "internal server error"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			w := httptest.NewRecorder()
+			data, _ := json.Marshal(t.req)
+			req, _ := http.NewRequest("POST", "/api/v1/reports", strings.NewReader(string(data)))
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.Equal(t.expResp, w.Body.String())
+		})
+	}
+}
+
+func (s *ReportHandlerTestSuite) TestList() {
+	for _, t := range []struct {
+		name    string
+		query   string
+		setup   func()
+		expCode int
+		expResp string
+	}{
+		{
+			name: "list the pending reports by default",
+			setup: func() {
+				s.uc.On("List", mock.Anything, &domain.ListReportsReqDto{Status: domain.ReportStatusPending, Limit: 100}).
+					Once().
+					Return([]*domain.ReportDto{
+						{ID: 1, TargetID: "testid1", Reason: "phishing", Status: domain.ReportStatusPending, CreatedAt: s.now},
+					}, nil)
+			},
+			expCode: 200,
+			expResp: "{\"reports\":[{\"contact\":\"\",\"createdAt\":\"2025-02-10T08:30:15Z\",\"id\":\"testid1\",\"reason\":\"phishing\",\"resolvedAt\":null,\"status\":\"Pending\"}]}",
+		},
+		{
+			name:  "list the reports of the status",
+			query: "?status=Dismissed&limit=10",
+			setup: func() {
+				s.uc.On("List", mock.Anything, &domain.ListReportsReqDto{Status: domain.ReportStatusDismissed, Limit: 10}).
+					Once().
+					Return([]*domain.ReportDto{}, nil)
+			},
+			expCode: 200,
+			expResp: "{\"reports\":[]}",
+		},
+		{
+			name:    "invalid status",
+			query:   "?status=whatever",
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "unprocessable entity"),
+		},
+		{
+			name:    "invalid limit",
+			query:   "?limit=5000",
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "unprocessable entity"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/api/v1/reports"+t.query, nil)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.Equal(t.expResp, w.Body.String())
+		})
+	}
+}
+
+func (s *ReportHandlerTestSuite) TestResolve() {
+	for _, t := range []struct {
+		name    string
+		id      string
+		req     *request.ReportResolveRequest
+		setup   func()
+		expCode int
+		expResp string
+	}{
+		{
+			name: "disable the reported short url",
+			id:   "testid1",
+			req:  &request.ReportResolveRequest{Action: "Disable"},
+			setup: func() {
+				s.uc.On("Resolve", mock.Anything, "testid1", domain.ReportActionDisable).Once().Return(domain.ReportStatusDisabled, nil)
+			},
+			expCode: 200,
+			expResp: "{\"id\":\"testid1\",\"status\":\"Disabled\"}",
+		},
+		{
+			name:    "invalid action",
+			id:      "testid1",
+			req:     &request.ReportResolveRequest{Action: "whatever"},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "unprocessable entity"),
+		},
+		{
+			name: "short url not found, return 404",
+			id:   "testid1",
+			req:  &request.ReportResolveRequest{Action: "Dismiss"},
+			setup: func() {
+				s.uc.On("Resolve", mock.Anything, "testid1", domain.ReportActionDismiss).Once().Return(domain.ReportStatus(""), domain.ErrRecordNotFound)
+			},
+			expCode: 404,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "not found"),
+		},
+		{
+			name: "short url without the creator to ban, return 409",
+			id:   "testid1",
+			req:  &request.ReportResolveRequest{Action: "Ban"},
+			setup: func() {
+				s.uc.On("Resolve", mock.Anything, "testid1", domain.ReportActionBan).Once().Return(domain.ReportStatus(""), domain.ErrNoCreator)
+			},
+			expCode: 409,
+			expResp: fmt.Sprintf("{\"error\":\"%s\",\"reason\":\"%s\"}", "conflict", "no creator"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			w := httptest.NewRecorder()
+			data, _ := json.Marshal(t.req)
+			req, _ := http.NewRequest("POST", "/api/v1/reports/"+t.id+"/resolve", strings.NewReader(string(data)))
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.Equal(t.expResp, w.Body.String())
+		})
+	}
+}
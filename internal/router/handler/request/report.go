@@ -0,0 +1,20 @@
+package request
+
+type ReportCreateRequest struct {
+	ID      string `form:"id" json:"id" binding:"required,max=16"`
+	Reason  string `form:"reason" json:"reason" binding:"required,max=1024"`
+	Contact string `form:"contact" json:"contact" binding:"max=255"`
+}
+
+type ReportListRequest struct {
+	Status string `form:"status"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=1000"`
+}
+
+type ReportResolveUriRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
+
+type ReportResolveRequest struct {
+	Action string `form:"action" json:"action" binding:"required"`
+}
@@ -1,12 +1,72 @@
 package request
 
-import "time"
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type ShortUrlCreateRequest struct {
 	Url      string    `form:"url" json:"url" binding:"required,url"`
 	ExpireAt time.Time `form:"expireAt" json:"expireAt" binding:"required"`
+	// Interstitial overrides the interstitial mode of the tenant for the link
+	Interstitial string `form:"interstitial" json:"interstitial,omitempty" binding:"omitempty,oneof=never unverified always"`
+	// RedirectCode overrides the default redirect code for the link
+	RedirectCode int `form:"redirectCode" json:"redirectCode,omitempty" binding:"omitempty,oneof=301 302 307 308"`
+	// Passthrough forwards the path and the query after the id to the destination by the merge rule of the query
+	Passthrough string `form:"passthrough" json:"passthrough,omitempty" binding:"omitempty,oneof=keep override append"`
+	// Template is the name of the param template appended to the destination
+	Template string `form:"template" json:"template,omitempty" binding:"omitempty,max=64"`
+	// Rules send the visitors of the platforms to their own destinations, the first matched one wins
+	Rules []RedirectRuleRequest `json:"rules,omitempty" binding:"omitempty,max=10,dive"`
+	// GeoRules send the visitors located in the countries or the continents to their own destinations
+	GeoRules []GeoRuleRequest `json:"geoRules,omitempty" binding:"omitempty,max=50,dive"`
+	// Variants split the visitors matching none of the rules across the destinations by weight, e.g. 70/20/10
+	Variants []VariantRequest `json:"variants,omitempty" binding:"omitempty,min=2,max=10,dive"`
 }
 
+type RedirectRuleRequest struct {
+	Platform string `json:"platform" binding:"required,oneof=ios android mobile windows macos linux desktop"`
+	Url      string `json:"url" binding:"required,url"`
+}
+
+// GeoRuleRequest is matched by either the country or the continent, which is checked by the use case
+type GeoRuleRequest struct {
+	Country   string `json:"country,omitempty" binding:"omitempty,iso3166_1_alpha2"`
+	Continent string `json:"continent,omitempty" binding:"omitempty,oneof=AF AN AS EU NA OC SA"`
+	Url       string `json:"url" binding:"required,url"`
+}
+
+type VariantRequest struct {
+	Url    string `json:"url" binding:"required,url"`
+	Weight int    `json:"weight" binding:"required,min=1,max=10000"`
+}
+
+// PREVIEW_SUFFIX appended to the id asks for the preview rather than the redirect, e.g. /abc123+
+const PREVIEW_SUFFIX = "+"
+
+// PREVIEW_QUERY set to true asks for the preview as well, e.g. /abc123?preview=1
+const PREVIEW_QUERY = "preview"
+
 type ShortUrlGetRequest struct {
-	ID string `uri:"id" binding:"required"`
+	ID string `uri:"id" form:"-" binding:"required"`
+	// Preview is set by PREVIEW_QUERY or PREVIEW_SUFFIX
+	Preview bool `form:"-"`
+}
+
+// ParsePreviewQuery sets Preview if PREVIEW_QUERY of the query is true.
+// The other values never fail the redirect, which are left to the destination as it might use the same name.
+func (req *ShortUrlGetRequest) ParsePreviewQuery(query url.Values) {
+	if preview, err := strconv.ParseBool(query.Get(PREVIEW_QUERY)); err == nil && preview {
+		req.Preview = true
+	}
+}
+
+// TrimPreviewSuffix trims PREVIEW_SUFFIX off the id and sets Preview if the id has it
+func (req *ShortUrlGetRequest) TrimPreviewSuffix() {
+	if id, ok := strings.CutSuffix(req.ID, PREVIEW_SUFFIX); ok {
+		req.ID = id
+		req.Preview = true
+	}
 }
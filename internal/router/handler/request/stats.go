@@ -0,0 +1,5 @@
+package request
+
+type StatsUriRequest struct {
+	ID string `uri:"id" binding:"required"`
+}
@@ -0,0 +1,14 @@
+package request
+
+type ParamTemplateCreateRequest struct {
+	Name  string `form:"name" json:"name" binding:"required,max=64"`
+	Query string `form:"query" json:"query" binding:"required,max=2048"`
+}
+
+type ParamTemplateUriRequest struct {
+	Name string `uri:"name" binding:"required,max=64"`
+}
+
+type ParamTemplateUpdateRequest struct {
+	Query string `form:"query" json:"query" binding:"required,max=2048"`
+}
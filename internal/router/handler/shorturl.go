@@ -1,76 +1,398 @@
 package handler
 
 import (
+	"context"
 	"errors"
-	"log"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"time"
 
 	"github.com/Hao1995/short-url/internal/domain"
 	"github.com/Hao1995/short-url/internal/router/handler/request"
 	"github.com/Hao1995/short-url/internal/usecase"
+	"github.com/Hao1995/short-url/pkg/logkit"
+	"github.com/Hao1995/short-url/pkg/migrationkit/randkit"
+	"github.com/Hao1995/short-url/pkg/policykit"
+	"github.com/Hao1995/short-url/pkg/tracekit"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"go.opentelemetry.io/otel"
 )
 
+// HEADER_API_KEY identifies the creator of the short url, which is optional
+const HEADER_API_KEY = "X-API-Key"
+
 var (
 	ErrUnprocessableEntity = errors.New("unprocessable entity")
 	ErrInternalServerError = errors.New("internal server error")
+	ErrBadRequest          = errors.New("bad request")
 	ErrNotFound            = errors.New("not found")
+	ErrGone                = errors.New("gone")
+	ErrServiceUnavailable  = errors.New("service unavailable")
+	ErrForbidden           = errors.New("forbidden")
+
+	tracer = otel.Tracer("github.com/Hao1995/short-url/internal/router/handler")
+
+	now = func() time.Time {
+		return time.Now()
+	}
+
+	newClickID = func() string {
+		return randkit.String(16)
+	}
+
+	randIntN = func(n int) int {
+		return rand.IntN(n)
+	}
 )
 
+// RedirectConfig is the config of the redirect responses
+type RedirectConfig struct {
+	// Code is one of 301, 302, 307 and 308, which is used by the links without their own
+	Code int
+	// MaxAge caps how long the permanent redirects are cached by the browsers and the CDNs
+	MaxAge time.Duration
+}
+
 type ShortUrlHandler struct {
-	uc usecase.UseCase
+	uc           usecase.UseCase
+	params       usecase.ParamTemplateUseCase
+	templates    *template.Template
+	interstitial *Interstitial
+	fallbacks    *Fallbacks
+	geo          *Geo
+	splitter     *Splitter
+	redirect     RedirectConfig
 }
 
-func NewShortUrlHandler(uc usecase.UseCase) *ShortUrlHandler {
+// NewShortUrlHandler generates the handler, the templates of the pages are loaded by LoadTemplates
+// and `params` renders the param templates attached to the short urls
+func NewShortUrlHandler(uc usecase.UseCase, params usecase.ParamTemplateUseCase, templates *template.Template, interstitial *Interstitial, fallbacks *Fallbacks, geo *Geo, splitter *Splitter, redirect RedirectConfig) *ShortUrlHandler {
 	return &ShortUrlHandler{
-		uc: uc,
+		uc:           uc,
+		params:       params,
+		templates:    templates,
+		interstitial: interstitial,
+		fallbacks:    fallbacks,
+		geo:          geo,
+		splitter:     splitter,
+		redirect:     redirect,
 	}
 }
 
 // Create creates short_url record and return short url id
 func (hlr *ShortUrlHandler) Create(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ShortUrlHandler.Create")
+	defer span.End()
+
 	var req request.ShortUrlCreateRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		log.Printf("handler.Create. failed to bind json: %s", err)
+		slog.InfoContext(ctx, "handler.Create. failed to bind json", logkit.Err(err))
 		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
 		return
 	}
+	if len(req.GeoRules) > 0 && !hlr.geo.Enabled() {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  ErrUnprocessableEntity.Error(),
+			"reason": "geo_unavailable",
+		})
+		return
+	}
 
-	obj, err := hlr.uc.Create(c.Request.Context(), &domain.CreateReqDto{Url: req.Url, ExpireAt: req.ExpireAt})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInternalServerError.Error()})
+	obj, err := hlr.uc.Create(ctx, &domain.CreateReqDto{
+		Url:          req.Url,
+		ExpireAt:     req.ExpireAt,
+		APIKey:       c.GetHeader(HEADER_API_KEY),
+		Interstitial: domain.Interstitial(req.Interstitial),
+		RedirectCode: req.RedirectCode,
+		Passthrough:  domain.Passthrough(req.Passthrough),
+		Template:     req.Template,
+		Rules:        redirectRules(req.Rules),
+		GeoRules:     geoRules(req.GeoRules),
+		Variants:     splitVariants(req.Variants),
+	})
+	var violation *policykit.Violation
+	if errors.Is(err, domain.ErrCreatorBanned) {
+		c.JSON(http.StatusForbidden, gin.H{"error": ErrForbidden.Error()})
+		return
+	} else if errors.Is(err, domain.ErrUnknownTemplate) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  ErrUnprocessableEntity.Error(),
+			"reason": "unknown_template",
+		})
+		return
+	} else if errors.Is(err, domain.ErrInvalidRule) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  ErrUnprocessableEntity.Error(),
+			"reason": "invalid_rule",
+		})
+		return
+	} else if errors.Is(err, domain.ErrInvalidVariant) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  ErrUnprocessableEntity.Error(),
+			"reason": "invalid_variant",
+		})
+		return
+	} else if errors.As(err, &violation) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  ErrUnprocessableEntity.Error(),
+			"reason": violation.Reason,
+		})
+		return
+	} else if err != nil {
+		tracekit.RecordError(span, err)
+		abortWithError(c, err)
 		return
 	}
 
-	log.Printf("handler.Create. success create a short utl: %s", obj.ShortUrl)
+	slog.InfoContext(ctx, "handler.Create. success create a short url", "id", obj.TargetID)
 	c.JSON(http.StatusCreated, gin.H{
 		"id":       obj.TargetID,
 		"shortUrl": obj.ShortUrl,
+		// PendingReview until the destination is scanned
+		"status": obj.Status,
 	})
 }
 
-// Get redirects to the original url
+// Get redirects to the original url, which serves HEAD as well for the link checkers
 func (hlr *ShortUrlHandler) Get(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ShortUrlHandler.Get")
+	defer span.End()
+
 	var req request.ShortUrlGetRequest
 	if err := c.ShouldBindUri(&req); err != nil {
-		log.Printf("handler.Get. failed to bind uri: %s", err)
+		logkit.Sampled().InfoContext(ctx, "handler.Get. failed to bind request", logkit.Err(err))
 		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
 		return
 	}
+	req.ParsePreviewQuery(c.Request.URL.Query())
+	req.TrimPreviewSuffix()
 
-	obj, err := hlr.uc.Get(c.Request.Context(), req.ID)
+	obj, err := hlr.uc.Get(ctx, req.ID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInternalServerError.Error()})
+		tracekit.RecordError(span, err)
+		abortWithError(c, err)
+		return
+	}
+
+	if req.Preview {
+		hlr.preview(c, req.ID, obj)
+		return
+	}
+
+	switch obj.Status {
+	case domain.GetRespStatusNormal:
+	case domain.GetRespStatusDisabled:
+		// tell the visitors the link was taken down rather than never existed
+		logkit.Sampled().InfoContext(ctx, "handler.Get. get disabled status, return 410", "id", req.ID)
+		hlr.abortWithPage(c, http.StatusGone, ErrGone, "removed.html", req.ID)
+		return
+	case domain.GetRespStatusExpired:
+		logkit.Sampled().InfoContext(ctx, "handler.Get. get expired status, return 410", "id", req.ID)
+		hlr.fallback(c, http.StatusGone, ErrGone, "expired.html", req.ID)
+		return
+	default:
+		logkit.Sampled().InfoContext(ctx, "handler.Get. get abnormal status, return 404", "id", req.ID, "status", obj.Status.String())
+		hlr.fallback(c, http.StatusNotFound, ErrNotFound, "notfound.html", req.ID)
+		return
+	}
+
+	path := forwardedPath(c.Request)
+	if obj.Passthrough == "" && path != "" && path != "/" {
+		logkit.Sampled().InfoContext(ctx, "handler.Get. get the path of the link without passthrough, return 404", "id", req.ID)
+		hlr.abortWithPage(c, http.StatusNotFound, ErrNotFound, "notfound.html", req.ID)
 		return
 	}
 
-	if obj.Status == domain.GetRespStatusNotFound || obj.Status == domain.GetRespStatusExpired {
-		log.Printf("handler.Get. get abnormal status: %s, return 404", obj.Status)
+	// the destinations are validated on creating, the ones failed to parse are shown before leaving as well.
+	// Only the visitors are assigned to the variants, the link checkers never follow the redirects of HEAD.
+	target, ruleNo, variantNo := hlr.destination(c, req.ID, obj, c.Request.Method != http.MethodHead)
+	dest, parseErr := url.Parse(target)
+	if obj.Passthrough != "" && parseErr == nil {
+		forwarded, err := passthrough(dest, obj.Passthrough, path, c.Request.URL.Query())
+		if err != nil {
+			logkit.Sampled().InfoContext(ctx, "handler.Get. failed to pass the path and the query through", "id", req.ID, logkit.Err(err))
+			c.JSON(http.StatusBadRequest, gin.H{"error": ErrBadRequest.Error()})
+			return
+		}
+		dest, target = forwarded, forwarded.String()
+	}
+
+	var clickID string
+	if obj.Template != "" && parseErr == nil {
+		clickID = newClickID()
+		if forwarded, err := hlr.applyParams(ctx, req.ID, obj.Template, clickID, dest); err != nil {
+			// the campaign params are nice to have, which never fail the redirect
+			logkit.Sampled().WarnContext(ctx, "handler.Get. failed to apply the param template", "id", req.ID, "template", obj.Template, logkit.Err(err))
+		} else {
+			dest, target = forwarded, forwarded.String()
+		}
+	}
+
+	// the link checkers don't click the links
+	if c.Request.Method != http.MethodHead {
+		hlr.uc.Click(ctx, &domain.ClickDto{
+			TargetID:  req.ID,
+			ClickID:   clickID,
+			Rule:      ruleNo,
+			Variant:   variantNo,
+			Referer:   c.Request.Referer(),
+			UserAgent: c.Request.UserAgent(),
+		})
+	}
+
+	if parseErr != nil || hlr.interstitial.Show(c.Request.Host, obj.Interstitial, dest) {
+		logkit.Sampled().DebugContext(ctx, "handler.Get. show the interstitial page", "id", req.ID, "url", target)
+		host := target
+		if parseErr == nil {
+			host = dest.Host
+		}
+		renderPage(c, http.StatusOK, hlr.templates, "interstitial.html", gin.H{
+			"ID":    req.ID,
+			"URL":   target,
+			"Host":  host,
+			"Delay": int(hlr.interstitial.Delay().Seconds()),
+		})
+		return
+	}
+
+	code := obj.RedirectCode
+	if code == 0 {
+		code = hlr.redirect.Code
+	}
+	logkit.Sampled().DebugContext(ctx, "handler.Get. success redirect", "id", req.ID, "url", target, "code", code)
+	// the caches can't tell the client ip apart as the User-Agent, so the redirects of the geo rules are kept by the browsers only,
+	// and so are the ones of the variants, which set the cookies of the visitors.
+	// The ones of the param templates are never cached, which carry the click id and the date of every click.
+	hlr.setCacheHeaders(c, code, obj.ExpireAt, len(obj.GeoRules) > 0 || len(obj.Variants) > 0, obj.Template != "")
+	if len(obj.Rules) > 0 {
+		// the caches can't share the redirect among the platforms
+		c.Header("Vary", "User-Agent")
+	}
+	c.Redirect(code, target)
+}
+
+// applyParams renders the param template and sets the params on dest, which override the ones of dest and the request
+func (hlr *ShortUrlHandler) applyParams(ctx context.Context, id, name, clickID string, dest *url.URL) (*url.URL, error) {
+	params, err := hlr.params.Render(ctx, name, &domain.ParamTemplateData{
+		ID:      id,
+		Date:    now().UTC().Format(time.DateOnly),
+		ClickID: clickID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(params) == 0 {
+		return dest, nil
+	}
+	return passthrough(dest, domain.PassthroughOverride, "", params)
+}
+
+// destination returns the url the visitor is sent to, which is the one of the first redirect rule matched,
+// then the one of the first geo rule matched, then the one of the variant of the visitor, or the url of the short url.
+// The visitor not on any variant is assigned to one if `assign`.
+// The 1-based positions of the redirect rule matched and the variant are returned as well, which are 0 if not used.
+func (hlr *ShortUrlHandler) destination(c *gin.Context, id string, obj *domain.GetRespDto, assign bool) (string, int, int) {
+	if rule, no := matchRule(obj.Rules, platformOf(c.Request.UserAgent())); rule != nil {
+		return rule.Url, no, 0
+	}
+	// the client ip is looked up only for the links of the geo rules
+	if len(obj.GeoRules) > 0 {
+		if rule := matchGeoRule(obj.GeoRules, hlr.geo.Locate(c.Request.Context(), c.Request)); rule != nil {
+			return rule.Url, 0, 0
+		}
+	}
+	if len(obj.Variants) > 0 {
+		if variant, no := hlr.splitter.Pick(c, id, obj.Variants, assign); variant != nil {
+			return variant.Url, 0, no
+		}
+	}
+	return obj.Url, 0, 0
+}
+
+// setCacheHeaders lets the permanent redirects be cached until the link expires, capped by MaxAge.
+// The temporary ones are revalidated every time, so that the clicks are recorded and the changes take effect,
+// and so are the permanent ones if `revalidate`.
+func (hlr *ShortUrlHandler) setCacheHeaders(c *gin.Context, code int, expireAt time.Time, private, revalidate bool) {
+	if revalidate || (code != http.StatusMovedPermanently && code != http.StatusPermanentRedirect) {
+		c.Header("Cache-Control", "private, no-cache")
+		return
+	}
+
+	maxAge := min(expireAt.Sub(now()), hlr.redirect.MaxAge).Truncate(time.Second)
+	if maxAge <= 0 {
+		c.Header("Cache-Control", "no-store")
+		return
+	}
+	visibility := "public"
+	if private {
+		visibility = "private"
+	}
+	c.Header("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(maxAge.Seconds())))
+	c.Header("Expires", now().Add(maxAge).UTC().Format(http.TimeFormat))
+}
+
+// preview shows where the short url goes without redirecting, in JSON or HTML by the Accept header
+func (hlr *ShortUrlHandler) preview(c *gin.Context, id string, obj *domain.GetRespDto) {
+	if obj.Status == domain.GetRespStatusNotFound {
 		c.JSON(http.StatusNotFound, gin.H{"error": ErrNotFound.Error()})
 		return
 	}
 
-	log.Printf("handler.Get. success redirect to: %s", obj.Url)
-	c.Redirect(http.StatusFound, obj.Url)
+	// the destination of the removed link isn't exposed any longer, nor the one not scanned yet, which might be harmful.
+	// The one of the platform is shown to the visitor.
+	// The preview never assigns the variant, which would override the one of the visitor as the cookie isn't sent to the path of PREVIEW_SUFFIX.
+	dest, _, _ := hlr.destination(c, id, obj, false)
+	if obj.Status == domain.GetRespStatusDisabled || obj.Status == domain.GetRespStatusPendingReview {
+		dest = ""
+	}
+	data := gin.H{
+		"id":        id,
+		"url":       dest,
+		"status":    obj.Status,
+		"createdAt": obj.CreatedAt,
+		"expireAt":  obj.ExpireAt,
+	}
+
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEHTML:
+		renderPage(c, http.StatusOK, hlr.templates, "preview.html", data)
+	default:
+		c.JSON(http.StatusOK, data)
+	}
+}
+
+// fallback redirects to the fallback url of the tenant if any, or responds the error page
+func (hlr *ShortUrlHandler) fallback(c *gin.Context, code int, err error, name string, id string) {
+	if fallback, ok := hlr.fallbacks.Lookup(c.Request.Host); ok {
+		c.Header("Cache-Control", "private, no-cache")
+		c.Redirect(http.StatusFound, fallback)
+		return
+	}
+	hlr.abortWithPage(c, code, err, name, id)
+}
+
+// abortWithPage responds the error page of the name to the browsers, and the error in JSON to the others
+func (hlr *ShortUrlHandler) abortWithPage(c *gin.Context, code int, err error, name string, id string) {
+	switch c.NegotiateFormat(binding.MIMEJSON, binding.MIMEHTML) {
+	case binding.MIMEHTML:
+		renderPage(c, code, hlr.templates, name, gin.H{"ID": id})
+	default:
+		c.JSON(code, gin.H{"error": err.Error()})
+	}
+}
+
+// abortWithError responds the error returned by the use case.
+// Timeouts are responded with 503 so that clients know they can retry later.
+func abortWithError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrTimeout) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": ErrServiceUnavailable.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": ErrInternalServerError.Error()})
 }
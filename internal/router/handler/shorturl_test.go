@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"bytes"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html/template"
+	"math/rand/v2"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -13,7 +19,12 @@ import (
 	"github.com/Hao1995/short-url/internal/domain"
 	"github.com/Hao1995/short-url/internal/router/handler/request"
 	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+	"github.com/Hao1995/short-url/pkg/geokit"
+	"github.com/Hao1995/short-url/pkg/geokit/geokittest"
+	"github.com/Hao1995/short-url/pkg/migrationkit/randkit"
+	"github.com/Hao1995/short-url/pkg/policykit"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -25,8 +36,15 @@ type ShortUrlHandlerTestSuite struct {
 
 	now time.Time
 
-	uc   *usecase.UseCase
-	impl *ShortUrlHandler
+	uc           *usecase.UseCase
+	params       *usecase.ParamTemplateUseCase
+	templates    *template.Template
+	interstitial *Interstitial
+	fallbacks    *Fallbacks
+	geo          *Geo
+	geoDB        *geokit.DB
+	splitter     *Splitter
+	impl         *ShortUrlHandler
 }
 
 func TestShortUrlHandlerTestSuite(t *testing.T) {
@@ -35,28 +53,70 @@ func TestShortUrlHandlerTestSuite(t *testing.T) {
 
 func (s *ShortUrlHandlerTestSuite) SetupSuite() {
 	s.now = time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC)
+	now = func() time.Time {
+		return s.now
+	}
+	newClickID = func() string {
+		return "whatever-click"
+	}
 
 	s.uc = usecase.NewUseCase(s.T())
-	s.impl = NewShortUrlHandler(s.uc)
+	s.params = usecase.NewParamTemplateUseCase(s.T())
+	var err error
+	s.templates, err = LoadTemplates("")
+	s.Require().NoError(err)
+	s.interstitial, err = NewInterstitial(InterstitialRules{Mode: domain.InterstitialNever})
+	s.Require().NoError(err)
+	s.fallbacks, err = NewFallbacks(nil)
+	s.Require().NoError(err)
+	path := filepath.Join(s.T().TempDir(), "country.mmdb")
+	s.Require().NoError(geokittest.WriteDB(path, map[string]geokit.Location{
+		"192.0.2.0/24":  {Country: "TW", Continent: "AS"},
+		"2001:db8::/32": {Country: "DE", Continent: "EU"},
+	}))
+	s.geoDB, err = geokit.Open(path)
+	s.Require().NoError(err)
+	proxies, err := geokit.NewProxies([]string{"10.0.0.0/8"})
+	s.Require().NoError(err)
+	s.geo = NewGeo(s.geoDB, proxies)
+	s.splitter = NewSplitter(SplitConfig{Secret: []byte("whatever-secret"), MaxAge: 24 * time.Hour})
+	s.impl = NewShortUrlHandler(s.uc, s.params, s.templates, s.interstitial, s.fallbacks, s.geo, s.splitter, RedirectConfig{Code: http.StatusFound, MaxAge: time.Hour})
 
 	r := gin.Default()
 	r.POST("/api/v1/urls", s.impl.Create)
 	r.GET("/:id", s.impl.Get)
+	r.HEAD("/:id", s.impl.Get)
+	r.GET("/:id/*path", s.impl.Get)
 	s.ginEngine = r
 }
 
 func (s *ShortUrlHandlerTestSuite) SetupTest() {}
 
-func (s *ShortUrlHandlerTestSuite) TearDownSubTest() {}
+func (s *ShortUrlHandlerTestSuite) TearDownSubTest() {
+	s.Require().NoError(s.interstitial.SetRules(InterstitialRules{Mode: domain.InterstitialNever}))
+	s.Require().NoError(s.fallbacks.SetURLs(nil))
+	s.geo.db = s.geoDB
+}
 
 func (s *ShortUrlHandlerTestSuite) TearDownTest() {}
 
-func (s *ShortUrlHandlerTestSuite) TearDownSuite() {}
+func (s *ShortUrlHandlerTestSuite) TearDownSuite() {
+	now = func() time.Time {
+		return time.Now()
+	}
+	newClickID = func() string {
+		return randkit.String(16)
+	}
+	randIntN = func(n int) int {
+		return rand.IntN(n)
+	}
+}
 
 func (s *ShortUrlHandlerTestSuite) TestCreate() {
 	for _, t := range []struct {
 		name    string
 		req     *request.ShortUrlCreateRequest
+		apiKey  string
 		setup   func()
 		expCode int
 		expResp string
@@ -75,10 +135,68 @@ func (s *ShortUrlHandlerTestSuite) TestCreate() {
 				}).Once().Return(&domain.CreateRespDto{
 					TargetID: "testid1",
 					ShortUrl: "http://localhost/testid1",
+					Status:   domain.LinkStatusActive,
+				}, nil)
+			},
+			expCode: 201,
+			expResp: "{\"id\":\"testid1\",\"shortUrl\":\"http://localhost/testid1\",\"status\":\"Active\"}",
+		},
+		{
+			name: "create record waiting for the review",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+			},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+				}).Once().Return(&domain.CreateRespDto{
+					TargetID: "testid1",
+					ShortUrl: "http://localhost/testid1",
+					Status:   domain.LinkStatusPendingReview,
+				}, nil)
+			},
+			expCode: 201,
+			expResp: "{\"id\":\"testid1\",\"shortUrl\":\"http://localhost/testid1\",\"status\":\"PendingReview\"}",
+		},
+		{
+			name: "create record with the api key",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+			},
+			apiKey: "whatever-key",
+			setup: func() {
+				s.uc.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+					APIKey:   "whatever-key",
+				}).Once().Return(&domain.CreateRespDto{
+					TargetID: "testid1",
+					ShortUrl: "http://localhost/testid1",
+					Status:   domain.LinkStatusActive,
 				}, nil)
 			},
 			expCode: 201,
-			expResp: "{\"id\":\"testid1\",\"shortUrl\":\"http://localhost/testid1\"}",
+			expResp: "{\"id\":\"testid1\",\"shortUrl\":\"http://localhost/testid1\",\"status\":\"Active\"}",
+		},
+		{
+			name: "creator banned, return 403",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+			},
+			apiKey: "banned-key",
+			setup: func() {
+				s.uc.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+					APIKey:   "banned-key",
+				}).Once().Return(nil, domain.ErrCreatorBanned)
+			},
+			expCode: 403,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "forbidden"),
 		},
 		{
 			name:    "failed to bind request data",
@@ -107,6 +225,184 @@ func (s *ShortUrlHandlerTestSuite) TestCreate() {
 			expCode: 500,
 			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "internal server error"),
 		},
+		{
+			name: "failed to create a short url due to timeout",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+			},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+				}).Once().Return(nil, domain.ErrTimeout)
+			},
+			expCode: 503,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "service unavailable"),
+		},
+		{
+			name: "url rejected by the policy, return 422 with the reason",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://evil.example.com/whatever1",
+				ExpireAt: s.now,
+			},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url:      "https://evil.example.com/whatever1",
+					ExpireAt: s.now,
+				}).Once().Return(nil, &policykit.Violation{Reason: policykit.ReasonHostDenied, Detail: "whatever"})
+			},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\",\"reason\":\"%s\"}", "unprocessable entity", "host_denied"),
+		},
+		{
+			name: "param template not found, return 422 with the reason",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				Template: "newsletter",
+			},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+					Template: "newsletter",
+				}).Once().Return(nil, domain.ErrUnknownTemplate)
+			},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\",\"reason\":\"%s\"}", "unprocessable entity", "unknown_template"),
+		},
+		{
+			name: "create record with the redirect rules",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				Rules: []request.RedirectRuleRequest{
+					{Platform: "ios", Url: "https://apps.example.com/whatever1"},
+					{Platform: "android", Url: "https://play.example.com/whatever1"},
+				},
+			},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+					Rules: []domain.RedirectRule{
+						{Platform: domain.PlatformIos, Url: "https://apps.example.com/whatever1"},
+						{Platform: domain.PlatformAndroid, Url: "https://play.example.com/whatever1"},
+					},
+				}).Once().Return(&domain.CreateRespDto{
+					TargetID: "testid1",
+					ShortUrl: "http://localhost/testid1",
+					Status:   domain.LinkStatusActive,
+				}, nil)
+			},
+			expCode: 201,
+			expResp: "{\"id\":\"testid1\",\"shortUrl\":\"http://localhost/testid1\",\"status\":\"Active\"}",
+		},
+		{
+			name: "create record with the variants",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				Variants: []request.VariantRequest{
+					{Url: "https://a.example.com/whatever1", Weight: 70},
+					{Url: "https://b.example.com/whatever1", Weight: 30},
+				},
+			},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+					Variants: []domain.Variant{
+						{Url: "https://a.example.com/whatever1", Weight: 70},
+						{Url: "https://b.example.com/whatever1", Weight: 30},
+					},
+				}).Once().Return(&domain.CreateRespDto{
+					TargetID: "testid1",
+					ShortUrl: "http://localhost/testid1",
+					Status:   domain.LinkStatusActive,
+				}, nil)
+			},
+			expCode: 201,
+			expResp: "{\"id\":\"testid1\",\"shortUrl\":\"http://localhost/testid1\",\"status\":\"Active\"}",
+		},
+		{
+			name: "single variant",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				Variants: []request.VariantRequest{{Url: "https://a.example.com/whatever1", Weight: 100}},
+			},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "unprocessable entity"),
+		},
+		{
+			name: "variant rejected by the use case, return 422 with the reason",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				Variants: []request.VariantRequest{
+					{Url: "https://a.example.com/whatever1", Weight: 1},
+					{Url: "https://b.example.com/whatever1", Weight: 1},
+				},
+			},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, mock.Anything).Once().Return(nil, fmt.Errorf("%w: whatever", domain.ErrInvalidVariant))
+			},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\",\"reason\":\"%s\"}", "unprocessable entity", "invalid_variant"),
+		},
+		{
+			name: "unknown platform of the rule",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				Rules:    []request.RedirectRuleRequest{{Platform: "symbian", Url: "https://example.com/symbian"}},
+			},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "unprocessable entity"),
+		},
+		{
+			name: "unknown continent of the geo rule",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				GeoRules: []request.GeoRuleRequest{{Continent: "Asia", Url: "https://asia.example.com/"}},
+			},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "unprocessable entity"),
+		},
+		{
+			name: "geo rules without the GeoIP database, return 422 with the reason",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				GeoRules: []request.GeoRuleRequest{{Country: "TW", Url: "https://tw.example.com/"}},
+			},
+			setup: func() {
+				empty, err := geokit.Open("")
+				s.Require().NoError(err)
+				s.geo.db = empty
+			},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\",\"reason\":\"%s\"}", "unprocessable entity", "geo_unavailable"),
+		},
+		{
+			name: "rule never matched, return 422 with the reason",
+			req: &request.ShortUrlCreateRequest{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				Rules: []request.RedirectRuleRequest{
+					{Platform: "ios", Url: "https://apps.example.com/whatever1"},
+					{Platform: "ios", Url: "https://apps.example.com/whatever2"},
+				},
+			},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, mock.Anything).Once().Return(nil, fmt.Errorf("%w: whatever", domain.ErrInvalidRule))
+			},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\",\"reason\":\"%s\"}", "unprocessable entity", "invalid_rule"),
+		},
 	} {
 		s.Suite.Run(t.name, func() {
 			if t.setup != nil {
@@ -116,6 +412,9 @@ func (s *ShortUrlHandlerTestSuite) TestCreate() {
 			w := httptest.NewRecorder()
 			data, _ := json.Marshal(t.req)
 			req, _ := http.NewRequest("POST", "/api/v1/urls", strings.NewReader(string(data)))
+			if t.apiKey != "" {
+				req.Header.Set(HEADER_API_KEY, t.apiKey)
+			}
 			s.ginEngine.ServeHTTP(w, req)
 
 			s.Equal(t.expCode, w.Code)
@@ -124,10 +423,18 @@ func (s *ShortUrlHandlerTestSuite) TestCreate() {
 	}
 }
 
+func (s *ShortUrlHandlerTestSuite) render(name string, data any) string {
+	var buf bytes.Buffer
+	s.Require().NoError(s.templates.ExecuteTemplate(&buf, name, data))
+	return buf.String()
+}
+
 func (s *ShortUrlHandlerTestSuite) TestGet() {
 	for _, t := range []struct {
 		name        string
 		req         *request.ShortUrlGetRequest
+		host        string
+		accept      string
 		setup       func()
 		expCode     int
 		expResp     string
@@ -145,11 +452,62 @@ func (s *ShortUrlHandlerTestSuite) TestGet() {
 						Url:      "https://example.com/whatever1",
 						ExpireAt: s.now,
 					}, nil)
+				s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1", UserAgent: "whatever-agent"}).Once()
 			},
 			expCode:     302,
 			expResp:     "<a href=\"https://example.com/whatever1\">Found</a>.\n\n",
 			expLocation: "https://example.com/whatever1",
 		},
+		{
+			name: "show the interstitial page of the link",
+			req:  &request.ShortUrlGetRequest{ID: "whatever1"},
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").
+					Once().
+					Return(&domain.GetRespDto{
+						Status:       domain.GetRespStatusNormal,
+						Url:          "https://example.com/whatever1?a=1&b=2",
+						ExpireAt:     s.now,
+						Interstitial: domain.InterstitialAlways,
+					}, nil)
+				s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1", UserAgent: "whatever-agent"}).Once()
+			},
+			expCode: 200,
+			expResp: s.render("interstitial.html", gin.H{
+				"ID":    "whatever1",
+				"URL":   "https://example.com/whatever1?a=1&b=2",
+				"Host":  "example.com",
+				"Delay": 0,
+			}),
+			expLocation: "",
+		},
+		{
+			name: "show the interstitial page of the unverified destination with the countdown",
+			req:  &request.ShortUrlGetRequest{ID: "whatever1"},
+			setup: func() {
+				s.Require().NoError(s.interstitial.SetRules(InterstitialRules{
+					Mode:       domain.InterstitialUnverified,
+					AllowHosts: []string{"*.example.com"},
+					Delay:      5 * time.Second,
+				}))
+				s.uc.On("Get", mock.Anything, "whatever1").
+					Once().
+					Return(&domain.GetRespDto{
+						Status:   domain.GetRespStatusNormal,
+						Url:      "https://example.org/whatever1",
+						ExpireAt: s.now,
+					}, nil)
+				s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1", UserAgent: "whatever-agent"}).Once()
+			},
+			expCode: 200,
+			expResp: s.render("interstitial.html", gin.H{
+				"ID":    "whatever1",
+				"URL":   "https://example.org/whatever1",
+				"Host":  "example.org",
+				"Delay": 5,
+			}),
+			expLocation: "",
+		},
 		{
 			name: "record not found, return 404",
 			req:  &request.ShortUrlGetRequest{ID: "whatever1"},
@@ -167,7 +525,34 @@ func (s *ShortUrlHandlerTestSuite) TestGet() {
 			expLocation: "",
 		},
 		{
-			name: "record is expired, return 404",
+			name:   "record not found, return 404 with the not found page",
+			req:    &request.ShortUrlGetRequest{ID: "whatever1"},
+			accept: "text/html,application/xhtml+xml,*/*;q=0.8",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").
+					Once().
+					Return(&domain.GetRespDto{Status: domain.GetRespStatusNotFound}, nil)
+			},
+			expCode:     404,
+			expResp:     s.render("notfound.html", gin.H{"ID": "whatever1"}),
+			expLocation: "",
+		},
+		{
+			name: "record not found, redirect to the fallback url of the tenant",
+			req:  &request.ShortUrlGetRequest{ID: "whatever1"},
+			host: "Go.Example.com:8080",
+			setup: func() {
+				s.Require().NoError(s.fallbacks.SetURLs(map[string]string{"go.example.com": "https://example.com/home"}))
+				s.uc.On("Get", mock.Anything, "whatever1").
+					Once().
+					Return(&domain.GetRespDto{Status: domain.GetRespStatusNotFound}, nil)
+			},
+			expCode:     302,
+			expResp:     "<a href=\"https://example.com/home\">Found</a>.\n\n",
+			expLocation: "https://example.com/home",
+		},
+		{
+			name: "record is expired, return 410",
 			req:  &request.ShortUrlGetRequest{ID: "whatever1"},
 			setup: func() {
 				s.uc.On("Get", mock.Anything, "whatever1").
@@ -178,8 +563,70 @@ func (s *ShortUrlHandlerTestSuite) TestGet() {
 						ExpireAt: s.now,
 					}, nil)
 			},
-			expCode:     404,
-			expResp:     fmt.Sprintf("{\"error\":\"%s\"}", "not found"),
+			expCode:     410,
+			expResp:     fmt.Sprintf("{\"error\":\"%s\"}", "gone"),
+			expLocation: "",
+		},
+		{
+			name:   "record is expired, return 410 with the expired page",
+			req:    &request.ShortUrlGetRequest{ID: "whatever1"},
+			accept: "text/html,application/xhtml+xml,*/*;q=0.8",
+			setup: func() {
+				s.Require().NoError(s.fallbacks.SetURLs(map[string]string{"go.example.com": "https://example.com/home"}))
+				s.uc.On("Get", mock.Anything, "whatever1").
+					Once().
+					Return(&domain.GetRespDto{
+						Status:   domain.GetRespStatusExpired,
+						Url:      "https://example.com/whatever1",
+						ExpireAt: s.now,
+					}, nil)
+			},
+			expCode:     410,
+			expResp:     s.render("expired.html", gin.H{"ID": "whatever1"}),
+			expLocation: "",
+		},
+		{
+			name:   "record is disabled, return 410 with the removed page",
+			req:    &request.ShortUrlGetRequest{ID: "whatever1"},
+			host:   "go.example.com",
+			accept: "text/html,application/xhtml+xml,*/*;q=0.8",
+			setup: func() {
+				s.Require().NoError(s.fallbacks.SetURLs(map[string]string{"go.example.com": "https://example.com/home"}))
+				s.uc.On("Get", mock.Anything, "whatever1").
+					Once().
+					Return(&domain.GetRespDto{
+						Status:     domain.GetRespStatusDisabled,
+						Url:        "https://example.com/whatever1",
+						ExpireAt:   s.now,
+						LinkStatus: domain.LinkStatusDisabled,
+					}, nil)
+			},
+			expCode:     410,
+			expResp:     s.render("removed.html", gin.H{"ID": "whatever1"}),
+			expLocation: "",
+		},
+		{
+			name: "failed to get record due to timeout, return 503",
+			req:  &request.ShortUrlGetRequest{ID: "whatever1"},
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").
+					Once().
+					Return(nil, domain.ErrTimeout)
+			},
+			expCode:     503,
+			expResp:     fmt.Sprintf("{\"error\":\"%s\"}", "service unavailable"),
+			expLocation: "",
+		},
+		{
+			name: "failed to get record due to unknown error, return 500",
+			req:  &request.ShortUrlGetRequest{ID: "whatever1"},
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").
+					Once().
+					Return(nil, errors.New("whatever"))
+			},
+			expCode:     500,
+			expResp:     fmt.Sprintf("{\"error\":\"%s\"}", "internal server error"),
 			expLocation: "",
 		},
 	} {
@@ -190,11 +637,690 @@ func (s *ShortUrlHandlerTestSuite) TestGet() {
 
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest("GET", "/"+t.req.ID, nil)
+			req.Header.Set("User-Agent", "whatever-agent")
+			if t.host != "" {
+				req.Host = t.host
+			}
+			if t.accept != "" {
+				req.Header.Set("Accept", t.accept)
+			}
 			s.ginEngine.ServeHTTP(w, req)
 
 			s.Equal(t.expCode, w.Code)
 			s.Equal(t.expResp, w.Body.String())
 			s.Equal(t.expLocation, w.Header().Get("location"))
+			if strings.HasPrefix(w.Header().Get("Content-Type"), binding.MIMEHTML) && t.expCode != http.StatusFound {
+				s.Equal(contentSecurityPolicy, w.Header().Get("Content-Security-Policy"))
+			}
+		})
+	}
+}
+
+func (s *ShortUrlHandlerTestSuite) TestPreview() {
+	variants := []domain.Variant{
+		{Url: "https://a.example.com/whatever1", Weight: 50},
+		{Url: "https://b.example.com/whatever1", Weight: 50},
+	}
+	for _, t := range []struct {
+		name    string
+		path    string
+		accept  string
+		cookie  string
+		setup   func()
+		expCode int
+		expResp string
+	}{
+		{
+			name: "preview in json by the suffix",
+			path: "/whatever1+",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:    domain.GetRespStatusNormal,
+					Url:       "https://example.com/whatever1",
+					ExpireAt:  s.now,
+					CreatedAt: s.now,
+				}, nil)
+			},
+			expCode: 200,
+			expResp: "{\"createdAt\":\"2025-02-10T08:30:15Z\",\"expireAt\":\"2025-02-10T08:30:15Z\",\"id\":\"whatever1\",\"status\":\"Normal\",\"url\":\"https://example.com/whatever1\"}",
+		},
+		{
+			name:   "preview in html by the query",
+			path:   "/whatever1?preview=1",
+			accept: "text/html,application/xhtml+xml,*/*;q=0.8",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:    domain.GetRespStatusExpired,
+					Url:       "https://example.com/whatever1",
+					ExpireAt:  s.now,
+					CreatedAt: s.now,
+				}, nil)
+			},
+			expCode: 200,
+			expResp: s.render("preview.html", gin.H{
+				"id":        "whatever1",
+				"url":       "https://example.com/whatever1",
+				"status":    domain.GetRespStatusExpired,
+				"createdAt": s.now,
+				"expireAt":  s.now,
+			}),
+		},
+		{
+			name: "hide the destination of the disabled link",
+			path: "/whatever1+",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:     domain.GetRespStatusDisabled,
+					Url:        "https://example.com/whatever1",
+					ExpireAt:   s.now,
+					CreatedAt:  s.now,
+					LinkStatus: domain.LinkStatusDisabled,
+				}, nil)
+			},
+			expCode: 200,
+			expResp: "{\"createdAt\":\"2025-02-10T08:30:15Z\",\"expireAt\":\"2025-02-10T08:30:15Z\",\"id\":\"whatever1\",\"status\":\"Disabled\",\"url\":\"\"}",
+		},
+		{
+			name: "hide the destination of the link pending review",
+			path: "/whatever1+",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:     domain.GetRespStatusPendingReview,
+					Url:        "https://example.com/whatever1",
+					ExpireAt:   s.now,
+					CreatedAt:  s.now,
+					LinkStatus: domain.LinkStatusPendingReview,
+				}, nil)
+			},
+			expCode: 200,
+			expResp: "{\"createdAt\":\"2025-02-10T08:30:15Z\",\"expireAt\":\"2025-02-10T08:30:15Z\",\"id\":\"whatever1\",\"status\":\"PendingReview\",\"url\":\"\"}",
+		},
+		{
+			name:   "preview the variant of the visitor",
+			path:   "/whatever1?preview=1",
+			cookie: s.splitter.sign("whatever1", 2),
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:    domain.GetRespStatusNormal,
+					Url:       "https://example.com/whatever1",
+					ExpireAt:  s.now,
+					CreatedAt: s.now,
+					Variants:  variants,
+				}, nil)
+			},
+			expCode: 200,
+			expResp: "{\"createdAt\":\"2025-02-10T08:30:15Z\",\"expireAt\":\"2025-02-10T08:30:15Z\",\"id\":\"whatever1\",\"status\":\"Normal\",\"url\":\"https://b.example.com/whatever1\"}",
+		},
+		{
+			name: "never assign the visitor not on any variant",
+			path: "/whatever1+",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:    domain.GetRespStatusNormal,
+					Url:       "https://example.com/whatever1",
+					ExpireAt:  s.now,
+					CreatedAt: s.now,
+					Variants:  variants,
+				}, nil)
+			},
+			expCode: 200,
+			expResp: "{\"createdAt\":\"2025-02-10T08:30:15Z\",\"expireAt\":\"2025-02-10T08:30:15Z\",\"id\":\"whatever1\",\"status\":\"Normal\",\"url\":\"https://example.com/whatever1\"}",
+		},
+		{
+			name: "record not found, return 404",
+			path: "/whatever1+",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{Status: domain.GetRespStatusNotFound}, nil)
+			},
+			expCode: 404,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "not found"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", t.path, nil)
+			if t.accept != "" {
+				req.Header.Set("Accept", t.accept)
+			}
+			if t.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: COOKIE_VARIANT, Value: t.cookie})
+			}
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.Equal(t.expResp, w.Body.String())
+			s.Empty(w.Header().Get("location"))
+			s.Empty(w.Header().Get("Set-Cookie"))
+		})
+	}
+}
+
+func (s *ShortUrlHandlerTestSuite) TestRedirect() {
+	for _, t := range []struct {
+		name       string
+		method     string
+		setup      func()
+		expCode    int
+		expHeaders map[string]string
+	}{
+		{
+			name:   "redirect temporarily by default without caching",
+			method: "GET",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:   domain.GetRespStatusNormal,
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now.Add(24 * time.Hour),
+				}, nil)
+				s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1"}).Once()
+			},
+			expCode:    302,
+			expHeaders: map[string]string{"Cache-Control": "private, no-cache", "Expires": ""},
+		},
+		{
+			name:   "redirect permanently by the link, cached for the max age",
+			method: "GET",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:       domain.GetRespStatusNormal,
+					Url:          "https://example.com/whatever1",
+					ExpireAt:     s.now.Add(24 * time.Hour),
+					RedirectCode: 301,
+				}, nil)
+				s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1"}).Once()
+			},
+			expCode:    301,
+			expHeaders: map[string]string{"Cache-Control": "public, max-age=3600", "Expires": "Mon, 10 Feb 2025 09:30:15 GMT"},
+		},
+		{
+			name:   "cache the permanent redirect until the link expires",
+			method: "GET",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:       domain.GetRespStatusNormal,
+					Url:          "https://example.com/whatever1",
+					ExpireAt:     s.now.Add(10*time.Minute + 500*time.Millisecond),
+					RedirectCode: 308,
+				}, nil)
+				s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1"}).Once()
+			},
+			expCode:    308,
+			expHeaders: map[string]string{"Cache-Control": "public, max-age=600", "Expires": "Mon, 10 Feb 2025 08:40:15 GMT"},
+		},
+		{
+			name:   "not cache the permanent redirect of the param template",
+			method: "GET",
+			setup: func() {
+				s.params.On("Render", mock.Anything, "newsletter", mock.Anything).Once().Return(url.Values{"click": {"whatever-click"}}, nil)
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:       domain.GetRespStatusNormal,
+					Url:          "https://example.com/whatever1",
+					ExpireAt:     s.now.Add(24 * time.Hour),
+					RedirectCode: 301,
+					Template:     "newsletter",
+				}, nil)
+				s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1", ClickID: "whatever-click"}).Once()
+			},
+			expCode: 301,
+			expHeaders: map[string]string{
+				"Location":      "https://example.com/whatever1?click=whatever-click",
+				"Cache-Control": "private, no-cache",
+				"Expires":       "",
+			},
+		},
+		{
+			name:   "not cache the permanent redirect about to expire",
+			method: "GET",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:       domain.GetRespStatusNormal,
+					Url:          "https://example.com/whatever1",
+					ExpireAt:     s.now.Add(500 * time.Millisecond),
+					RedirectCode: 308,
+				}, nil)
+				s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1"}).Once()
+			},
+			expCode:    308,
+			expHeaders: map[string]string{"Cache-Control": "no-store", "Expires": ""},
+		},
+		{
+			name:   "serve head without recording the click",
+			method: "HEAD",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+					Status:       domain.GetRespStatusNormal,
+					Url:          "https://example.com/whatever1",
+					ExpireAt:     s.now.Add(24 * time.Hour),
+					RedirectCode: 307,
+				}, nil)
+			},
+			expCode:    307,
+			expHeaders: map[string]string{"Location": "https://example.com/whatever1", "Cache-Control": "private, no-cache"},
+		},
+		{
+			name:   "serve head of the record not found",
+			method: "HEAD",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{Status: domain.GetRespStatusNotFound}, nil)
+			},
+			expCode: 404,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(t.method, "/whatever1", nil)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			for k, v := range t.expHeaders {
+				s.Equal(v, w.Header().Get(k), k)
+			}
+		})
+	}
+}
+
+func (s *ShortUrlHandlerTestSuite) TestPassthrough() {
+	for _, t := range []struct {
+		name        string
+		path        string
+		passthrough domain.Passthrough
+		click       bool
+		expCode     int
+		expLocation string
+	}{
+		{
+			name:        "forward the path and the query",
+			path:        "/whatever1/extra/a%20b%2Fc?utm_source=x&a=3",
+			passthrough: domain.PassthroughOverride,
+			click:       true,
+			expCode:     302,
+			expLocation: "https://example.com/app/extra/a%20b%2Fc?b=2&a=3&utm_source=x",
+		},
+		{
+			name:        "keep the query parameters of the destination",
+			path:        "/whatever1?a=3&c=4",
+			passthrough: domain.PassthroughKeep,
+			click:       true,
+			expCode:     302,
+			expLocation: "https://example.com/app?a=1&b=2&c=4",
+		},
+		{
+			name:        "forward the preview query not turning on the preview",
+			path:        "/whatever1?preview=whatever",
+			passthrough: domain.PassthroughAppend,
+			click:       true,
+			expCode:     302,
+			expLocation: "https://example.com/app?a=1&b=2&preview=whatever",
+		},
+		{
+			name:        "reject the dot segments",
+			path:        "/whatever1/%2e%2e/admin",
+			passthrough: domain.PassthroughKeep,
+			expCode:     400,
+		},
+		{
+			name:        "path of the link without passthrough, return 404",
+			path:        "/whatever1/extra",
+			expCode:     404,
+			expLocation: "",
+		},
+		{
+			name:        "ignore the query of the link without passthrough",
+			path:        "/whatever1/?utm_source=x",
+			click:       true,
+			expCode:     302,
+			expLocation: "https://example.com/app?a=1&b=2",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+				Status:      domain.GetRespStatusNormal,
+				Url:         "https://example.com/app?a=1&b=2",
+				ExpireAt:    s.now,
+				Passthrough: t.passthrough,
+			}, nil)
+			if t.click {
+				s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1"}).Once()
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", t.path, nil)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.Equal(t.expLocation, w.Header().Get("Location"))
+		})
+	}
+}
+
+func (s *ShortUrlHandlerTestSuite) TestParamTemplate() {
+	for _, t := range []struct {
+		name        string
+		path        string
+		passthrough domain.Passthrough
+		setup       func()
+		expLocation string
+	}{
+		{
+			name:        "set the params over the ones of the destination and the request",
+			path:        "/whatever1?utm_source=x&a=3",
+			passthrough: domain.PassthroughKeep,
+			setup: func() {
+				s.params.On("Render", mock.Anything, "newsletter", &domain.ParamTemplateData{
+					ID: "whatever1", Date: "2025-02-10", ClickID: "whatever-click",
+				}).Once().Return(url.Values{"utm_source": {"newsletter"}, "b": {"whatever-click"}}, nil)
+			},
+			expLocation: "https://example.com/app?a=1&b=whatever-click&utm_source=newsletter",
+		},
+		{
+			name: "redirect without the params if it failed to render them",
+			path: "/whatever1",
+			setup: func() {
+				s.params.On("Render", mock.Anything, "newsletter", mock.Anything).Once().Return(nil, domain.ErrTimeout)
+			},
+			expLocation: "https://example.com/app?a=1&b=2",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			t.setup()
+			s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+				Status:      domain.GetRespStatusNormal,
+				Url:         "https://example.com/app?a=1&b=2",
+				ExpireAt:    s.now,
+				Passthrough: t.passthrough,
+				Template:    "newsletter",
+			}, nil)
+			s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1", ClickID: "whatever-click"}).Once()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", t.path, nil)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(http.StatusFound, w.Code)
+			s.Equal(t.expLocation, w.Header().Get("Location"))
+		})
+	}
+}
+
+func (s *ShortUrlHandlerTestSuite) TestRedirectRule() {
+	iPhone := "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1"
+	android := "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36"
+	windows := "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36"
+	for _, t := range []struct {
+		name        string
+		userAgent   string
+		expRule     int
+		expLocation string
+	}{
+		{
+			name:        "send the iOS users to the app store",
+			userAgent:   iPhone,
+			expRule:     1,
+			expLocation: "https://apps.example.com/whatever1",
+		},
+		{
+			name:        "send the Android users to the mobile one",
+			userAgent:   android,
+			expRule:     2,
+			expLocation: "https://m.example.com/whatever1",
+		},
+		{
+			name:        "send the others to the url of the short url",
+			userAgent:   windows,
+			expLocation: "https://example.com/whatever1",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+				Status:   domain.GetRespStatusNormal,
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				Rules: []domain.RedirectRule{
+					{Platform: domain.PlatformIos, Url: "https://apps.example.com/whatever1"},
+					{Platform: domain.PlatformMobile, Url: "https://m.example.com/whatever1"},
+				},
+			}, nil)
+			s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1", Rule: t.expRule, UserAgent: t.userAgent}).Once()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/whatever1", nil)
+			req.Header.Set("User-Agent", t.userAgent)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(http.StatusFound, w.Code)
+			s.Equal(t.expLocation, w.Header().Get("Location"))
+			s.Equal("User-Agent", w.Header().Get("Vary"))
+		})
+	}
+}
+
+func (s *ShortUrlHandlerTestSuite) TestGeoRule() {
+	for _, t := range []struct {
+		name        string
+		remoteAddr  string
+		forwarded   string
+		userAgent   string
+		expRule     int
+		expLocation string
+	}{
+		{
+			name:        "send the visitors to the site of the country",
+			remoteAddr:  "192.0.2.10:1234",
+			expLocation: "https://tw.example.com/whatever1",
+		},
+		{
+			name:        "send the visitors behind the trusted proxy to the site of the continent",
+			remoteAddr:  "10.0.0.1:1234",
+			forwarded:   "2001:db8::1",
+			expLocation: "https://eu.example.com/whatever1",
+		},
+		{
+			name:        "ignore the forwarded address from the untrusted peer",
+			remoteAddr:  "198.51.100.1:1234",
+			forwarded:   "192.0.2.10",
+			expLocation: "https://example.com/whatever1",
+		},
+		{
+			name:        "match the redirect rules before the geo rules",
+			remoteAddr:  "192.0.2.10:1234",
+			userAgent:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X)",
+			expRule:     1,
+			expLocation: "https://apps.example.com/whatever1",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+				Status:       domain.GetRespStatusNormal,
+				Url:          "https://example.com/whatever1",
+				ExpireAt:     s.now.Add(24 * time.Hour),
+				RedirectCode: http.StatusMovedPermanently,
+				Rules:        []domain.RedirectRule{{Platform: domain.PlatformIos, Url: "https://apps.example.com/whatever1"}},
+				GeoRules: []domain.GeoRule{
+					{Country: "TW", Url: "https://tw.example.com/whatever1"},
+					{Continent: domain.ContinentEU, Url: "https://eu.example.com/whatever1"},
+				},
+			}, nil)
+			s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1", Rule: t.expRule, UserAgent: t.userAgent}).Once()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/whatever1", nil)
+			req.RemoteAddr = t.remoteAddr
+			if t.forwarded != "" {
+				req.Header.Set(geokit.HeaderForwardedFor, t.forwarded)
+			}
+			req.Header.Set("User-Agent", t.userAgent)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(http.StatusMovedPermanently, w.Code)
+			s.Equal(t.expLocation, w.Header().Get("Location"))
+			// the shared caches can't tell the visitors of the other countries apart
+			s.Equal("private, max-age=3600", w.Header().Get("Cache-Control"))
+		})
+	}
+}
+
+func (s *ShortUrlHandlerTestSuite) TestSplit() {
+	for _, t := range []struct {
+		name        string
+		cookie      string
+		userAgent   string
+		tls         bool
+		rand        int
+		expRule     int
+		expVariant  int
+		expLocation string
+		expCookie   string
+	}{
+		{
+			name:        "assign the new visitor by weight",
+			rand:        75,
+			expVariant:  2,
+			expLocation: "https://b.example.com/whatever1",
+			expCookie:   s.splitter.sign("whatever1", 2),
+		},
+		{
+			name:        "assign the new visitor over https by the secure cookie",
+			tls:         true,
+			rand:        75,
+			expVariant:  2,
+			expLocation: "https://b.example.com/whatever1",
+			expCookie:   s.splitter.sign("whatever1", 2),
+		},
+		{
+			name:        "keep the visitor on the variant in the cookie",
+			cookie:      s.splitter.sign("whatever1", 3),
+			expVariant:  3,
+			expLocation: "https://c.example.com/whatever1",
+		},
+		{
+			name:        "assign the visitor of the forged cookie again",
+			cookie:      "3.whatever",
+			expVariant:  1,
+			expLocation: "https://a.example.com/whatever1",
+			expCookie:   s.splitter.sign("whatever1", 1),
+		},
+		{
+			name:        "assign the visitor of the cookie of the other link again",
+			cookie:      s.splitter.sign("whatever2", 3),
+			expVariant:  1,
+			expLocation: "https://a.example.com/whatever1",
+			expCookie:   s.splitter.sign("whatever1", 1),
+		},
+		{
+			name:        "assign the visitor of the variant out of range again",
+			cookie:      s.splitter.sign("whatever1", 4),
+			rand:        99,
+			expVariant:  3,
+			expLocation: "https://c.example.com/whatever1",
+			expCookie:   s.splitter.sign("whatever1", 3),
+		},
+		{
+			name:        "match the redirect rules before the variants",
+			userAgent:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X)",
+			expRule:     1,
+			expLocation: "https://apps.example.com/whatever1",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			randIntN = func(n int) int {
+				s.Equal(100, n)
+				return t.rand
+			}
+			s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+				Status:       domain.GetRespStatusNormal,
+				Url:          "https://example.com/whatever1",
+				ExpireAt:     s.now.Add(24 * time.Hour),
+				RedirectCode: http.StatusMovedPermanently,
+				Rules:        []domain.RedirectRule{{Platform: domain.PlatformIos, Url: "https://apps.example.com/whatever1"}},
+				Variants: []domain.Variant{
+					{Url: "https://a.example.com/whatever1", Weight: 70},
+					{Url: "https://b.example.com/whatever1", Weight: 20},
+					{Url: "https://c.example.com/whatever1", Weight: 10},
+				},
+			}, nil)
+			s.uc.On("Click", mock.Anything, &domain.ClickDto{TargetID: "whatever1", Rule: t.expRule, Variant: t.expVariant, UserAgent: t.userAgent}).Once()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/whatever1", nil)
+			if t.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: COOKIE_VARIANT, Value: t.cookie})
+			}
+			req.Header.Set("User-Agent", t.userAgent)
+			if t.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(http.StatusMovedPermanently, w.Code)
+			s.Equal(t.expLocation, w.Header().Get("Location"))
+			// the shared caches must not hand the cookie of a visitor to the others
+			s.Equal("private, max-age=3600", w.Header().Get("Cache-Control"))
+			if t.expCookie == "" {
+				s.Empty(w.Header().Get("Set-Cookie"))
+				return
+			}
+			attrs := "HttpOnly"
+			if t.tls {
+				attrs += "; Secure"
+			}
+			s.Equal(fmt.Sprintf("%s=%s; Path=/whatever1; Max-Age=86400; %s; SameSite=Lax", COOKIE_VARIANT, t.expCookie, attrs), w.Header().Get("Set-Cookie"))
+		})
+	}
+}
+
+func (s *ShortUrlHandlerTestSuite) TestSplitNeverAssigned() {
+	for _, t := range []struct {
+		name        string
+		method      string
+		path        string
+		expCode     int
+		expLocation string
+	}{
+		{
+			name:        "never assign the link checker",
+			method:      "HEAD",
+			path:        "/whatever1",
+			expCode:     http.StatusMovedPermanently,
+			expLocation: "https://example.com/whatever1",
+		},
+		{
+			name:    "never assign the visitor of the path of the link without passthrough",
+			method:  "GET",
+			path:    "/whatever1/junk",
+			expCode: http.StatusNotFound,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			randIntN = func(n int) int {
+				s.Fail("the variant is picked")
+				return 0
+			}
+			s.uc.On("Get", mock.Anything, "whatever1").Once().Return(&domain.GetRespDto{
+				Status:       domain.GetRespStatusNormal,
+				Url:          "https://example.com/whatever1",
+				ExpireAt:     s.now.Add(24 * time.Hour),
+				RedirectCode: http.StatusMovedPermanently,
+				Variants: []domain.Variant{
+					{Url: "https://a.example.com/whatever1", Weight: 70},
+					{Url: "https://b.example.com/whatever1", Weight: 30},
+				},
+			}, nil)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(t.method, t.path, nil)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.Equal(t.expLocation, w.Header().Get("Location"))
+			s.Empty(w.Header().Get("Set-Cookie"))
 		})
 	}
 }
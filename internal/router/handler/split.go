@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/router/handler/request"
+
+	"github.com/gin-gonic/gin"
+)
+
+// COOKIE_VARIANT keeps the variant the visitor is assigned to, which is scoped to the path of the link
+const COOKIE_VARIANT = "variant"
+
+// SplitConfig is the config of the split links
+type SplitConfig struct {
+	// Secret signs the variant cookies, so that the visitors can't pick the variants by themselves
+	Secret []byte
+	// MaxAge is how long the visitors stay on their variants
+	MaxAge time.Duration
+	// Secure sends the cookies over HTTPS only, e.g. when the short urls are https behind the TLS terminating proxies.
+	// The cookies of the requests over TLS are always secure.
+	Secure bool
+}
+
+// Splitter assigns the visitors to the variants of the split links by weight, and keeps them there by a signed cookie
+type Splitter struct {
+	cfg SplitConfig
+}
+
+// NewSplitter generates the splitter, the instances behind the same host must share the secret to keep the visitors on their variants
+func NewSplitter(cfg SplitConfig) *Splitter {
+	return &Splitter{cfg: cfg}
+}
+
+// Pick returns the variant of the visitor and its 1-based position. The one in the cookie is kept if it's signed for the link,
+// otherwise one is picked by weight and set in the cookie if `assign`, or nil is returned.
+func (s *Splitter) Pick(c *gin.Context, id string, variants []domain.Variant, assign bool) (*domain.Variant, int) {
+	if cookie, err := c.Request.Cookie(COOKIE_VARIANT); err == nil {
+		if no, ok := s.verify(id, cookie.Value); ok && no <= len(variants) {
+			return &variants[no-1], no
+		}
+	}
+	if !assign {
+		return nil, 0
+	}
+
+	no := pickVariant(variants)
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     COOKIE_VARIANT,
+		Value:    s.sign(id, no),
+		Path:     "/" + id,
+		MaxAge:   int(s.cfg.MaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   s.cfg.Secure || c.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return &variants[no-1], no
+}
+
+// sign returns the cookie value of the variant of the link, which is `<no>.<signature>`
+func (s *Splitter) sign(id string, no int) string {
+	value := strconv.Itoa(no)
+	return value + "." + s.signature(id, value)
+}
+
+// verify returns the variant in the cookie value if it's signed for the link
+func (s *Splitter) verify(id, cookie string) (int, bool) {
+	value, sig, ok := strings.Cut(cookie, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(s.signature(id, value))) {
+		return 0, false
+	}
+	no, err := strconv.Atoi(value)
+	if err != nil || no < 1 {
+		return 0, false
+	}
+	return no, true
+}
+
+// signature binds the variant to the link, so that the cookie of a link is useless to the others
+func (s *Splitter) signature(id, value string) string {
+	mac := hmac.New(sha256.New, s.cfg.Secret)
+	mac.Write([]byte(id + ":" + value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// pickVariant picks the 1-based position of a variant at random in proportion to the weights
+func pickVariant(variants []domain.Variant) int {
+	total := 0
+	for _, variant := range variants {
+		total += variant.Weight
+	}
+	n := randIntN(total)
+	for i, variant := range variants {
+		if n < variant.Weight {
+			return i + 1
+		}
+		n -= variant.Weight
+	}
+	return len(variants)
+}
+
+// splitVariants converts the variants of the request to the domain ones in order
+func splitVariants(reqs []request.VariantRequest) []domain.Variant {
+	if len(reqs) == 0 {
+		return nil
+	}
+	variants := make([]domain.Variant, len(reqs))
+	for i, req := range reqs {
+		variants[i] = domain.Variant{Url: req.Url, Weight: req.Weight}
+	}
+	return variants
+}
@@ -0,0 +1,16 @@
+// counts down the seconds until the meta refresh redirects, the page works without it
+(function () {
+  var el = document.getElementById("countdown");
+  if (!el) {
+    return;
+  }
+  var seconds = parseInt(el.getAttribute("data-seconds"), 10);
+  var timer = setInterval(function () {
+    seconds -= 1;
+    if (seconds <= 0) {
+      seconds = 0;
+      clearInterval(timer);
+    }
+    el.textContent = seconds;
+  }, 1000);
+})();
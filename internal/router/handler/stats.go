@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/router/handler/request"
+	"github.com/Hao1995/short-url/internal/usecase"
+	"github.com/Hao1995/short-url/pkg/logkit"
+	"github.com/Hao1995/short-url/pkg/tracekit"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	uc usecase.AdminUseCase
+}
+
+func NewStatsHandler(uc usecase.AdminUseCase) *StatsHandler {
+	return &StatsHandler{
+		uc: uc,
+	}
+}
+
+// Get breaks down the clicks of the short url by the variant, the first one of variant 0 is the clicks of the url and the rules
+func (hlr *StatsHandler) Get(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "StatsHandler.Get")
+	defer span.End()
+
+	var uri request.StatsUriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		slog.InfoContext(ctx, "handler.Get. failed to bind uri", logkit.Err(err))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+		return
+	}
+
+	objs, err := hlr.uc.Stats(ctx, uri.ID)
+	if errors.Is(err, domain.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": ErrNotFound.Error()})
+		return
+	} else if err != nil {
+		tracekit.RecordError(span, err)
+		abortWithError(c, err)
+		return
+	}
+
+	var total int64
+	variants := make([]gin.H, len(objs))
+	for i, obj := range objs {
+		total += obj.Clicks
+		variants[i] = gin.H{
+			"variant": obj.Variant,
+			"url":     obj.Url,
+			"weight":  obj.Weight,
+			"clicks":  obj.Clicks,
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":       uri.ID,
+		"clicks":   total,
+		"variants": variants,
+	})
+}
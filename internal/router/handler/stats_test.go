@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+	"github.com/gin-gonic/gin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatsHandlerTestSuite struct {
+	suite.Suite
+	ginEngine *gin.Engine
+
+	uc   *usecase.AdminUseCase
+	impl *StatsHandler
+}
+
+func TestStatsHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(StatsHandlerTestSuite))
+}
+
+func (s *StatsHandlerTestSuite) SetupSuite() {
+	s.uc = usecase.NewAdminUseCase(s.T())
+	s.impl = NewStatsHandler(s.uc)
+
+	r := gin.Default()
+	r.GET("/api/v1/urls/:id/stats", s.impl.Get)
+	s.ginEngine = r
+}
+
+func (s *StatsHandlerTestSuite) TestGet() {
+	for _, t := range []struct {
+		name    string
+		setup   func()
+		expCode int
+		expResp string
+	}{
+		{
+			name: "break down the clicks by the variant",
+			setup: func() {
+				s.uc.On("Stats", mock.Anything, "testid1").Once().Return([]*domain.VariantStatsDto{
+					{Url: "https://example.com/whatever1", Clicks: 5},
+					{Variant: 1, Url: "https://a.example.com/whatever1", Weight: 70, Clicks: 70},
+					{Variant: 2, Url: "https://b.example.com/whatever1", Weight: 30, Clicks: 25},
+				}, nil)
+			},
+			expCode: 200,
+			expResp: "{\"clicks\":100,\"id\":\"testid1\",\"variants\":[" +
+				"{\"clicks\":5,\"url\":\"https://example.com/whatever1\",\"variant\":0,\"weight\":0}," +
+				"{\"clicks\":70,\"url\":\"https://a.example.com/whatever1\",\"variant\":1,\"weight\":70}," +
+				"{\"clicks\":25,\"url\":\"https://b.example.com/whatever1\",\"variant\":2,\"weight\":30}]}",
+		},
+		{
+			name: "record not found, return 404",
+			setup: func() {
+				s.uc.On("Stats", mock.Anything, "testid1").Once().Return(nil, domain.ErrRecordNotFound)
+			},
+			expCode: 404,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "not found"),
+		},
+		{
+			name: "failed to count the clicks",
+			setup: func() {
+				s.uc.On("Stats", mock.Anything, "testid1").Once().Return(nil, errors.New("whatever"))
+			},
+			expCode: 500,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "internal server error"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			t.setup()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/api/v1/urls/testid1/stats", nil)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.Equal(t.expResp, w.Body.String())
+		})
+	}
+}
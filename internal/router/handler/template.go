@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/router/handler/request"
+	"github.com/Hao1995/short-url/internal/usecase"
+	"github.com/Hao1995/short-url/pkg/logkit"
+	"github.com/Hao1995/short-url/pkg/tracekit"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ParamTemplateHandler struct {
+	uc usecase.ParamTemplateUseCase
+}
+
+func NewParamTemplateHandler(uc usecase.ParamTemplateUseCase) *ParamTemplateHandler {
+	return &ParamTemplateHandler{
+		uc: uc,
+	}
+}
+
+// Create creates the param template of the name
+func (hlr *ParamTemplateHandler) Create(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ParamTemplateHandler.Create")
+	defer span.End()
+
+	var req request.ParamTemplateCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		slog.InfoContext(ctx, "handler.Create. failed to bind json", logkit.Err(err))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+		return
+	}
+
+	obj := &domain.ParamTemplateDto{Name: req.Name, Query: req.Query}
+	if err := hlr.uc.Create(ctx, obj); errors.Is(err, domain.ErrDuplicatedKey) {
+		c.JSON(http.StatusConflict, gin.H{"error": ErrConflict.Error()})
+		return
+	} else if err != nil {
+		abortWithTemplateError(c, span, err)
+		return
+	}
+
+	slog.InfoContext(ctx, "handler.Create. success create a param template", "name", obj.Name)
+	c.JSON(http.StatusCreated, paramTemplateView(obj))
+}
+
+// List lists all the param templates by the name
+func (hlr *ParamTemplateHandler) List(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ParamTemplateHandler.List")
+	defer span.End()
+
+	objs, err := hlr.uc.List(ctx)
+	if err != nil {
+		tracekit.RecordError(span, err)
+		abortWithError(c, err)
+		return
+	}
+
+	templates := make([]gin.H, len(objs))
+	for i, obj := range objs {
+		templates[i] = paramTemplateView(obj)
+	}
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// Get gets the param template of the name
+func (hlr *ParamTemplateHandler) Get(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ParamTemplateHandler.Get")
+	defer span.End()
+
+	var uri request.ParamTemplateUriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		slog.InfoContext(ctx, "handler.Get. failed to bind uri", logkit.Err(err))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+		return
+	}
+
+	obj, err := hlr.uc.Get(ctx, uri.Name)
+	if err != nil {
+		abortWithTemplateError(c, span, err)
+		return
+	}
+	c.JSON(http.StatusOK, paramTemplateView(obj))
+}
+
+// Update replaces the query of the param template of the name
+func (hlr *ParamTemplateHandler) Update(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ParamTemplateHandler.Update")
+	defer span.End()
+
+	var uri request.ParamTemplateUriRequest
+	var req request.ParamTemplateUpdateRequest
+	if err := errors.Join(c.ShouldBindUri(&uri), c.ShouldBindJSON(&req)); err != nil {
+		slog.InfoContext(ctx, "handler.Update. failed to bind request", logkit.Err(err))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+		return
+	}
+
+	obj := &domain.ParamTemplateDto{Name: uri.Name, Query: req.Query}
+	if err := hlr.uc.Update(ctx, obj); err != nil {
+		abortWithTemplateError(c, span, err)
+		return
+	}
+
+	slog.InfoContext(ctx, "handler.Update. success update the param template", "name", obj.Name)
+	c.JSON(http.StatusOK, gin.H{
+		"name":      obj.Name,
+		"query":     obj.Query,
+		"updatedAt": obj.UpdatedAt,
+	})
+}
+
+// Delete deletes the param template of the name
+func (hlr *ParamTemplateHandler) Delete(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "ParamTemplateHandler.Delete")
+	defer span.End()
+
+	var uri request.ParamTemplateUriRequest
+	if err := c.ShouldBindUri(&uri); err != nil {
+		slog.InfoContext(ctx, "handler.Delete. failed to bind uri", logkit.Err(err))
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": ErrUnprocessableEntity.Error()})
+		return
+	}
+
+	if err := hlr.uc.Delete(ctx, uri.Name); err != nil {
+		abortWithTemplateError(c, span, err)
+		return
+	}
+
+	slog.InfoContext(ctx, "handler.Delete. success delete the param template", "name", uri.Name)
+	c.Status(http.StatusNoContent)
+}
+
+func paramTemplateView(obj *domain.ParamTemplateDto) gin.H {
+	return gin.H{
+		"name":      obj.Name,
+		"query":     obj.Query,
+		"createdAt": obj.CreatedAt,
+		"updatedAt": obj.UpdatedAt,
+	}
+}
+
+// abortWithTemplateError responds the errors of the param template use case
+func abortWithTemplateError(c *gin.Context, span trace.Span, err error) {
+	switch {
+	case errors.Is(err, domain.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": ErrNotFound.Error()})
+	case errors.Is(err, domain.ErrInvalidTemplate):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  ErrUnprocessableEntity.Error(),
+			"reason": err.Error(),
+		})
+	default:
+		tracekit.RecordError(span, err)
+		abortWithError(c, err)
+	}
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/router/handler/request"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+	"github.com/gin-gonic/gin"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ParamTemplateHandlerTestSuite struct {
+	suite.Suite
+	ginEngine *gin.Engine
+
+	now time.Time
+
+	uc   *usecase.ParamTemplateUseCase
+	impl *ParamTemplateHandler
+}
+
+func TestParamTemplateHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ParamTemplateHandlerTestSuite))
+}
+
+func (s *ParamTemplateHandlerTestSuite) SetupSuite() {
+	s.now = time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC)
+
+	s.uc = usecase.NewParamTemplateUseCase(s.T())
+	s.impl = NewParamTemplateHandler(s.uc)
+
+	r := gin.Default()
+	r.POST("/api/v1/templates", s.impl.Create)
+	r.GET("/api/v1/templates", s.impl.List)
+	r.GET("/api/v1/templates/:name", s.impl.Get)
+	r.PUT("/api/v1/templates/:name", s.impl.Update)
+	r.DELETE("/api/v1/templates/:name", s.impl.Delete)
+	s.ginEngine = r
+}
+
+func (s *ParamTemplateHandlerTestSuite) TestCreate() {
+	for _, t := range []struct {
+		name    string
+		req     *request.ParamTemplateCreateRequest
+		setup   func()
+		expCode int
+		expResp string
+	}{
+		{
+			name: "create the param template successfully",
+			req:  &request.ParamTemplateCreateRequest{Name: "newsletter", Query: "utm_campaign={{.Date}}"},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, &domain.ParamTemplateDto{Name: "newsletter", Query: "utm_campaign={{.Date}}"}).
+					Once().
+					Run(func(args mock.Arguments) {
+						obj := args.Get(1).(*domain.ParamTemplateDto)
+						obj.CreatedAt, obj.UpdatedAt = s.now, s.now
+					}).
+					Return(nil)
+			},
+			expCode: 201,
+			expResp: "{\"createdAt\":\"2025-02-10T08:30:15Z\",\"name\":\"newsletter\",\"query\":\"utm_campaign={{.Date}}\",\"updatedAt\":\"2025-02-10T08:30:15Z\"}",
+		},
+		{
+			name:    "missing query",
+			req:     &request.ParamTemplateCreateRequest{Name: "newsletter"},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "unprocessable entity"),
+		},
+		{
+			name: "invalid placeholder, return 422 with the reason",
+			req:  &request.ParamTemplateCreateRequest{Name: "newsletter", Query: "utm_campaign={{.Date"},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, mock.Anything).Once().Return(fmt.Errorf("%w: whatever", domain.ErrInvalidTemplate))
+			},
+			expCode: 422,
+			expResp: fmt.Sprintf("{\"error\":\"%s\",\"reason\":\"%s\"}", "unprocessable entity", "invalid template: whatever"),
+		},
+		{
+			name: "name taken, return 409",
+			req:  &request.ParamTemplateCreateRequest{Name: "newsletter", Query: "utm_source=newsletter"},
+			setup: func() {
+				s.uc.On("Create", mock.Anything, mock.Anything).Once().Return(domain.ErrDuplicatedKey)
+			},
+			expCode: 409,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "conflict"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			w := httptest.NewRecorder()
+			data, _ := json.Marshal(t.req)
+			req, _ := http.NewRequest("POST", "/api/v1/templates", strings.NewReader(string(data)))
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.Equal(t.expResp, w.Body.String())
+		})
+	}
+}
+
+func (s *ParamTemplateHandlerTestSuite) TestManage() {
+	for _, t := range []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		setup   func()
+		expCode int
+		expResp string
+	}{
+		{
+			name:   "list the param templates",
+			method: "GET",
+			path:   "/api/v1/templates",
+			setup: func() {
+				s.uc.On("List", mock.Anything).Once().Return([]*domain.ParamTemplateDto{
+					{Name: "newsletter", Query: "utm_source=newsletter", CreatedAt: s.now, UpdatedAt: s.now},
+				}, nil)
+			},
+			expCode: 200,
+			expResp: "{\"templates\":[{\"createdAt\":\"2025-02-10T08:30:15Z\",\"name\":\"newsletter\",\"query\":\"utm_source=newsletter\",\"updatedAt\":\"2025-02-10T08:30:15Z\"}]}",
+		},
+		{
+			name:   "get the param template not found",
+			method: "GET",
+			path:   "/api/v1/templates/newsletter",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "newsletter").Once().Return(nil, domain.ErrRecordNotFound)
+			},
+			expCode: 404,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "not found"),
+		},
+		{
+			name:   "update the param template",
+			method: "PUT",
+			path:   "/api/v1/templates/newsletter",
+			body:   "{\"query\":\"utm_source=mail\"}",
+			setup: func() {
+				s.uc.On("Update", mock.Anything, &domain.ParamTemplateDto{Name: "newsletter", Query: "utm_source=mail"}).
+					Once().
+					Run(func(args mock.Arguments) {
+						args.Get(1).(*domain.ParamTemplateDto).UpdatedAt = s.now
+					}).
+					Return(nil)
+			},
+			expCode: 200,
+			expResp: "{\"name\":\"newsletter\",\"query\":\"utm_source=mail\",\"updatedAt\":\"2025-02-10T08:30:15Z\"}",
+		},
+		{
+			name:   "delete the param template",
+			method: "DELETE",
+			path:   "/api/v1/templates/newsletter",
+			setup: func() {
+				s.uc.On("Delete", mock.Anything, "newsletter").Once().Return(nil)
+			},
+			expCode: 204,
+		},
+		{
+			name:   "failed to delete the param template",
+			method: "DELETE",
+			path:   "/api/v1/templates/newsletter",
+			setup: func() {
+				s.uc.On("Delete", mock.Anything, "newsletter").Once().Return(errors.New("whatever"))
+			},
+			expCode: 500,
+			expResp: fmt.Sprintf("{\"error\":\"%s\"}", "internal server error"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(t.method, t.path, strings.NewReader(t.body))
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(t.expCode, w.Code)
+			s.Equal(t.expResp, w.Body.String())
+		})
+	}
+}
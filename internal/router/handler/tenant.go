@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"fmt"
+	"net/url"
+	"sync/atomic"
+
+	"github.com/Hao1995/short-url/pkg/policykit"
+)
+
+// Fallbacks are the urls the tenants redirect to instead of the error pages of the links not found or expired,
+// which can be replaced at runtime
+type Fallbacks struct {
+	urls atomic.Pointer[map[string]string]
+}
+
+// NewFallbacks generates the fallbacks by the host serving the short urls
+func NewFallbacks(urls map[string]string) (*Fallbacks, error) {
+	f := &Fallbacks{}
+	if err := f.SetURLs(urls); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// SetURLs replaces the current fallbacks, which are kept if the new ones are invalid
+func (f *Fallbacks) SetURLs(urls map[string]string) error {
+	normalized := make(map[string]string, len(urls))
+	for host, fallback := range urls {
+		tenant, err := policykit.NormalizeHost(host)
+		if err != nil {
+			return fmt.Errorf("tenant %q: %w", host, err)
+		}
+		if u, err := url.Parse(fallback); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("tenant %q: invalid fallback url %q", host, fallback)
+		}
+		normalized[tenant] = fallback
+	}
+	f.urls.Store(&normalized)
+	return nil
+}
+
+// Lookup returns the fallback url of the tenant host, which might come with the port
+func (f *Fallbacks) Lookup(tenant string) (string, bool) {
+	host, ok := tenantHost(tenant)
+	if !ok {
+		return "", false
+	}
+	fallback, ok := (*f.urls.Load())[host]
+	return fallback, ok
+}
+
+// tenantHost normalizes the host of the request, e.g. `Go.Example.com:8080` to `go.example.com`
+func tenantHost(hostport string) (string, bool) {
+	host, err := policykit.NormalizeHost((&url.URL{Host: hostport}).Hostname())
+	return host, err == nil
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FallbacksTestSuite struct {
+	suite.Suite
+}
+
+func TestFallbacksTestSuite(t *testing.T) {
+	suite.Run(t, new(FallbacksTestSuite))
+}
+
+func (s *FallbacksTestSuite) TestLookup() {
+	impl, err := NewFallbacks(map[string]string{"Go.Example.com": "https://example.com/home"})
+	s.Require().NoError(err)
+
+	for _, t := range []struct {
+		name   string
+		tenant string
+		exp    string
+		expOK  bool
+	}{
+		{
+			name:   "follow the tenant",
+			tenant: "go.example.com",
+			exp:    "https://example.com/home",
+			expOK:  true,
+		},
+		{
+			name:   "follow the tenant with the port",
+			tenant: "GO.example.com:8080",
+			exp:    "https://example.com/home",
+			expOK:  true,
+		},
+		{
+			name:   "tenant without the fallback url",
+			tenant: "sho.rt",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			fallback, ok := impl.Lookup(t.tenant)
+			s.Equal(t.expOK, ok)
+			s.Equal(t.exp, fallback)
+		})
+	}
+}
+
+func (s *FallbacksTestSuite) TestSetURLs() {
+	impl, err := NewFallbacks(map[string]string{"sho.rt": "https://example.com/home"})
+	s.Require().NoError(err)
+
+	s.Error(impl.SetURLs(map[string]string{"sho.rt": "javascript:alert(1)"}))
+	_, ok := impl.Lookup("sho.rt")
+	s.True(ok, "keep the urls if the new ones are invalid")
+
+	s.NoError(impl.SetURLs(nil))
+	_, ok = impl.Lookup("sho.rt")
+	s.False(ok)
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/router/middleware"
+	uc "github.com/Hao1995/short-url/internal/usecase"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+	"github.com/Hao1995/short-url/pkg/cachekit"
+	"github.com/Hao1995/short-url/pkg/policykit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/viney-shih/go-cache"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	traceID      = "4bf92f3577b34da6a3ce929d0e0e4736"
+	parentSpanID = "00f067aa0ba902b7"
+)
+
+// TracingTestSuite checks the span tree of a request going through the handler, the use case and the cache
+type TracingTestSuite struct {
+	suite.Suite
+	ginEngine *gin.Engine
+
+	now time.Time
+
+	recorder *tracetest.SpanRecorder
+	repo     *usecase.Repository
+}
+
+func TestTracingTestSuite(t *testing.T) {
+	suite.Run(t, new(TracingTestSuite))
+}
+
+func (s *TracingTestSuite) SetupSuite() {
+	s.now = time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	s.recorder = tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(s.recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+func (s *TracingTestSuite) SetupSubTest() {
+	s.repo = usecase.NewRepository(s.T())
+	c := cachekit.New(nil, cache.NewTinyLFU(10), []cachekit.Setting{
+		{Prefix: domain.CACHE_PREFIX_SHORT_URL, LocalTTL: time.Minute},
+	}, cachekit.Hooks{})
+	nc := cachekit.New(nil, cache.NewTinyLFU(10), []cachekit.Setting{
+		{Prefix: domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, LocalTTL: time.Minute},
+	}, cachekit.Hooks{})
+	clicks := usecase.NewClickRecorder(s.T())
+	clicks.On("Record", mock.Anything, mock.Anything).Maybe()
+	policy, err := policykit.New(policykit.Rules{})
+	s.Require().NoError(err)
+	templates, err := LoadTemplates("")
+	s.Require().NoError(err)
+	interstitial, err := NewInterstitial(InterstitialRules{Mode: domain.InterstitialNever})
+	s.Require().NoError(err)
+	fallbacks, err := NewFallbacks(nil)
+	s.Require().NoError(err)
+	impl := NewShortUrlHandler(uc.NewShortUrlUseCase(s.repo, c, nc, uc.CRC32IDGenerator, policy, nil, clicks, uc.Config{AppHost: "http://localhost", ScanMode: uc.ScanModeNone}),
+		nil, templates, interstitial, fallbacks, nil, nil, RedirectConfig{Code: http.StatusFound})
+
+	r := gin.New()
+	r.Use(middleware.Tracing())
+	r.GET("/:id", impl.Get)
+	s.ginEngine = r
+
+	// drop the spans of the previous sub test
+	s.recorder.Reset()
+}
+
+func (s *TracingTestSuite) TestSpanTree() {
+	for _, t := range []struct {
+		name        string
+		setup       func()
+		requests    int
+		expCode     int
+		expEdges    []string
+		expErrSpans []string
+	}{
+		{
+			name: "load the record on cache missed",
+			setup: func() {
+				s.repo.On("Get", mock.Anything, "testid1").Once().Return(&domain.GetRespDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+				}, nil)
+			},
+			requests: 1,
+			expCode:  http.StatusFound,
+			expEdges: []string{
+				" > GET /:id",
+				"GET /:id > ShortUrlHandler.Get",
+				"ShortUrlHandler.Get > ShortUrlUseCase.Get",
+				"ShortUrlUseCase.Get > cachekit.Get",
+				"cachekit.Get > cachekit.local",
+				"ShortUrlUseCase.Get > cachekit.GetByFunc",
+				"cachekit.GetByFunc > cachekit.local",
+				"cachekit.GetByFunc > cachekit.singleflight",
+				"cachekit.singleflight > ShortUrlUseCase.Get.loader",
+			},
+		},
+		{
+			name: "hit the local cache on the second request",
+			setup: func() {
+				s.repo.On("Get", mock.Anything, "testid1").Once().Return(&domain.GetRespDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+				}, nil)
+			},
+			requests: 2,
+			expCode:  http.StatusFound,
+			expEdges: []string{
+				" > GET /:id",
+				"GET /:id > ShortUrlHandler.Get",
+				"ShortUrlHandler.Get > ShortUrlUseCase.Get",
+				"ShortUrlUseCase.Get > cachekit.Get",
+				"cachekit.Get > cachekit.local",
+				"ShortUrlUseCase.Get > cachekit.GetByFunc",
+				"cachekit.GetByFunc > cachekit.local",
+			},
+		},
+		{
+			name: "set the negative cache in the loader on record not found",
+			setup: func() {
+				s.repo.On("Get", mock.Anything, "testid1").Once().Return(nil, domain.ErrRecordNotFound)
+			},
+			requests: 1,
+			expCode:  http.StatusNotFound,
+			expEdges: []string{
+				" > GET /:id",
+				"GET /:id > ShortUrlHandler.Get",
+				"ShortUrlHandler.Get > ShortUrlUseCase.Get",
+				"ShortUrlUseCase.Get > cachekit.Get",
+				"cachekit.Get > cachekit.local",
+				"ShortUrlUseCase.Get > cachekit.GetByFunc",
+				"cachekit.GetByFunc > cachekit.local",
+				"cachekit.GetByFunc > cachekit.singleflight",
+				"cachekit.singleflight > ShortUrlUseCase.Get.loader",
+				"ShortUrlUseCase.Get.loader > cachekit.Set",
+			},
+			// the cache doesn't know the error returned by the loader is expected
+			expErrSpans: []string{
+				"cachekit.GetByFunc",
+				"cachekit.singleflight",
+			},
+		},
+		{
+			name: "mark the spans failed on unknown error",
+			setup: func() {
+				s.repo.On("Get", mock.Anything, "testid1").Once().Return(nil, errors.New("unknown error"))
+			},
+			requests: 1,
+			expCode:  http.StatusInternalServerError,
+			expEdges: []string{
+				" > GET /:id",
+				"GET /:id > ShortUrlHandler.Get",
+				"ShortUrlHandler.Get > ShortUrlUseCase.Get",
+				"ShortUrlUseCase.Get > cachekit.Get",
+				"cachekit.Get > cachekit.local",
+				"ShortUrlUseCase.Get > cachekit.GetByFunc",
+				"cachekit.GetByFunc > cachekit.local",
+				"cachekit.GetByFunc > cachekit.singleflight",
+				"cachekit.singleflight > ShortUrlUseCase.Get.loader",
+			},
+			expErrSpans: []string{
+				"GET /:id",
+				"ShortUrlHandler.Get",
+				"ShortUrlUseCase.Get",
+				"cachekit.GetByFunc",
+				"cachekit.singleflight",
+				"ShortUrlUseCase.Get.loader",
+			},
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			var w *httptest.ResponseRecorder
+			for i := 0; i < t.requests; i++ {
+				s.recorder.Reset()
+				w = httptest.NewRecorder()
+				req, _ := http.NewRequestWithContext(context.Background(), "GET", "/testid1", nil)
+				req.Header.Set("traceparent", fmt.Sprintf("00-%s-%s-01", traceID, parentSpanID))
+				s.ginEngine.ServeHTTP(w, req)
+			}
+			s.Equal(t.expCode, w.Code)
+
+			spans := s.recorder.Ended()
+			names := map[string]string{}
+			for _, span := range spans {
+				names[span.SpanContext().SpanID().String()] = span.Name()
+			}
+
+			edges := []string{}
+			errSpans := []string{}
+			for _, span := range spans {
+				s.Equal(traceID, span.SpanContext().TraceID().String())
+				if span.Parent().IsRemote() {
+					s.Equal(parentSpanID, span.Parent().SpanID().String())
+				}
+				edges = append(edges, fmt.Sprintf("%s > %s", names[span.Parent().SpanID().String()], span.Name()))
+				if span.Status().Code == codes.Error {
+					errSpans = append(errSpans, span.Name())
+				}
+			}
+			s.ElementsMatch(t.expEdges, edges)
+			s.ElementsMatch(t.expErrSpans, errSpans)
+		})
+	}
+}
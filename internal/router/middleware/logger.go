@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/Hao1995/short-url/pkg/geokit"
+	"github.com/Hao1995/short-url/pkg/logkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logger logs the access of the requests. The successful ones are sampled, since redirecting is the hot path.
+// Only the path is logged, the query string might carry the personal data.
+// The client IP is resolved by the trusted proxies, X-Forwarded-For from the others is ignored since it can be forged.
+func Logger(proxies *geokit.Proxies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", route,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency", time.Since(start),
+			"client_ip", proxies.ClientIP(c.Request).String(),
+		}
+
+		ctx := c.Request.Context()
+		switch {
+		case status >= http.StatusInternalServerError:
+			slog.ErrorContext(ctx, "http request", attrs...)
+		case len(c.Errors) > 0:
+			slog.WarnContext(ctx, "http request", append(attrs, "errors", c.Errors.String())...)
+		default:
+			logkit.Sampled().InfoContext(ctx, "http request", attrs...)
+		}
+	}
+}
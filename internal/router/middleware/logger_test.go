@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Hao1995/short-url/pkg/geokit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoggerTestSuite struct {
+	suite.Suite
+	ginEngine *gin.Engine
+
+	buf           bytes.Buffer
+	defaultLogger *slog.Logger
+}
+
+func TestLoggerTestSuite(t *testing.T) {
+	suite.Run(t, new(LoggerTestSuite))
+}
+
+func (s *LoggerTestSuite) SetupSuite() {
+	s.defaultLogger = slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&s.buf, nil)))
+
+	proxies, err := geokit.NewProxies([]string{"10.0.0.0/8"})
+	s.Require().NoError(err)
+
+	r := gin.New()
+	r.Use(Logger(proxies))
+	r.GET("/:id", func(c *gin.Context) {
+		// the failed requests are always logged
+		c.Status(http.StatusInternalServerError)
+	})
+	s.ginEngine = r
+}
+
+func (s *LoggerTestSuite) TearDownSuite() {
+	slog.SetDefault(s.defaultLogger)
+}
+
+func (s *LoggerTestSuite) TestClientIP() {
+	for _, t := range []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor string
+		exp          string
+	}{
+		{
+			name:         "log the client forwarded by the trusted proxy",
+			remoteAddr:   "10.0.0.1:1234",
+			forwardedFor: "203.0.113.1",
+			exp:          "203.0.113.1",
+		},
+		{
+			name:         "ignore the forwarded client from the untrusted peer",
+			remoteAddr:   "198.51.100.1:1234",
+			forwardedFor: "203.0.113.1",
+			exp:          "198.51.100.1",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			s.buf.Reset()
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/testid1", nil)
+			req.RemoteAddr = t.remoteAddr
+			req.Header.Set(geokit.HeaderForwardedFor, t.forwardedFor)
+			s.ginEngine.ServeHTTP(w, req)
+
+			var entry struct {
+				ClientIP string `json:"client_ip"`
+			}
+			s.Require().NoError(json.Unmarshal(s.buf.Bytes(), &entry))
+			s.Equal(t.exp, entry.ClientIP)
+		})
+	}
+}
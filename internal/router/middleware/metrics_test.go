@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Hao1995/short-url/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+type MetricsTestSuite struct {
+	suite.Suite
+	ginEngine *gin.Engine
+}
+
+func TestMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsTestSuite))
+}
+
+func (s *MetricsTestSuite) SetupSuite() {
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/:id", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, "https://example.com/whatever1")
+	})
+	s.ginEngine = r
+}
+
+func (s *MetricsTestSuite) TestMetrics() {
+	for _, t := range []struct {
+		name      string
+		method    string
+		path      string
+		expRoute  string
+		expStatus string
+	}{
+		{
+			name:      "record the route instead of the path",
+			method:    "GET",
+			path:      "/testid1",
+			expRoute:  "/:id",
+			expStatus: "302",
+		},
+		{
+			name:      "record the unmatched route",
+			method:    "GET",
+			path:      "/testid1/whatever",
+			expRoute:  "unmatched",
+			expStatus: "404",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			counter := metrics.HTTPRequests.WithLabelValues(t.method, t.expRoute, t.expStatus)
+			before := testutil.ToFloat64(counter)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(t.method, t.path, nil)
+			s.ginEngine.ServeHTTP(w, req)
+
+			s.Equal(before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/Hao1995/short-url/pkg/logkit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderRequestID carries the request id from the upstream proxy, and back to the client
+const HeaderRequestID = "X-Request-ID"
+
+// validRequestID keeps the ids of the upstream from polluting the logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9_.\-]{1,64}$`)
+
+// RequestID carries the request id of the upstream, or a new one, through the context of the request
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(HeaderRequestID)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Header(HeaderRequestID, id)
+		c.Request = c.Request.WithContext(logkit.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
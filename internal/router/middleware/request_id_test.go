@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Hao1995/short-url/pkg/logkit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type RequestIDTestSuite struct {
+	suite.Suite
+	ginEngine *gin.Engine
+
+	ctxID string
+}
+
+func TestRequestIDTestSuite(t *testing.T) {
+	suite.Run(t, new(RequestIDTestSuite))
+}
+
+func (s *RequestIDTestSuite) SetupSuite() {
+	r := gin.New()
+	r.Use(RequestID())
+	r.GET("/:id", func(c *gin.Context) {
+		s.ctxID = logkit.RequestID(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	s.ginEngine = r
+}
+
+func (s *RequestIDTestSuite) TestRequestID() {
+	for _, t := range []struct {
+		name     string
+		header   string
+		expID    string
+		expNewID bool
+	}{
+		{
+			name:   "carry the request id of the upstream",
+			header: "reqid1",
+			expID:  "reqid1",
+		},
+		{
+			name:     "generate the request id if it's missing",
+			header:   "",
+			expNewID: true,
+		},
+		{
+			name:     "generate the request id if the one of the upstream is invalid",
+			header:   "reqid1\n" + strings.Repeat("x", 64),
+			expNewID: true,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", "/testid1", nil)
+			if t.header != "" {
+				req.Header.Set(HeaderRequestID, t.header)
+			}
+			s.ginEngine.ServeHTTP(w, req)
+
+			id := w.Header().Get(HeaderRequestID)
+			if t.expNewID {
+				s.Len(id, 32)
+			} else {
+				s.Equal(t.expID, id)
+			}
+			s.Equal(id, s.ctxID)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+)
+
+type ShortUrlAdminUseCase struct {
+	repo Repository
+	c    Cache
+	nc   Cache
+}
+
+// NewShortUrlAdminUseCase generates the use case implementation of the admin use case interface.
+// `c` and `nc` are the positive and the negative cache shared with the ShortUrl use case.
+func NewShortUrlAdminUseCase(repo Repository, c Cache, nc Cache) AdminUseCase {
+	return &ShortUrlAdminUseCase{
+		repo: repo,
+		c:    c,
+		nc:   nc,
+	}
+}
+
+// Inspect gets the short url by id
+func (uc *ShortUrlAdminUseCase) Inspect(ctx context.Context, id string) (*domain.ShortUrlDto, error) {
+	return uc.repo.Find(ctx, id)
+}
+
+// Search lists the short urls of the target url from the newest one
+func (uc *ShortUrlAdminUseCase) Search(ctx context.Context, listReqDto *domain.ListReqDto) ([]*domain.ShortUrlDto, error) {
+	return uc.repo.List(ctx, listReqDto)
+}
+
+// SetStatus disables or enables the short url, and purges its cache so that it takes effect
+func (uc *ShortUrlAdminUseCase) SetStatus(ctx context.Context, id string, status domain.LinkStatus) error {
+	if !status.IsValid() {
+		return fmt.Errorf("%w: %q", domain.ErrInvalidLinkStatus, status)
+	}
+	if err := uc.repo.Update(ctx, id, &domain.UpdateReqDto{Status: &status}); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "ShortUrlAdminUseCase.SetStatus. Set the status of the short url", "id", id, "status", status.String())
+	return uc.Purge(ctx, id)
+}
+
+// Extend sets the expiry of the short url, and purges its cache so that it takes effect
+func (uc *ShortUrlAdminUseCase) Extend(ctx context.Context, id string, expireAt time.Time) error {
+	if err := uc.repo.Update(ctx, id, &domain.UpdateReqDto{ExpireAt: &expireAt}); err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "ShortUrlAdminUseCase.Extend. Set the expiry of the short url", "id", id, "expire_at", expireAt)
+	return uc.Purge(ctx, id)
+}
+
+// Purge removes the short url from both tiers of the positive and the negative cache,
+// and the other instances evict it from their local tier by the broadcast of the cache.
+func (uc *ShortUrlAdminUseCase) Purge(ctx context.Context, id string) error {
+	return errors.Join(
+		uc.c.Del(ctx, domain.CACHE_PREFIX_SHORT_URL, id),
+		uc.nc.Del(ctx, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, id),
+	)
+}
+
+// Clicks lists the recent clicks of the short url from the newest one
+func (uc *ShortUrlAdminUseCase) Clicks(ctx context.Context, id string, limit int) ([]*domain.ClickDto, error) {
+	return uc.repo.ListClicks(ctx, id, limit)
+}
+
+// Stats counts the clicks of the short url by the variant, in the order of the variants after the ones of the url and the rules.
+// The variants never clicked are listed as well, so that the traffic split can be compared with the weights.
+func (uc *ShortUrlAdminUseCase) Stats(ctx context.Context, id string) ([]*domain.VariantStatsDto, error) {
+	obj, err := uc.repo.Find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := uc.repo.CountClicksByVariant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*domain.VariantStatsDto, 0, len(obj.Variants)+1)
+	stats = append(stats, &domain.VariantStatsDto{Url: obj.Url, Clicks: counts[0]})
+	for i, variant := range obj.Variants {
+		stats = append(stats, &domain.VariantStatsDto{
+			Variant: i + 1,
+			Url:     variant.Url,
+			Weight:  variant.Weight,
+			Clicks:  counts[i+1],
+		})
+	}
+	return stats, nil
+}
@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ShortUrlAdminUseCaseTestSuite struct {
+	suite.Suite
+	ctx context.Context
+	now time.Time
+
+	repo *usecase.Repository
+	c    *usecase.Cache
+	nc   *usecase.Cache
+	impl AdminUseCase
+}
+
+func TestShortUrlAdminUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ShortUrlAdminUseCaseTestSuite))
+}
+
+func (s *ShortUrlAdminUseCaseTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	s.now = time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *ShortUrlAdminUseCaseTestSuite) SetupSubTest() {
+	s.repo = usecase.NewRepository(s.T())
+	s.c = usecase.NewCache(s.T())
+	s.nc = usecase.NewCache(s.T())
+	s.impl = NewShortUrlAdminUseCase(s.repo, s.c, s.nc)
+}
+
+func (s *ShortUrlAdminUseCaseTestSuite) expectPurge() {
+	s.c.On("Del", s.ctx, domain.CACHE_PREFIX_SHORT_URL, "testid1").Once().Return(nil)
+	s.nc.On("Del", s.ctx, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, "testid1").Once().Return(nil)
+}
+
+func (s *ShortUrlAdminUseCaseTestSuite) TestSetStatus() {
+	for _, t := range []struct {
+		name   string
+		status domain.LinkStatus
+		setup  func()
+		expErr error
+	}{
+		{
+			name:   "disable the short url and purge its cache",
+			status: domain.LinkStatusDisabled,
+			setup: func() {
+				status := domain.LinkStatusDisabled
+				s.repo.On("Update", s.ctx, "testid1", &domain.UpdateReqDto{Status: &status}).Once().Return(nil)
+				s.expectPurge()
+			},
+		},
+		{
+			name:   "failed to set the unknown status",
+			status: domain.LinkStatus("Unknown"),
+			expErr: domain.ErrInvalidLinkStatus,
+		},
+		{
+			name:   "failed to set the status of the short url not found",
+			status: domain.LinkStatusActive,
+			setup: func() {
+				status := domain.LinkStatusActive
+				s.repo.On("Update", s.ctx, "testid1", &domain.UpdateReqDto{Status: &status}).Once().Return(domain.ErrRecordNotFound)
+			},
+			expErr: domain.ErrRecordNotFound,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			s.ErrorIs(s.impl.SetStatus(s.ctx, "testid1", t.status), t.expErr)
+		})
+	}
+}
+
+func (s *ShortUrlAdminUseCaseTestSuite) TestExtend() {
+	for _, t := range []struct {
+		name   string
+		setup  func()
+		expErr error
+	}{
+		{
+			name: "extend the short url and purge its cache",
+			setup: func() {
+				expireAt := s.now.Add(time.Hour)
+				s.repo.On("Update", s.ctx, "testid1", &domain.UpdateReqDto{ExpireAt: &expireAt}).Once().Return(nil)
+				s.expectPurge()
+			},
+		},
+		{
+			name: "failed to extend the short url due to unknown error",
+			setup: func() {
+				s.repo.On("Update", s.ctx, "testid1", mock.Anything).Once().Return(errors.New("unknown error"))
+			},
+			expErr: errors.New("unknown error"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			s.Equal(t.expErr, s.impl.Extend(s.ctx, "testid1", s.now.Add(time.Hour)))
+		})
+	}
+}
+
+func (s *ShortUrlAdminUseCaseTestSuite) TestPurge() {
+	for _, t := range []struct {
+		name   string
+		setup  func()
+		expErr string
+	}{
+		{
+			name:  "purge both the positive and the negative cache",
+			setup: s.expectPurge,
+		},
+		{
+			name: "purge the negative cache even if failed to purge the positive cache",
+			setup: func() {
+				s.c.On("Del", s.ctx, domain.CACHE_PREFIX_SHORT_URL, "testid1").Once().Return(errors.New("unknown error"))
+				s.nc.On("Del", s.ctx, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, "testid1").Once().Return(nil)
+			},
+			expErr: "unknown error",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			err := s.impl.Purge(s.ctx, "testid1")
+			if t.expErr != "" {
+				s.EqualError(err, t.expErr)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
+
+func (s *ShortUrlAdminUseCaseTestSuite) TestStats() {
+	for _, t := range []struct {
+		name   string
+		setup  func()
+		exp    []*domain.VariantStatsDto
+		expErr error
+	}{
+		{
+			name: "break down the clicks by the variant including the ones never clicked",
+			setup: func() {
+				s.repo.On("Find", s.ctx, "testid1").Once().Return(&domain.ShortUrlDto{
+					TargetID: "testid1",
+					Url:      "https://example.com/",
+					Variants: []domain.Variant{
+						{Url: "https://a.example.com/", Weight: 70},
+						{Url: "https://b.example.com/", Weight: 30},
+					},
+				}, nil)
+				s.repo.On("CountClicksByVariant", s.ctx, "testid1").Once().Return(map[int]int64{0: 3, 1: 42}, nil)
+			},
+			exp: []*domain.VariantStatsDto{
+				{Url: "https://example.com/", Clicks: 3},
+				{Variant: 1, Url: "https://a.example.com/", Weight: 70, Clicks: 42},
+				{Variant: 2, Url: "https://b.example.com/", Weight: 30},
+			},
+		},
+		{
+			name: "count the clicks of the short url without the variants",
+			setup: func() {
+				s.repo.On("Find", s.ctx, "testid1").Once().Return(&domain.ShortUrlDto{TargetID: "testid1", Url: "https://example.com/"}, nil)
+				s.repo.On("CountClicksByVariant", s.ctx, "testid1").Once().Return(map[int]int64{0: 5}, nil)
+			},
+			exp: []*domain.VariantStatsDto{
+				{Url: "https://example.com/", Clicks: 5},
+			},
+		},
+		{
+			name: "failed to find the short url",
+			setup: func() {
+				s.repo.On("Find", s.ctx, "testid1").Once().Return(nil, domain.ErrRecordNotFound)
+			},
+			expErr: domain.ErrRecordNotFound,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			t.setup()
+			objs, err := s.impl.Stats(s.ctx, "testid1")
+			s.Equal(t.expErr, err)
+			s.Equal(t.exp, objs)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/metrics"
+	"github.com/Hao1995/short-url/pkg/logkit"
+)
+
+// ClickRecorderConfig is the config of BatchClickRecorder
+type ClickRecorderConfig struct {
+	// BufferSize is the number of clicks waiting to be stored, the clicks beyond it are dropped
+	BufferSize int
+	// BatchSize is the max number of clicks stored at once
+	BatchSize int
+	// FlushInterval is the max time a click waits in the buffer
+	FlushInterval time.Duration
+}
+
+// BatchClickRecorder stores the clicks in batches in the background, so that the redirection never waits for the DB
+type BatchClickRecorder struct {
+	repo   Repository
+	cfg    ClickRecorderConfig
+	clicks chan *domain.ClickDto
+}
+
+// NewBatchClickRecorder generates the click recorder, which stores nothing until Run is called
+func NewBatchClickRecorder(repo Repository, cfg ClickRecorderConfig) *BatchClickRecorder {
+	return &BatchClickRecorder{
+		repo:   repo,
+		cfg:    cfg,
+		clicks: make(chan *domain.ClickDto, cfg.BufferSize),
+	}
+}
+
+// Record buffers the click, or drops it if the buffer is full
+func (r *BatchClickRecorder) Record(ctx context.Context, clickDto *domain.ClickDto) {
+	select {
+	case r.clicks <- clickDto:
+	default:
+		metrics.Clicks.WithLabelValues("dropped").Inc()
+		logkit.Sampled().WarnContext(ctx, "BatchClickRecorder.Record. Drop the click as the buffer is full", "id", clickDto.TargetID)
+	}
+}
+
+// Run stores the buffered clicks every FlushInterval or BatchSize clicks, until the context is done.
+// The clicks left in the buffer are stored before it returns.
+func (r *BatchClickRecorder) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*domain.ClickDto, 0, r.cfg.BatchSize)
+	for {
+		select {
+		case clickDto := <-r.clicks:
+			batch = append(batch, clickDto)
+			if len(batch) >= r.cfg.BatchSize {
+				r.flush(ctx, batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			r.flush(ctx, batch)
+			batch = batch[:0]
+		case <-ctx.Done():
+			// the servers are stopped before the workers, so nothing is recorded after the buffer is drained
+			ctx = context.WithoutCancel(ctx)
+			for {
+				select {
+				case clickDto := <-r.clicks:
+					batch = append(batch, clickDto)
+					if len(batch) >= r.cfg.BatchSize {
+						r.flush(ctx, batch)
+						batch = batch[:0]
+					}
+				default:
+					r.flush(ctx, batch)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (r *BatchClickRecorder) flush(ctx context.Context, batch []*domain.ClickDto) {
+	if len(batch) == 0 {
+		return
+	}
+
+	if err := r.repo.CreateClicks(ctx, batch); err != nil {
+		metrics.Clicks.WithLabelValues("failed").Add(float64(len(batch)))
+		slog.ErrorContext(ctx, "BatchClickRecorder.flush. Failed to store the clicks", "count", len(batch), logkit.Err(err))
+		return
+	}
+	metrics.Clicks.WithLabelValues("stored").Add(float64(len(batch)))
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BatchClickRecorderTestSuite struct {
+	suite.Suite
+	now time.Time
+
+	repo *usecase.Repository
+}
+
+func TestBatchClickRecorderTestSuite(t *testing.T) {
+	suite.Run(t, new(BatchClickRecorderTestSuite))
+}
+
+func (s *BatchClickRecorderTestSuite) SetupSuite() {
+	s.now = time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+}
+
+func (s *BatchClickRecorderTestSuite) SetupSubTest() {
+	s.repo = usecase.NewRepository(s.T())
+}
+
+func (s *BatchClickRecorderTestSuite) click(id string) *domain.ClickDto {
+	return &domain.ClickDto{TargetID: id, ClickedAt: s.now}
+}
+
+func (s *BatchClickRecorderTestSuite) TestRun() {
+	for _, t := range []struct {
+		name   string
+		cfg    ClickRecorderConfig
+		clicks []string
+		setup  func()
+	}{
+		{
+			name:   "store the clicks in batches and the rest on stopped",
+			cfg:    ClickRecorderConfig{BufferSize: 10, BatchSize: 2, FlushInterval: time.Hour},
+			clicks: []string{"testid1", "testid2", "testid3"},
+			setup: func() {
+				s.repo.On("CreateClicks", mock.Anything, []*domain.ClickDto{s.click("testid1"), s.click("testid2")}).Once().Return(nil)
+				s.repo.On("CreateClicks", mock.Anything, []*domain.ClickDto{s.click("testid3")}).Once().Return(nil)
+			},
+		},
+		{
+			name:   "drop the clicks beyond the buffer",
+			cfg:    ClickRecorderConfig{BufferSize: 2, BatchSize: 10, FlushInterval: time.Hour},
+			clicks: []string{"testid1", "testid2", "testid3"},
+			setup: func() {
+				s.repo.On("CreateClicks", mock.Anything, []*domain.ClickDto{s.click("testid1"), s.click("testid2")}).Once().Return(nil)
+			},
+		},
+		{
+			name:   "keep recording after failed to store the clicks",
+			cfg:    ClickRecorderConfig{BufferSize: 10, BatchSize: 1, FlushInterval: time.Hour},
+			clicks: []string{"testid1", "testid2"},
+			setup: func() {
+				s.repo.On("CreateClicks", mock.Anything, []*domain.ClickDto{s.click("testid1")}).Once().Return(errors.New("unknown error"))
+				s.repo.On("CreateClicks", mock.Anything, []*domain.ClickDto{s.click("testid2")}).Once().Return(nil)
+			},
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			// record before running, so that the batches are decided by the config only
+			impl := NewBatchClickRecorder(s.repo, t.cfg)
+			for _, id := range t.clicks {
+				impl.Record(context.Background(), s.click(id))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				impl.Run(ctx)
+				close(done)
+			}()
+			s.Eventually(func() bool {
+				return len(impl.clicks) == 0
+			}, time.Second, time.Millisecond)
+			cancel()
+			<-done
+		})
+	}
+}
+
+func (s *BatchClickRecorderTestSuite) TestFlushInterval() {
+	s.Suite.Run("store the clicks every flush interval", func() {
+		stored := make(chan struct{})
+		s.repo.On("CreateClicks", mock.Anything, []*domain.ClickDto{s.click("testid1")}).Once().Return(nil).Run(func(args mock.Arguments) {
+			close(stored)
+		})
+
+		impl := NewBatchClickRecorder(s.repo, ClickRecorderConfig{BufferSize: 10, BatchSize: 10, FlushInterval: 10 * time.Millisecond})
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go impl.Run(ctx)
+
+		impl.Record(ctx, s.click("testid1"))
+		select {
+		case <-stored:
+		case <-time.After(time.Second):
+			s.Fail("the clicks aren't stored after the flush interval")
+		}
+	})
+}
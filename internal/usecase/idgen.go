@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"fmt"
+	"hash/crc32"
+
+	"github.com/Hao1995/short-url/pkg/shardkit"
+)
+
+// IDGenerator generates the target id of the url
+type IDGenerator func(url string) string
+
+// CRC32IDGenerator generates the id by the crc32 checksum of the url in 8 hex characters
+func CRC32IDGenerator(url string) string {
+	return fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(url)))
+}
+
+// NewShardedIDGenerator generates the crc32 id with the shard number embedded in the first two hex characters,
+// so that the shard of a link can be told by its id alone.
+func NewShardedIDGenerator(shards int) IDGenerator {
+	return func(url string) string {
+		sum := crc32.ChecksumIEEE([]byte(url))
+		return shardkit.Embed(fmt.Sprintf("%08x", sum), int(sum%uint32(shards)))
+	}
+}
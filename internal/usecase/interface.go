@@ -2,16 +2,96 @@ package usecase
 
 import (
 	"context"
+	"net/url"
+	"time"
 
 	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/pkg/cachekit"
 )
 
 type Repository interface {
 	Create(ctx context.Context, CreateReqDto *domain.CreateReqDto) (string, error)
 	Get(ctx context.Context, id string) (*domain.GetRespDto, error)
+	List(ctx context.Context, listReqDto *domain.ListReqDto) ([]*domain.ShortUrlDto, error)
+	// ListByStatus pages through the unexpired short urls of the statuses by the id, which is for the background workers
+	ListByStatus(ctx context.Context, listReqDto *domain.ListByStatusReqDto) ([]*domain.ShortUrlDto, error)
+	// Find gets the whole record by id from the primary, which is for the operators rather than the redirection
+	Find(ctx context.Context, id string) (*domain.ShortUrlDto, error)
+	Update(ctx context.Context, id string, updateReqDto *domain.UpdateReqDto) error
+	CreateClicks(ctx context.Context, clickDtos []*domain.ClickDto) error
+	// ListClicks lists the clicks of the id from the newest one
+	ListClicks(ctx context.Context, id string, limit int) ([]*domain.ClickDto, error)
+	// CountClicksByVariant counts the clicks of the id by the 1-based position of the variant, 0 for the others
+	CountClicksByVariant(ctx context.Context, id string) (map[int]int64, error)
+	CreateReport(ctx context.Context, reportDto *domain.ReportDto) error
+	// ListReports lists the reports of the status from the oldest one
+	ListReports(ctx context.Context, listReqDto *domain.ListReportsReqDto) ([]*domain.ReportDto, error)
+	// ResolveReports sets the status of the pending reports of the id, and returns the number of them
+	ResolveReports(ctx context.Context, id string, status domain.ReportStatus) (int, error)
+	BanCreator(ctx context.Context, creator string, reason string) error
+	IsCreatorBanned(ctx context.Context, creator string) (bool, error)
+	CreateParamTemplate(ctx context.Context, templateDto *domain.ParamTemplateDto) error
+	GetParamTemplate(ctx context.Context, name string) (*domain.ParamTemplateDto, error)
+	ListParamTemplates(ctx context.Context) ([]*domain.ParamTemplateDto, error)
+	UpdateParamTemplate(ctx context.Context, templateDto *domain.ParamTemplateDto) error
+	DeleteParamTemplate(ctx context.Context, name string) error
 }
 
 type UseCase interface {
 	Create(ctx context.Context, CreateReqDto *domain.CreateReqDto) (*domain.CreateRespDto, error)
 	Get(ctx context.Context, id string) (*domain.GetRespDto, error)
+	// Click records the click on the short url without waiting for it to be stored
+	Click(ctx context.Context, clickDto *domain.ClickDto)
+}
+
+// AdminUseCase is for the operators to investigate and manage the short urls
+type AdminUseCase interface {
+	Inspect(ctx context.Context, id string) (*domain.ShortUrlDto, error)
+	Search(ctx context.Context, listReqDto *domain.ListReqDto) ([]*domain.ShortUrlDto, error)
+	SetStatus(ctx context.Context, id string, status domain.LinkStatus) error
+	Extend(ctx context.Context, id string, expireAt time.Time) error
+	Purge(ctx context.Context, id string) error
+	Clicks(ctx context.Context, id string, limit int) ([]*domain.ClickDto, error)
+	// Stats breaks down the clicks of the short url by the variant
+	Stats(ctx context.Context, id string) ([]*domain.VariantStatsDto, error)
+}
+
+// ReportUseCase takes the abuse reports from the public, and resolves them by the operators
+type ReportUseCase interface {
+	Report(ctx context.Context, reportDto *domain.ReportDto) error
+	List(ctx context.Context, listReqDto *domain.ListReportsReqDto) ([]*domain.ReportDto, error)
+	Resolve(ctx context.Context, id string, action domain.ReportAction) (domain.ReportStatus, error)
+}
+
+// ParamTemplateUseCase manages the param templates, and renders the params of the short urls attached to them on redirecting
+type ParamTemplateUseCase interface {
+	Create(ctx context.Context, templateDto *domain.ParamTemplateDto) error
+	Get(ctx context.Context, name string) (*domain.ParamTemplateDto, error)
+	List(ctx context.Context) ([]*domain.ParamTemplateDto, error)
+	Update(ctx context.Context, templateDto *domain.ParamTemplateDto) error
+	Delete(ctx context.Context, name string) error
+	// Render renders the params of the template by the placeholders, which are empty if the template is deleted
+	Render(ctx context.Context, name string, data *domain.ParamTemplateData) (url.Values, error)
+}
+
+// URLPolicy decides whether the url is allowed as the destination, e.g. policykit.Policy
+type URLPolicy interface {
+	Check(rawURL string) error
+}
+
+// URLScanner looks up the url in a reputation source, e.g. scankit.LocalDB.
+// The threat is the type of the threat flagged, or empty if the url is clean.
+type URLScanner interface {
+	Scan(ctx context.Context, rawURL string) (threat string, err error)
+}
+
+type ClickRecorder interface {
+	Record(ctx context.Context, clickDto *domain.ClickDto)
+}
+
+type Cache interface {
+	GetByFunc(ctx context.Context, prefix, key string, container interface{}, loader cachekit.LoaderFunc) error
+	Get(ctx context.Context, prefix, key string, container interface{}) error
+	Set(ctx context.Context, prefix, key string, value interface{}) error
+	Del(ctx context.Context, prefix string, keys ...string) error
 }
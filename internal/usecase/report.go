@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/metrics"
+)
+
+type ShortUrlReportUseCase struct {
+	repo    Repository
+	uc      UseCase
+	adminUC AdminUseCase
+}
+
+// NewShortUrlReportUseCase generates the use case implementation of the report use case interface.
+// The reported short urls are resolved through the use case so that the cache absorbs the reports of the anonymous clients,
+// and the short urls are disabled through the admin use case so that the cache is purged.
+func NewShortUrlReportUseCase(repo Repository, uc UseCase, adminUC AdminUseCase) ReportUseCase {
+	return &ShortUrlReportUseCase{
+		repo:    repo,
+		uc:      uc,
+		adminUC: adminUC,
+	}
+}
+
+// Report queues the report of the existing short url for the review
+func (uc *ShortUrlReportUseCase) Report(ctx context.Context, reportDto *domain.ReportDto) error {
+	obj, err := uc.uc.Get(ctx, reportDto.TargetID)
+	if err != nil {
+		return err
+	}
+	if obj.Status == domain.GetRespStatusNotFound {
+		return domain.ErrRecordNotFound
+	}
+
+	reportDto.Status = domain.ReportStatusPending
+	reportDto.CreatedAt = now()
+	if err := uc.repo.CreateReport(ctx, reportDto); err != nil {
+		return err
+	}
+	metrics.Reports.WithLabelValues(domain.ReportStatusPending.String()).Inc()
+	slog.InfoContext(ctx, "ShortUrlReportUseCase.Report. Queue the report", "id", reportDto.TargetID, "report_id", reportDto.ID)
+	return nil
+}
+
+// List lists the reports of the status from the oldest one
+func (uc *ShortUrlReportUseCase) List(ctx context.Context, listReqDto *domain.ListReportsReqDto) ([]*domain.ReportDto, error) {
+	return uc.repo.ListReports(ctx, listReqDto)
+}
+
+// Resolve takes the action on the short url, and resolves all of its pending reports with the status of the action
+func (uc *ShortUrlReportUseCase) Resolve(ctx context.Context, id string, action domain.ReportAction) (domain.ReportStatus, error) {
+	var status domain.ReportStatus
+	switch action {
+	case domain.ReportActionDismiss:
+		status = domain.ReportStatusDismissed
+	case domain.ReportActionDisable:
+		if err := uc.adminUC.SetStatus(ctx, id, domain.LinkStatusDisabled); err != nil {
+			return "", err
+		}
+		status = domain.ReportStatusDisabled
+	case domain.ReportActionBan:
+		obj, err := uc.repo.Find(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		if obj.Creator == "" {
+			return "", domain.ErrNoCreator
+		}
+		if err := uc.repo.BanCreator(ctx, obj.Creator, fmt.Sprintf("reported short url %s", id)); err != nil {
+			return "", err
+		}
+		if err := uc.adminUC.SetStatus(ctx, id, domain.LinkStatusDisabled); err != nil {
+			return "", err
+		}
+		status = domain.ReportStatusBanned
+	default:
+		return "", fmt.Errorf("%w: %q", domain.ErrInvalidReportAction, action)
+	}
+
+	count, err := uc.repo.ResolveReports(ctx, id, status)
+	if err != nil {
+		return "", err
+	}
+	metrics.Reports.WithLabelValues(status.String()).Add(float64(count))
+	slog.InfoContext(ctx, "ShortUrlReportUseCase.Resolve. Resolve the reports", "id", id, "action", action.String(), "count", count)
+	return status, nil
+}
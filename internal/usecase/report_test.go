@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ShortUrlReportUseCaseTestSuite struct {
+	suite.Suite
+	ctx context.Context
+	now time.Time
+
+	repo    *usecase.Repository
+	uc      *usecase.UseCase
+	adminUC *usecase.AdminUseCase
+	impl    ReportUseCase
+}
+
+func TestShortUrlReportUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ShortUrlReportUseCaseTestSuite))
+}
+
+func (s *ShortUrlReportUseCaseTestSuite) SetupSuite() {
+	s.now = time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC)
+	now = func() time.Time {
+		return s.now
+	}
+}
+
+func (s *ShortUrlReportUseCaseTestSuite) TearDownSuite() {
+	now = func() time.Time {
+		return time.Now()
+	}
+}
+
+func (s *ShortUrlReportUseCaseTestSuite) SetupSubTest() {
+	s.ctx = context.Background()
+	s.repo = usecase.NewRepository(s.T())
+	s.uc = usecase.NewUseCase(s.T())
+	s.adminUC = usecase.NewAdminUseCase(s.T())
+	s.impl = NewShortUrlReportUseCase(s.repo, s.uc, s.adminUC)
+}
+
+func (s *ShortUrlReportUseCaseTestSuite) TestReport() {
+	for _, t := range []struct {
+		name   string
+		setup  func()
+		expErr error
+	}{
+		{
+			name: "queue the report of the existing short url",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "testid1").Once().Return(&domain.GetRespDto{Status: domain.GetRespStatusNormal, Url: "https://example.com/whatever1"}, nil)
+				s.repo.On("CreateReport", mock.Anything, &domain.ReportDto{
+					TargetID: "testid1", Reason: "phishing", Status: domain.ReportStatusPending, CreatedAt: s.now,
+				}).Once().Return(nil)
+			},
+		},
+		{
+			name: "queue the report of the disabled short url",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "testid1").Once().Return(&domain.GetRespDto{Status: domain.GetRespStatusDisabled}, nil)
+				s.repo.On("CreateReport", mock.Anything, &domain.ReportDto{
+					TargetID: "testid1", Reason: "phishing", Status: domain.ReportStatusPending, CreatedAt: s.now,
+				}).Once().Return(nil)
+			},
+		},
+		{
+			name: "short url not found",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "testid1").Once().Return(&domain.GetRespDto{Status: domain.GetRespStatusNotFound}, nil)
+			},
+			expErr: domain.ErrRecordNotFound,
+		},
+		{
+			name: "failed to get the short url",
+			setup: func() {
+				s.uc.On("Get", mock.Anything, "testid1").Once().Return(nil, errors.New("whatever"))
+			},
+			expErr: errors.New("whatever"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			err := s.impl.Report(s.ctx, &domain.ReportDto{TargetID: "testid1", Reason: "phishing"})
+			s.Equal(t.expErr, err)
+		})
+	}
+}
+
+func (s *ShortUrlReportUseCaseTestSuite) TestResolve() {
+	for _, t := range []struct {
+		name      string
+		action    domain.ReportAction
+		setup     func()
+		expStatus domain.ReportStatus
+		expErr    error
+	}{
+		{
+			name:   "dismiss the reports",
+			action: domain.ReportActionDismiss,
+			setup: func() {
+				s.repo.On("ResolveReports", mock.Anything, "testid1", domain.ReportStatusDismissed).Once().Return(2, nil)
+			},
+			expStatus: domain.ReportStatusDismissed,
+		},
+		{
+			name:   "disable the short url",
+			action: domain.ReportActionDisable,
+			setup: func() {
+				s.adminUC.On("SetStatus", mock.Anything, "testid1", domain.LinkStatusDisabled).Once().Return(nil)
+				s.repo.On("ResolveReports", mock.Anything, "testid1", domain.ReportStatusDisabled).Once().Return(1, nil)
+			},
+			expStatus: domain.ReportStatusDisabled,
+		},
+		{
+			name:   "keep the reports pending if it failed to disable the short url",
+			action: domain.ReportActionDisable,
+			setup: func() {
+				s.adminUC.On("SetStatus", mock.Anything, "testid1", domain.LinkStatusDisabled).Once().Return(domain.ErrRecordNotFound)
+			},
+			expErr: domain.ErrRecordNotFound,
+		},
+		{
+			name:   "ban the creator and disable the short url",
+			action: domain.ReportActionBan,
+			setup: func() {
+				s.repo.On("Find", mock.Anything, "testid1").Once().Return(&domain.ShortUrlDto{TargetID: "testid1", Creator: "whatever-creator"}, nil)
+				s.repo.On("BanCreator", mock.Anything, "whatever-creator", "reported short url testid1").Once().Return(nil)
+				s.adminUC.On("SetStatus", mock.Anything, "testid1", domain.LinkStatusDisabled).Once().Return(nil)
+				s.repo.On("ResolveReports", mock.Anything, "testid1", domain.ReportStatusBanned).Once().Return(1, nil)
+			},
+			expStatus: domain.ReportStatusBanned,
+		},
+		{
+			name:   "short url without the creator to ban",
+			action: domain.ReportActionBan,
+			setup: func() {
+				s.repo.On("Find", mock.Anything, "testid1").Once().Return(&domain.ShortUrlDto{TargetID: "testid1"}, nil)
+			},
+			expErr: domain.ErrNoCreator,
+		},
+		{
+			name:   "failed to resolve the reports",
+			action: domain.ReportActionDismiss,
+			setup: func() {
+				s.repo.On("ResolveReports", mock.Anything, "testid1", domain.ReportStatusDismissed).Once().Return(0, errors.New("unknown error"))
+			},
+			expErr: errors.New("unknown error"),
+		},
+		{
+			name:   "invalid action",
+			action: domain.ReportAction("whatever"),
+			expErr: fmt.Errorf("%w: %q", domain.ErrInvalidReportAction, "whatever"),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			status, err := s.impl.Resolve(s.ctx, "testid1", t.action)
+			s.Equal(t.expErr, err)
+			s.Equal(t.expStatus, status)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/metrics"
+	"github.com/Hao1995/short-url/pkg/logkit"
+)
+
+// RescannerConfig is the config of Rescanner
+type RescannerConfig struct {
+	// Timeout bounds the scan of each url
+	Timeout time.Duration
+	// Interval is how often all the active short urls are scanned again, it's disabled if it isn't positive
+	Interval time.Duration
+	// PendingInterval is how often the short urls waiting for the review are scanned, it's disabled if it isn't positive
+	PendingInterval time.Duration
+	// BatchSize is the number of short urls listed at once
+	BatchSize int
+}
+
+// Rescanner scans the destinations of the existing short urls in the background. The flagged ones are disabled,
+// and the clean ones waiting for the review are activated.
+type Rescanner struct {
+	repo    Repository
+	scanner URLScanner
+	adminUC AdminUseCase
+	cfg     RescannerConfig
+}
+
+// NewRescanner generates the rescanner, which changes the statuses through the admin use case so that the cache is purged
+func NewRescanner(repo Repository, scanner URLScanner, adminUC AdminUseCase, cfg RescannerConfig) *Rescanner {
+	return &Rescanner{
+		repo:    repo,
+		scanner: scanner,
+		adminUC: adminUC,
+		cfg:     cfg,
+	}
+}
+
+// Run rescans every Interval and PendingInterval until the context is done
+func (r *Rescanner) Run(ctx context.Context) {
+	var all, pending <-chan time.Time
+	if r.cfg.Interval > 0 {
+		ticker := time.NewTicker(r.cfg.Interval)
+		defer ticker.Stop()
+		all = ticker.C
+	}
+	if r.cfg.PendingInterval > 0 {
+		ticker := time.NewTicker(r.cfg.PendingInterval)
+		defer ticker.Stop()
+		pending = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-all:
+			r.Rescan(ctx, domain.LinkStatusActive, domain.LinkStatusPendingReview)
+		case <-pending:
+			r.Rescan(ctx, domain.LinkStatusPendingReview)
+		}
+	}
+}
+
+// Rescan scans the unexpired short urls of the statuses page by page
+func (r *Rescanner) Rescan(ctx context.Context, statuses ...domain.LinkStatus) {
+	start := time.Now()
+	counts := map[string]int{}
+	req := &domain.ListByStatusReqDto{Statuses: statuses, Limit: r.cfg.BatchSize}
+	for ctx.Err() == nil {
+		objs, err := r.repo.ListByStatus(ctx, req)
+		if err != nil {
+			slog.ErrorContext(ctx, "Rescanner.Rescan. Failed to list the short urls", "after_id", req.AfterID, logkit.Err(err))
+			break
+		}
+		for _, obj := range objs {
+			counts[r.scan(ctx, obj)]++
+		}
+		if len(objs) < r.cfg.BatchSize {
+			break
+		}
+		req.AfterID = objs[len(objs)-1].TargetID
+	}
+
+	slog.InfoContext(ctx, "Rescanner.Rescan. Rescan the short urls", "statuses", statuses, "elapsed", time.Since(start),
+		"clean", counts["clean"], "flagged", counts["flagged"], "error", counts["error"])
+}
+
+// scan scans the short url along with the destinations of its rules, geo rules and variants and changes its status,
+// the result is clean, flagged or error
+func (r *Rescanner) scan(ctx context.Context, obj *domain.ShortUrlDto) string {
+	scanCtx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+
+	urls := []string{obj.Url}
+	for _, rule := range obj.Rules {
+		urls = append(urls, rule.Url)
+	}
+	for _, rule := range obj.GeoRules {
+		urls = append(urls, rule.Url)
+	}
+	for _, variant := range obj.Variants {
+		urls = append(urls, variant.Url)
+	}
+	var threat string
+	var err error
+	for _, url := range urls {
+		if threat, err = r.scanner.Scan(scanCtx, url); threat != "" || err != nil {
+			break
+		}
+	}
+	if err != nil {
+		metrics.Scans.WithLabelValues("error").Inc()
+		slog.WarnContext(ctx, "Rescanner.scan. Failed to scan the url", "id", obj.TargetID, logkit.Err(err))
+		return "error"
+	}
+
+	result, status := "clean", domain.LinkStatusActive
+	if threat != "" {
+		result, status = "flagged", domain.LinkStatusDisabled
+	}
+	metrics.Scans.WithLabelValues(result).Inc()
+	if status == obj.Status {
+		return result
+	}
+
+	if err := r.adminUC.SetStatus(ctx, obj.TargetID, status); err != nil {
+		slog.ErrorContext(ctx, "Rescanner.scan. Failed to set the status", "id", obj.TargetID, "status", status.String(), logkit.Err(err))
+		return "error"
+	}
+	if threat != "" {
+		slog.WarnContext(ctx, "Rescanner.scan. Disable the flagged short url", "id", obj.TargetID, "threat", threat)
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RescannerTestSuite struct {
+	suite.Suite
+	ctx context.Context
+
+	repo    *usecase.Repository
+	scanner *usecase.URLScanner
+	adminUC *usecase.AdminUseCase
+	impl    *Rescanner
+}
+
+func TestRescannerTestSuite(t *testing.T) {
+	suite.Run(t, new(RescannerTestSuite))
+}
+
+func (s *RescannerTestSuite) SetupSubTest() {
+	s.ctx = context.Background()
+	s.repo = usecase.NewRepository(s.T())
+	s.scanner = usecase.NewURLScanner(s.T())
+	s.adminUC = usecase.NewAdminUseCase(s.T())
+	s.impl = NewRescanner(s.repo, s.scanner, s.adminUC, RescannerConfig{Timeout: time.Second, BatchSize: 2})
+}
+
+func (s *RescannerTestSuite) TestRescan() {
+	for _, t := range []struct {
+		name     string
+		statuses []domain.LinkStatus
+		setup    func()
+	}{
+		{
+			name:     "disable the flagged short urls page by page",
+			statuses: []domain.LinkStatus{domain.LinkStatusActive, domain.LinkStatusPendingReview},
+			setup: func() {
+				statuses := []domain.LinkStatus{domain.LinkStatusActive, domain.LinkStatusPendingReview}
+				s.repo.On("ListByStatus", mock.Anything, &domain.ListByStatusReqDto{Statuses: statuses, Limit: 2}).Once().Return([]*domain.ShortUrlDto{
+					{TargetID: "testid1", Url: "https://example.com/whatever1", Status: domain.LinkStatusActive},
+					{TargetID: "testid2", Url: "https://evil.example.com/", Status: domain.LinkStatusActive},
+				}, nil)
+				s.repo.On("ListByStatus", mock.Anything, &domain.ListByStatusReqDto{Statuses: statuses, AfterID: "testid2", Limit: 2}).Once().Return([]*domain.ShortUrlDto{
+					{TargetID: "testid3", Url: "https://evil.example.com/pending", Status: domain.LinkStatusPendingReview},
+				}, nil)
+				s.scanner.On("Scan", mock.Anything, "https://example.com/whatever1").Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://evil.example.com/").Once().Return("MALWARE", nil)
+				s.scanner.On("Scan", mock.Anything, "https://evil.example.com/pending").Once().Return("MALWARE", nil)
+				s.adminUC.On("SetStatus", mock.Anything, "testid2", domain.LinkStatusDisabled).Once().Return(nil)
+				s.adminUC.On("SetStatus", mock.Anything, "testid3", domain.LinkStatusDisabled).Once().Return(nil)
+			},
+		},
+		{
+			name:     "activate the clean short urls waiting for the review",
+			statuses: []domain.LinkStatus{domain.LinkStatusPendingReview},
+			setup: func() {
+				s.repo.On("ListByStatus", mock.Anything, mock.Anything).Once().Return([]*domain.ShortUrlDto{
+					{TargetID: "testid1", Url: "https://example.com/whatever1", Status: domain.LinkStatusPendingReview},
+				}, nil)
+				s.scanner.On("Scan", mock.Anything, "https://example.com/whatever1").Once().Return("", nil)
+				s.adminUC.On("SetStatus", mock.Anything, "testid1", domain.LinkStatusActive).Once().Return(nil)
+			},
+		},
+		{
+			name:     "disable the short urls of which a rule is flagged",
+			statuses: []domain.LinkStatus{domain.LinkStatusActive},
+			setup: func() {
+				s.repo.On("ListByStatus", mock.Anything, mock.Anything).Once().Return([]*domain.ShortUrlDto{
+					{TargetID: "testid1", Url: "https://example.com/whatever1", Status: domain.LinkStatusActive, Rules: []domain.RedirectRule{
+						{Platform: domain.PlatformIos, Url: "https://evil.example.com/ios"},
+						{Platform: domain.PlatformAndroid, Url: "https://example.com/android"},
+					}},
+				}, nil)
+				s.scanner.On("Scan", mock.Anything, "https://example.com/whatever1").Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://evil.example.com/ios").Once().Return("MALWARE", nil)
+				s.adminUC.On("SetStatus", mock.Anything, "testid1", domain.LinkStatusDisabled).Once().Return(nil)
+			},
+		},
+		{
+			name:     "disable the short urls of which a geo rule is flagged",
+			statuses: []domain.LinkStatus{domain.LinkStatusActive},
+			setup: func() {
+				s.repo.On("ListByStatus", mock.Anything, mock.Anything).Once().Return([]*domain.ShortUrlDto{
+					{TargetID: "testid1", Url: "https://example.com/whatever1", Status: domain.LinkStatusActive, GeoRules: []domain.GeoRule{
+						{Country: "TW", Url: "https://evil.example.com/tw"},
+					}},
+				}, nil)
+				s.scanner.On("Scan", mock.Anything, "https://example.com/whatever1").Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://evil.example.com/tw").Once().Return("MALWARE", nil)
+				s.adminUC.On("SetStatus", mock.Anything, "testid1", domain.LinkStatusDisabled).Once().Return(nil)
+			},
+		},
+		{
+			name:     "disable the short urls of which a variant is flagged",
+			statuses: []domain.LinkStatus{domain.LinkStatusActive},
+			setup: func() {
+				s.repo.On("ListByStatus", mock.Anything, mock.Anything).Once().Return([]*domain.ShortUrlDto{
+					{TargetID: "testid1", Url: "https://example.com/whatever1", Status: domain.LinkStatusActive, Variants: []domain.Variant{
+						{Url: "https://example.com/a", Weight: 50},
+						{Url: "https://evil.example.com/b", Weight: 50},
+					}},
+				}, nil)
+				s.scanner.On("Scan", mock.Anything, "https://example.com/whatever1").Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://example.com/a").Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://evil.example.com/b").Once().Return("MALWARE", nil)
+				s.adminUC.On("SetStatus", mock.Anything, "testid1", domain.LinkStatusDisabled).Once().Return(nil)
+			},
+		},
+		{
+			name:     "keep the short urls failed to be scanned",
+			statuses: []domain.LinkStatus{domain.LinkStatusPendingReview},
+			setup: func() {
+				s.repo.On("ListByStatus", mock.Anything, mock.Anything).Once().Return([]*domain.ShortUrlDto{
+					{TargetID: "testid1", Url: "https://example.com/whatever1", Status: domain.LinkStatusPendingReview},
+				}, nil)
+				s.scanner.On("Scan", mock.Anything, "https://example.com/whatever1").Once().Return("", errors.New("unknown error"))
+			},
+		},
+		{
+			name:     "stop rescanning if it failed to list the short urls",
+			statuses: []domain.LinkStatus{domain.LinkStatusActive},
+			setup: func() {
+				s.repo.On("ListByStatus", mock.Anything, mock.Anything).Once().Return(nil, errors.New("unknown error"))
+			},
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			s.impl.Rescan(s.ctx, t.statuses...)
+		})
+	}
+}
@@ -2,15 +2,23 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"hash/crc32"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/metrics"
+	"github.com/Hao1995/short-url/pkg/cachekit"
+	"github.com/Hao1995/short-url/pkg/logkit"
 	"github.com/Hao1995/short-url/pkg/migrationkit/randkit"
-	"github.com/caarlos0/env/v11"
-	"github.com/viney-shih/go-cache"
+	"github.com/Hao1995/short-url/pkg/policykit"
+	"github.com/Hao1995/short-url/pkg/tracekit"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -22,77 +30,313 @@ var (
 		return randkit.String(4)
 	}
 
-	cfg config
+	// errNotFound stops the positive cache from keeping the record not found,
+	// which is kept by the negative cache instead.
+	errNotFound = errors.New("short url not found")
+
+	tracer = otel.Tracer("github.com/Hao1995/short-url/internal/usecase")
 )
 
-func init() {
-	if err := env.Parse(&cfg); err != nil {
-		log.Fatal("failed to parse env: ", err)
-	}
-}
+const attrID = attribute.Key("short_url.id")
 
-type config struct {
-	AppHost string `env:"APP_HOST" envDefault:"http://localhost"`
+// ScanMode decides how the destinations of the new short urls are scanned
+type ScanMode string
+
+const (
+	// ScanModeNone activates the short urls without scanning
+	ScanModeNone ScanMode = "none"
+	// ScanModeSync scans before creating, the urls flagged are rejected and the ones failed to scan wait for the review
+	ScanModeSync ScanMode = "sync"
+	// ScanModeAsync creates the short urls waiting for the review, which are activated by the Rescanner
+	ScanModeAsync ScanMode = "async"
+)
+
+// Config is the config of the ShortUrl use case
+type Config struct {
+	// AppHost is the scheme and the host of the short urls, e.g. https://sho.rt
+	AppHost string
+
+	ScanMode ScanMode
+	// ScanTimeout bounds the scan of ScanModeSync
+	ScanTimeout time.Duration
 }
+
 type ShortUrlUseCase struct {
-	repo Repository
-	c    cache.Cache
+	repo    Repository
+	c       Cache
+	nc      Cache
+	idGen   IDGenerator
+	policy  URLPolicy
+	scanner URLScanner
+	clicks  ClickRecorder
+	cfg     Config
 }
 
-// NewShortUrlUseCase generates the use case implementation of the ShortUrl use case interface
-func NewShortUrlUseCase(repo Repository, c cache.Cache) UseCase {
+// NewShortUrlUseCase generates the use case implementation of the ShortUrl use case interface.
+// `c` caches the existing short urls, and `nc` caches the ids not found, which needs a shorter TTL.
+// `scanner` is unused with ScanModeNone, which can be nil.
+func NewShortUrlUseCase(repo Repository, c Cache, nc Cache, idGen IDGenerator, policy URLPolicy, scanner URLScanner, clicks ClickRecorder, cfg Config) UseCase {
 	return &ShortUrlUseCase{
-		repo: repo,
-		c:    c,
+		repo:    repo,
+		c:       c,
+		nc:      nc,
+		idGen:   idGen,
+		policy:  policy,
+		scanner: scanner,
+		clicks:  clicks,
+		cfg:     cfg,
 	}
 }
 
 // Create creates short_url record and return short url id
-func (uc *ShortUrlUseCase) Create(ctx context.Context, createReqDto *domain.CreateReqDto) (*domain.CreateRespDto, error) {
+func (uc *ShortUrlUseCase) Create(ctx context.Context, createReqDto *domain.CreateReqDto) (_ *domain.CreateRespDto, err error) {
+	ctx, span := tracer.Start(ctx, "ShortUrlUseCase.Create")
+	defer func() {
+		// the url rejected is the fault of the client
+		var violation *policykit.Violation
+		if !errors.As(err, &violation) {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	if err := validateRules(createReqDto.Rules); err != nil {
+		return nil, err
+	}
+	if err := validateGeoRules(createReqDto.GeoRules); err != nil {
+		return nil, err
+	}
+	if err := validateVariants(createReqDto.Variants); err != nil {
+		return nil, err
+	}
+
+	// the destinations of the rules and the variants are checked like the url, the short url waits for the review if any of them does
+	var status domain.LinkStatus
+	urls := []string{createReqDto.Url}
+	for _, rule := range createReqDto.Rules {
+		urls = append(urls, rule.Url)
+	}
+	for _, rule := range createReqDto.GeoRules {
+		urls = append(urls, rule.Url)
+	}
+	for _, variant := range createReqDto.Variants {
+		urls = append(urls, variant.Url)
+	}
+	for _, url := range urls {
+		urlStatus, err := uc.check(ctx, url)
+		if err != nil {
+			var violation *policykit.Violation
+			if errors.As(err, &violation) {
+				metrics.URLRejections.WithLabelValues(string(violation.Reason)).Inc()
+				slog.InfoContext(ctx, "ShortUrlUseCase.Create. Reject the url", "reason", violation.Reason, "detail", violation.Detail)
+			}
+			return nil, err
+		}
+		if status != domain.LinkStatusPendingReview {
+			status = urlStatus
+		}
+	}
+	createReqDto.Status = status
+
+	if createReqDto.APIKey != "" {
+		sum := sha256.Sum256([]byte(createReqDto.APIKey))
+		createReqDto.Creator = hex.EncodeToString(sum[:])
+		banned, err := uc.repo.IsCreatorBanned(ctx, createReqDto.Creator)
+		if err != nil {
+			return nil, err
+		}
+		if banned {
+			slog.InfoContext(ctx, "ShortUrlUseCase.Create. Reject the banned creator", "creator", createReqDto.Creator)
+			return nil, domain.ErrCreatorBanned
+		}
+	}
+
+	if createReqDto.Template != "" {
+		if _, err := uc.repo.GetParamTemplate(ctx, createReqDto.Template); err == domain.ErrRecordNotFound {
+			return nil, domain.ErrUnknownTemplate
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
 	var id string
 	url := createReqDto.Url
 	for {
 		var err error
-		createReqDto.TargetID = fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(url)))
+		createReqDto.TargetID = uc.idGen(url)
 		id, err = uc.repo.Create(ctx, createReqDto)
 		if err == domain.ErrDuplicatedKey {
+			metrics.CreateCollisions.Inc()
 			url += randString() // 62^4=14M possibilities
-			log.Print("Append random suffix", url)
+			slog.InfoContext(ctx, "ShortUrlUseCase.Create. Append random suffix on id collision", "id", createReqDto.TargetID)
 		} else if err != nil {
 			return nil, err
 		} else {
 			break
 		}
 	}
+	span.SetAttributes(attrID.String(id))
+
+	// the id might be probed before, which shouldn't be regarded as not found any longer
+	if err := uc.nc.Del(ctx, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, id); err != nil {
+		slog.WarnContext(ctx, "ShortUrlUseCase.Create. Failed to evict the negative cache", "id", id, logkit.Err(err))
+	}
+
 	return &domain.CreateRespDto{
 		TargetID: id,
-		ShortUrl: fmt.Sprintf("%s/%s", cfg.AppHost, id),
+		ShortUrl: fmt.Sprintf("%s/%s", uc.cfg.AppHost, id),
+		Status:   status,
 	}, nil
 }
 
+// validateRules rejects the unknown platforms and the rules never matched because of the same platform before them
+func validateRules(rules []domain.RedirectRule) error {
+	seen := map[domain.Platform]bool{}
+	for i, rule := range rules {
+		if !rule.Platform.IsValid() {
+			return fmt.Errorf("%w: unknown platform %q of rule %d", domain.ErrInvalidRule, rule.Platform, i+1)
+		}
+		if seen[rule.Platform] {
+			return fmt.Errorf("%w: duplicated platform %q of rule %d", domain.ErrInvalidRule, rule.Platform, i+1)
+		}
+		seen[rule.Platform] = true
+	}
+	return nil
+}
+
+// validateGeoRules rejects the rules of neither or both of the country and the continent,
+// and the rules never matched because of the same country or continent before them
+func validateGeoRules(rules []domain.GeoRule) error {
+	seen := map[domain.GeoRule]bool{}
+	for i, rule := range rules {
+		switch {
+		case (rule.Country == "") == (rule.Continent == ""):
+			return fmt.Errorf("%w: want either the country or the continent of geo rule %d", domain.ErrInvalidRule, i+1)
+		case rule.Country != "" && !isCountryCode(rule.Country):
+			return fmt.Errorf("%w: unknown country %q of geo rule %d", domain.ErrInvalidRule, rule.Country, i+1)
+		case rule.Continent != "" && !rule.Continent.IsValid():
+			return fmt.Errorf("%w: unknown continent %q of geo rule %d", domain.ErrInvalidRule, rule.Continent, i+1)
+		}
+		key := domain.GeoRule{Country: rule.Country, Continent: rule.Continent}
+		if seen[key] {
+			return fmt.Errorf("%w: duplicated location of geo rule %d", domain.ErrInvalidRule, i+1)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// isCountryCode checks the shape of the ISO 3166-1 alpha-2 code, the unassigned ones are just never matched
+func isCountryCode(code string) bool {
+	return len(code) == 2 && 'A' <= code[0] && code[0] <= 'Z' && 'A' <= code[1] && code[1] <= 'Z'
+}
+
+// validateVariants rejects a single variant, which splits nothing, and the variants never picked because of no weight
+func validateVariants(variants []domain.Variant) error {
+	if len(variants) == 1 {
+		return fmt.Errorf("%w: want at least 2 variants", domain.ErrInvalidVariant)
+	}
+	for i, variant := range variants {
+		if variant.Weight <= 0 {
+			return fmt.Errorf("%w: non-positive weight %d of variant %d", domain.ErrInvalidVariant, variant.Weight, i+1)
+		}
+	}
+	return nil
+}
+
+// check checks the url against the policy, then scans it by ScanMode and decides the status of the new short url
+func (uc *ShortUrlUseCase) check(ctx context.Context, url string) (domain.LinkStatus, error) {
+	if err := uc.policy.Check(url); err != nil {
+		return "", err
+	}
+
+	switch uc.cfg.ScanMode {
+	case ScanModeSync:
+		scanCtx, cancel := context.WithTimeout(ctx, uc.cfg.ScanTimeout)
+		defer cancel()
+		threat, err := uc.scanner.Scan(scanCtx, url)
+		if err != nil {
+			// the Rescanner decides it later rather than failing the request
+			metrics.Scans.WithLabelValues("error").Inc()
+			slog.WarnContext(ctx, "ShortUrlUseCase.check. Failed to scan the url, wait for the review", logkit.Err(err))
+			return domain.LinkStatusPendingReview, nil
+		}
+		if threat != "" {
+			metrics.Scans.WithLabelValues("flagged").Inc()
+			return "", &policykit.Violation{Reason: policykit.ReasonThreatDetected, Detail: fmt.Sprintf("url is flagged as %s", threat)}
+		}
+		metrics.Scans.WithLabelValues("clean").Inc()
+	case ScanModeAsync:
+		return domain.LinkStatusPendingReview, nil
+	}
+	return domain.LinkStatusActive, nil
+}
+
 // Get gets short url record by id
-func (uc *ShortUrlUseCase) Get(ctx context.Context, id string) (*domain.GetRespDto, error) {
+func (uc *ShortUrlUseCase) Get(ctx context.Context, id string) (_ *domain.GetRespDto, err error) {
+	ctx, span := tracer.Start(ctx, "ShortUrlUseCase.Get", trace.WithAttributes(attrID.String(id)))
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
 	cacheObj := &domain.GetRespDto{}
-	if err := uc.c.GetByFunc(ctx, domain.CACHE_PREFIX_SHORT_URL, id, cacheObj, func() (interface{}, error) {
+
+	// look up the negative cache first, so that probing non-existent ids doesn't reach the DB
+	if err := uc.nc.Get(ctx, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, id, cacheObj); err == nil {
+		return cacheObj, nil
+	} else if err != cachekit.ErrCacheMiss {
+		slog.ErrorContext(ctx, "ShortUrlUseCase.Get. Failed to get the short_url from negative cache", "id", id, logkit.Err(err))
+		return nil, err
+	}
+
+	if err := uc.c.GetByFunc(ctx, domain.CACHE_PREFIX_SHORT_URL, id, cacheObj, func(ctx context.Context) (_ interface{}, err error) {
+		ctx, span := tracer.Start(ctx, "ShortUrlUseCase.Get.loader")
+		defer func() {
+			// the record not found is kept by the negative cache, which isn't a failure
+			if err != errNotFound {
+				tracekit.RecordError(span, err)
+			}
+			span.End()
+		}()
+
 		obj, err := uc.repo.Get(ctx, id)
 		if err == domain.ErrRecordNotFound {
-			obj = &domain.GetRespDto{Status: domain.GetRespStatusNotFound}
+			// the lookups of the negative cache probe all the ids, and only the ones not found are its misses
+			metrics.OnCacheMiss(domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, id, 1)
+			if err := uc.nc.Set(ctx, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, id, &domain.GetRespDto{Status: domain.GetRespStatusNotFound}); err != nil {
+				slog.WarnContext(ctx, "ShortUrlUseCase.Get. Failed to set the negative cache", "id", id, logkit.Err(err))
+			}
+			return nil, errNotFound
 		} else if err != nil {
 			return nil, err
-		} else {
-			obj.Status = domain.GetRespStatusNormal
 		}
+		obj.Status = domain.GetRespStatusNormal
 		return obj, nil
-	}); err != nil {
-		log.Print("ShortUrlUseCase.Get. Failed to get the short_url from cache: ", err)
+	}); err == errNotFound {
+		return &domain.GetRespDto{Status: domain.GetRespStatusNotFound}, nil
+	} else if err != nil {
+		slog.ErrorContext(ctx, "ShortUrlUseCase.Get. Failed to get the short_url from cache", "id", id, logkit.Err(err))
 		return nil, err
 	}
 
 	if cacheObj.Status == domain.GetRespStatusNormal {
-		if cacheObj.ExpireAt.Before(now()) {
+		if cacheObj.LinkStatus == domain.LinkStatusDisabled {
+			cacheObj.Status = domain.GetRespStatusDisabled
+		} else if cacheObj.LinkStatus == domain.LinkStatusPendingReview {
+			cacheObj.Status = domain.GetRespStatusPendingReview
+		} else if cacheObj.ExpireAt.Before(now()) {
 			cacheObj.Status = domain.GetRespStatusExpired
 		}
 	}
 
 	return cacheObj, nil
 }
+
+// Click records the click on the short url
+func (uc *ShortUrlUseCase) Click(ctx context.Context, clickDto *domain.ClickDto) {
+	if clickDto.ClickedAt.IsZero() {
+		clickDto.ClickedAt = now()
+	}
+	uc.clicks.Record(ctx, clickDto)
+}
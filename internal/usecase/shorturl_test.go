@@ -11,24 +11,29 @@ import (
 	"time"
 
 	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/internal/metrics"
 	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+	"github.com/Hao1995/short-url/pkg/cachekit"
+	"github.com/Hao1995/short-url/pkg/policykit"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/ory/dockertest/v3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"github.com/viney-shih/go-cache"
 )
 
 type ShortUrlUseCaseTestSuite struct {
 	suite.Suite
-	ctx               context.Context
-	now               time.Time
-	dockertestClose   func() error
-	cacheFactoryClose func()
-	host              string
-	port              string
+	ctx             context.Context
+	now             time.Time
+	dockertestClose func() error
+	host            string
+	port            string
 
 	ring *redis.Ring
+	nc   *cachekit.Cache
 	repo *usecase.Repository
 	impl UseCase
 }
@@ -62,40 +67,36 @@ func (s *ShortUrlUseCaseTestSuite) SetupSubTest() {
 	// Reset after each sub-test in order to rest local cache
 
 	// Setup Cache
-	tinyLfu := cache.NewTinyLFU(10000)
-
 	s.ring = redis.NewRing(&redis.RingOptions{Addrs: map[string]string{s.host: ":" + s.port}})
 	rds := cache.NewRedis(s.ring)
 
-	cacheFactory := cache.NewFactory(rds, tinyLfu)
-	s.cacheFactoryClose = cacheFactory.Close
+	cacheIns := cachekit.New(rds, cache.NewTinyLFU(10000), []cachekit.Setting{
+		{
+			Prefix:    domain.CACHE_PREFIX_SHORT_URL,
+			SharedTTL: time.Hour,
+			LocalTTL:  10 * time.Second,
+		},
+	}, cachekit.Hooks{})
 
-	cacheIns := cacheFactory.NewCache([]cache.Setting{
+	s.nc = cachekit.New(rds, cache.NewTinyLFU(10000), []cachekit.Setting{
 		{
-			Prefix: domain.CACHE_PREFIX_SHORT_URL,
-			CacheAttributes: map[cache.Type]cache.Attribute{
-				cache.SharedCacheType: {TTL: time.Hour},
-				cache.LocalCacheType:  {TTL: 10 * time.Second},
-			},
-			MarshalFunc:   json.Marshal,
-			UnmarshalFunc: json.Unmarshal,
+			Prefix:    domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND,
+			SharedTTL: 30 * time.Second,
+			LocalTTL:  5 * time.Second,
 		},
-	})
+	}, cachekit.Hooks{})
 
 	s.repo = usecase.NewRepository(s.T())
-	s.impl = NewShortUrlUseCase(s.repo, cacheIns)
+	policy, err := policykit.New(policykit.Rules{DenyHosts: []string{"evil.example.com"}, OwnHosts: []string{"localhost"}})
+	s.Require().NoError(err)
+	s.impl = NewShortUrlUseCase(s.repo, cacheIns, s.nc, CRC32IDGenerator, policy, nil, usecase.NewClickRecorder(s.T()), Config{AppHost: "http://localhost", ScanMode: ScanModeNone})
 }
 
 func (s *ShortUrlUseCaseTestSuite) TearDownSubTest() {
-	// clear registered prefix
-	cache.ClearPrefix()
-
 	// clean up all in redis
 	s.Require().NoError(s.ring.ForEachShard(context.Background(), func(ctx context.Context, client *redis.Client) error {
 		return client.FlushDB(ctx).Err()
 	}))
-
-	s.cacheFactoryClose()
 }
 
 func (s *ShortUrlUseCaseTestSuite) TearDownTest() {}
@@ -109,6 +110,7 @@ func (s *ShortUrlUseCaseTestSuite) TestCreate() {
 		name   string
 		req    *domain.CreateReqDto
 		setup  func()
+		check  func()
 		exp    *domain.CreateRespDto
 		expErr error
 	}{
@@ -124,11 +126,46 @@ func (s *ShortUrlUseCaseTestSuite) TestCreate() {
 					Url:      "https://example.com/whatever1",
 					TargetID: targetID,
 					ExpireAt: time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC),
+					Status:   domain.LinkStatusActive,
 				}).Once().Return("testid1", nil)
 			},
 			exp: &domain.CreateRespDto{
 				TargetID: "testid1",
 				ShortUrl: "http://localhost/testid1",
+				Status:   domain.LinkStatusActive,
+			},
+			expErr: nil,
+		},
+		{
+			name: "create a record probed before and evict the negative cache",
+			req: &domain.CreateReqDto{
+				Url:      "https://example.com/whatever1",
+				ExpireAt: time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC),
+			},
+			setup: func() {
+				s.NoError(s.nc.Set(s.ctx, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, "testid1", &domain.GetRespDto{Status: domain.GetRespStatusNotFound}))
+
+				targetID := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte("https://example.com/whatever1")))
+				s.repo.On("Create", s.ctx, &domain.CreateReqDto{
+					Url:      "https://example.com/whatever1",
+					TargetID: targetID,
+					ExpireAt: time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC),
+					Status:   domain.LinkStatusActive,
+				}).Once().Return("testid1", nil)
+			},
+			check: func() {
+				// Check cache
+				// `ca` is from the packageKey of cache library
+				key := fmt.Sprintf("ca:%s:%s", domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, "testid1")
+				s.ErrorIs(s.ring.Get(s.ctx, key).Err(), redis.Nil)
+
+				var obj domain.GetRespDto
+				s.ErrorIs(s.nc.Get(s.ctx, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, "testid1", &obj), cachekit.ErrCacheMiss)
+			},
+			exp: &domain.CreateRespDto{
+				TargetID: "testid1",
+				ShortUrl: "http://localhost/testid1",
+				Status:   domain.LinkStatusActive,
 			},
 			expErr: nil,
 		},
@@ -145,6 +182,7 @@ func (s *ShortUrlUseCaseTestSuite) TestCreate() {
 					Url:      "https://example.com/whatever1",
 					TargetID: targetID,
 					ExpireAt: time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC),
+					Status:   domain.LinkStatusActive,
 				}).Once().Return("", domain.ErrDuplicatedKey)
 
 				// added suffix record
@@ -157,14 +195,28 @@ func (s *ShortUrlUseCaseTestSuite) TestCreate() {
 					Url:      "https://example.com/whatever1",
 					TargetID: targetID,
 					ExpireAt: time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC),
+					Status:   domain.LinkStatusActive,
 				}).Once().Return("testid2", nil)
 			},
 			exp: &domain.CreateRespDto{
 				TargetID: "testid2",
 				ShortUrl: "http://localhost/testid2",
+				Status:   domain.LinkStatusActive,
 			},
 			expErr: nil,
 		},
+		{
+			name: "failed to create a record due to the url rejected by the policy",
+			req: &domain.CreateReqDto{
+				Url:      "https://evil.example.com/whatever1",
+				ExpireAt: time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC),
+			},
+			exp: nil,
+			expErr: &policykit.Violation{
+				Reason: policykit.ReasonHostDenied,
+				Detail: "host evil.example.com is denied by evil.example.com",
+			},
+		},
 		{
 			name: "failed to create a record due to unknown error",
 			req: &domain.CreateReqDto{
@@ -178,6 +230,7 @@ func (s *ShortUrlUseCaseTestSuite) TestCreate() {
 					Url:      "https://example.com/whatever2",
 					TargetID: targetID,
 					ExpireAt: time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC),
+					Status:   domain.LinkStatusActive,
 				}).Once().Return("", errors.New("unknown error"))
 			},
 			exp:    nil,
@@ -192,11 +245,17 @@ func (s *ShortUrlUseCaseTestSuite) TestCreate() {
 			id, err := s.impl.Create(ctx, t.req)
 			s.Equal(err, t.expErr)
 			s.Equal(t.exp, id)
+			if t.check != nil {
+				t.check()
+			}
 		})
 	}
 }
 
 func (s *ShortUrlUseCaseTestSuite) TestGet() {
+	// cachekit caps the TTL by the wall clock rather than the mocked now
+	expireSoon := time.Now().Add(30 * time.Second).UTC().Truncate(time.Second)
+
 	for _, t := range []struct {
 		name   string
 		req    string
@@ -236,6 +295,56 @@ func (s *ShortUrlUseCaseTestSuite) TestGet() {
 			},
 			expErr: nil,
 		},
+		{
+			name: "cache the redirect rules along with the url",
+			req:  "testid1",
+			setup: func() {
+				s.repo.On("Get", s.ctx, "testid1").Once().Return(&domain.GetRespDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: s.now,
+					Rules:    []domain.RedirectRule{{Platform: domain.PlatformIos, Url: "https://apps.example.com/whatever1"}},
+				}, nil)
+			},
+			check: func() {
+				key := fmt.Sprintf("ca:%s:%s", domain.CACHE_PREFIX_SHORT_URL, "testid1")
+				b, err := s.ring.Get(s.ctx, key).Bytes()
+				s.NoError(err)
+
+				var obj domain.GetRespDto
+				s.NoError(json.Unmarshal(b, &obj))
+				s.Equal([]domain.RedirectRule{{Platform: domain.PlatformIos, Url: "https://apps.example.com/whatever1"}}, obj.Rules)
+			},
+			expObj: &domain.GetRespDto{
+				Status:   domain.GetRespStatusNormal,
+				Url:      "https://example.com/whatever1",
+				ExpireAt: s.now,
+				Rules:    []domain.RedirectRule{{Platform: domain.PlatformIos, Url: "https://apps.example.com/whatever1"}},
+			},
+			expErr: nil,
+		},
+		{
+			name: "cache the record no longer than its expiry",
+			req:  "testid1",
+			setup: func() {
+				s.repo.On("Get", s.ctx, "testid1").Once().Return(&domain.GetRespDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: expireSoon,
+				}, nil)
+			},
+			check: func() {
+				key := fmt.Sprintf("ca:%s:%s", domain.CACHE_PREFIX_SHORT_URL, "testid1")
+				ttl, err := s.ring.TTL(s.ctx, key).Result()
+				s.NoError(err)
+				s.Greater(ttl, time.Duration(0))
+				s.LessOrEqual(ttl, 30*time.Second)
+			},
+			expObj: &domain.GetRespDto{
+				Status:   domain.GetRespStatusNormal,
+				Url:      "https://example.com/whatever1",
+				ExpireAt: expireSoon,
+			},
+			expErr: nil,
+		},
 		{
 			name: "failed to get record when the record not found",
 			req:  "testid1",
@@ -245,13 +354,30 @@ func (s *ShortUrlUseCaseTestSuite) TestGet() {
 			check: func() {
 				// Check cache
 				// `ca` is from the packageKey of cache library
-				key := fmt.Sprintf("ca:%s:%s", domain.CACHE_PREFIX_SHORT_URL, "testid1")
+				key := fmt.Sprintf("ca:%s:%s", domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, "testid1")
 				b, err := s.ring.Get(s.ctx, key).Bytes()
 				s.NoError(err)
 
 				var obj domain.GetRespDto
 				s.NoError(json.Unmarshal(b, &obj))
 				s.Equal(&domain.GetRespDto{Status: domain.GetRespStatusNotFound}, &obj)
+
+				// the positive cache doesn't keep it
+				key = fmt.Sprintf("ca:%s:%s", domain.CACHE_PREFIX_SHORT_URL, "testid1")
+				s.ErrorIs(s.ring.Get(s.ctx, key).Err(), redis.Nil)
+			},
+			expObj: &domain.GetRespDto{
+				Status:   domain.GetRespStatusNotFound,
+				Url:      "",
+				ExpireAt: time.Time{},
+			},
+			expErr: nil,
+		},
+		{
+			name: "get record not found from the negative cache without reaching the repository",
+			req:  "testid1",
+			setup: func() {
+				s.NoError(s.nc.Set(s.ctx, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, "testid1", &domain.GetRespDto{Status: domain.GetRespStatusNotFound}))
 			},
 			expObj: &domain.GetRespDto{
 				Status:   domain.GetRespStatusNotFound,
@@ -291,6 +417,42 @@ func (s *ShortUrlUseCaseTestSuite) TestGet() {
 			},
 			expErr: nil,
 		},
+		{
+			name: "failed to get record when the record is disabled",
+			req:  "testid1",
+			setup: func() {
+				s.repo.On("Get", s.ctx, "testid1").Once().Return(&domain.GetRespDto{
+					Url:        "https://example.com/whatever1",
+					ExpireAt:   s.now,
+					LinkStatus: domain.LinkStatusDisabled,
+				}, nil)
+			},
+			expObj: &domain.GetRespDto{
+				Status:     domain.GetRespStatusDisabled,
+				Url:        "https://example.com/whatever1",
+				ExpireAt:   s.now,
+				LinkStatus: domain.LinkStatusDisabled,
+			},
+			expErr: nil,
+		},
+		{
+			name: "get the record waiting for the review",
+			req:  "testid1",
+			setup: func() {
+				s.repo.On("Get", s.ctx, "testid1").Once().Return(&domain.GetRespDto{
+					Url:        "https://example.com/whatever1",
+					ExpireAt:   s.now.Add(time.Hour),
+					LinkStatus: domain.LinkStatusPendingReview,
+				}, nil)
+			},
+			expObj: &domain.GetRespDto{
+				Status:     domain.GetRespStatusPendingReview,
+				Url:        "https://example.com/whatever1",
+				ExpireAt:   s.now.Add(time.Hour),
+				LinkStatus: domain.LinkStatusPendingReview,
+			},
+			expErr: nil,
+		},
 		{
 			name: "failed to get record due to unknown error",
 			req:  "testid2",
@@ -317,6 +479,354 @@ func (s *ShortUrlUseCaseTestSuite) TestGet() {
 	}
 }
 
+type ShortUrlUseCaseScanTestSuite struct {
+	suite.Suite
+	ctx context.Context
+
+	repo    *usecase.Repository
+	nc      *usecase.Cache
+	scanner *usecase.URLScanner
+}
+
+func TestShortUrlUseCaseScanTestSuite(t *testing.T) {
+	suite.Run(t, new(ShortUrlUseCaseScanTestSuite))
+}
+
+func (s *ShortUrlUseCaseScanTestSuite) SetupSubTest() {
+	s.ctx = context.Background()
+	s.repo = usecase.NewRepository(s.T())
+	s.nc = usecase.NewCache(s.T())
+	s.scanner = usecase.NewURLScanner(s.T())
+}
+
+func (s *ShortUrlUseCaseScanTestSuite) TestCreate() {
+	url := "https://example.com/whatever1"
+	targetID := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(url)))
+	for _, t := range []struct {
+		name   string
+		mode   ScanMode
+		setup  func()
+		exp    *domain.CreateRespDto
+		expErr error
+	}{
+		{
+			name: "activate the clean url scanned synchronously",
+			mode: ScanModeSync,
+			setup: func() {
+				s.scanner.On("Scan", mock.Anything, url).Once().Return("", nil)
+				s.expectCreate(targetID, domain.LinkStatusActive)
+			},
+			exp: &domain.CreateRespDto{TargetID: targetID, ShortUrl: "http://localhost/" + targetID, Status: domain.LinkStatusActive},
+		},
+		{
+			name: "reject the url flagged synchronously",
+			mode: ScanModeSync,
+			setup: func() {
+				s.scanner.On("Scan", mock.Anything, url).Once().Return("MALWARE", nil)
+			},
+			expErr: &policykit.Violation{Reason: policykit.ReasonThreatDetected, Detail: "url is flagged as MALWARE"},
+		},
+		{
+			name: "wait for the review if the url failed to be scanned synchronously",
+			mode: ScanModeSync,
+			setup: func() {
+				s.scanner.On("Scan", mock.Anything, url).Once().Return("", context.DeadlineExceeded)
+				s.expectCreate(targetID, domain.LinkStatusPendingReview)
+			},
+			exp: &domain.CreateRespDto{TargetID: targetID, ShortUrl: "http://localhost/" + targetID, Status: domain.LinkStatusPendingReview},
+		},
+		{
+			name: "wait for the review without scanning asynchronously",
+			mode: ScanModeAsync,
+			setup: func() {
+				s.expectCreate(targetID, domain.LinkStatusPendingReview)
+			},
+			exp: &domain.CreateRespDto{TargetID: targetID, ShortUrl: "http://localhost/" + targetID, Status: domain.LinkStatusPendingReview},
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			policy, err := policykit.New(policykit.Rules{})
+			s.Require().NoError(err)
+			impl := NewShortUrlUseCase(s.repo, nil, s.nc, CRC32IDGenerator, policy, s.scanner, usecase.NewClickRecorder(s.T()),
+				Config{AppHost: "http://localhost", ScanMode: t.mode, ScanTimeout: time.Second})
+
+			obj, err := impl.Create(s.ctx, &domain.CreateReqDto{Url: url})
+			s.Equal(t.expErr, err)
+			s.Equal(t.exp, obj)
+		})
+	}
+}
+
+func (s *ShortUrlUseCaseScanTestSuite) TestCreateByCreator() {
+	url := "https://example.com/whatever1"
+	targetID := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(url)))
+	// sha256 of "whatever-key"
+	creator := "f9f760f52ed5791c3531013d2694912dce7ce9728efe3e0661824587d590f873"
+	for _, t := range []struct {
+		name   string
+		setup  func()
+		exp    *domain.CreateRespDto
+		expErr error
+	}{
+		{
+			name: "store the hash of the api key as the creator",
+			setup: func() {
+				s.repo.On("IsCreatorBanned", mock.Anything, creator).Once().Return(false, nil)
+				s.repo.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url: url, TargetID: targetID, Status: domain.LinkStatusActive, APIKey: "whatever-key", Creator: creator,
+				}).Once().Return(targetID, nil)
+				s.nc.On("Del", mock.Anything, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, targetID).Once().Return(nil)
+			},
+			exp: &domain.CreateRespDto{TargetID: targetID, ShortUrl: "http://localhost/" + targetID, Status: domain.LinkStatusActive},
+		},
+		{
+			name: "reject the banned creator",
+			setup: func() {
+				s.repo.On("IsCreatorBanned", mock.Anything, creator).Once().Return(true, nil)
+			},
+			expErr: domain.ErrCreatorBanned,
+		},
+		{
+			name: "failed to check the creator",
+			setup: func() {
+				s.repo.On("IsCreatorBanned", mock.Anything, creator).Once().Return(false, domain.ErrTimeout)
+			},
+			expErr: domain.ErrTimeout,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			policy, err := policykit.New(policykit.Rules{})
+			s.Require().NoError(err)
+			impl := NewShortUrlUseCase(s.repo, nil, s.nc, CRC32IDGenerator, policy, nil, usecase.NewClickRecorder(s.T()),
+				Config{AppHost: "http://localhost", ScanMode: ScanModeNone})
+
+			obj, err := impl.Create(s.ctx, &domain.CreateReqDto{Url: url, APIKey: "whatever-key"})
+			s.Equal(t.expErr, err)
+			s.Equal(t.exp, obj)
+		})
+	}
+}
+
+func (s *ShortUrlUseCaseScanTestSuite) TestCreateWithTemplate() {
+	url := "https://example.com/whatever1"
+	targetID := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(url)))
+	for _, t := range []struct {
+		name   string
+		setup  func()
+		exp    *domain.CreateRespDto
+		expErr error
+	}{
+		{
+			name: "attach the existing param template",
+			setup: func() {
+				s.repo.On("GetParamTemplate", mock.Anything, "newsletter").Once().Return(&domain.ParamTemplateDto{Name: "newsletter"}, nil)
+				s.repo.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url: url, TargetID: targetID, Status: domain.LinkStatusActive, Template: "newsletter",
+				}).Once().Return(targetID, nil)
+				s.nc.On("Del", mock.Anything, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, targetID).Once().Return(nil)
+			},
+			exp: &domain.CreateRespDto{TargetID: targetID, ShortUrl: "http://localhost/" + targetID, Status: domain.LinkStatusActive},
+		},
+		{
+			name: "reject the unknown param template",
+			setup: func() {
+				s.repo.On("GetParamTemplate", mock.Anything, "newsletter").Once().Return(nil, domain.ErrRecordNotFound)
+			},
+			expErr: domain.ErrUnknownTemplate,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			policy, err := policykit.New(policykit.Rules{})
+			s.Require().NoError(err)
+			impl := NewShortUrlUseCase(s.repo, nil, s.nc, CRC32IDGenerator, policy, nil, usecase.NewClickRecorder(s.T()),
+				Config{AppHost: "http://localhost", ScanMode: ScanModeNone})
+
+			obj, err := impl.Create(s.ctx, &domain.CreateReqDto{Url: url, Template: "newsletter"})
+			s.Equal(t.expErr, err)
+			s.Equal(t.exp, obj)
+		})
+	}
+}
+
+func (s *ShortUrlUseCaseScanTestSuite) TestCreateWithRules() {
+	url := "https://example.com/whatever1"
+	targetID := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(url)))
+	for _, t := range []struct {
+		name     string
+		rules    []domain.RedirectRule
+		geoRules []domain.GeoRule
+		variants []domain.Variant
+		setup    func()
+		exp      *domain.CreateRespDto
+		expErr   error
+	}{
+		{
+			name: "create the short url with the rules scanned",
+			rules: []domain.RedirectRule{
+				{Platform: domain.PlatformIos, Url: "https://apps.example.com/ios"},
+				{Platform: domain.PlatformAndroid, Url: "https://play.example.com/android"},
+			},
+			setup: func() {
+				s.scanner.On("Scan", mock.Anything, url).Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://apps.example.com/ios").Once().Return("", errors.New("unknown error"))
+				s.scanner.On("Scan", mock.Anything, "https://play.example.com/android").Once().Return("", nil)
+				s.repo.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url: url, TargetID: targetID, Status: domain.LinkStatusPendingReview, Rules: []domain.RedirectRule{
+						{Platform: domain.PlatformIos, Url: "https://apps.example.com/ios"},
+						{Platform: domain.PlatformAndroid, Url: "https://play.example.com/android"},
+					},
+				}).Once().Return(targetID, nil)
+				s.nc.On("Del", mock.Anything, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, targetID).Once().Return(nil)
+			},
+			exp: &domain.CreateRespDto{TargetID: targetID, ShortUrl: "http://localhost/" + targetID, Status: domain.LinkStatusPendingReview},
+		},
+		{
+			name: "reject the rule flagged",
+			rules: []domain.RedirectRule{
+				{Platform: domain.PlatformMobile, Url: "https://evil.example.com/"},
+			},
+			setup: func() {
+				s.scanner.On("Scan", mock.Anything, url).Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://evil.example.com/").Once().Return("MALWARE", nil)
+			},
+			expErr: &policykit.Violation{Reason: policykit.ReasonThreatDetected, Detail: "url is flagged as MALWARE"},
+		},
+		{
+			name: "reject the unknown platform",
+			rules: []domain.RedirectRule{
+				{Platform: "symbian", Url: "https://example.com/symbian"},
+			},
+			expErr: fmt.Errorf("%w: unknown platform %q of rule %d", domain.ErrInvalidRule, "symbian", 1),
+		},
+		{
+			name: "reject the rule never matched",
+			rules: []domain.RedirectRule{
+				{Platform: domain.PlatformIos, Url: "https://apps.example.com/ios"},
+				{Platform: domain.PlatformIos, Url: "https://apps.example.com/ios2"},
+			},
+			expErr: fmt.Errorf("%w: duplicated platform %q of rule %d", domain.ErrInvalidRule, "ios", 2),
+		},
+		{
+			name: "create the short url with the geo rules scanned",
+			geoRules: []domain.GeoRule{
+				{Country: "TW", Url: "https://tw.example.com/"},
+				{Continent: domain.ContinentEU, Url: "https://eu.example.com/"},
+			},
+			setup: func() {
+				s.scanner.On("Scan", mock.Anything, url).Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://tw.example.com/").Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://eu.example.com/").Once().Return("", nil)
+				s.repo.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url: url, TargetID: targetID, Status: domain.LinkStatusActive, GeoRules: []domain.GeoRule{
+						{Country: "TW", Url: "https://tw.example.com/"},
+						{Continent: domain.ContinentEU, Url: "https://eu.example.com/"},
+					},
+				}).Once().Return(targetID, nil)
+				s.nc.On("Del", mock.Anything, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, targetID).Once().Return(nil)
+			},
+			exp: &domain.CreateRespDto{TargetID: targetID, ShortUrl: "http://localhost/" + targetID, Status: domain.LinkStatusActive},
+		},
+		{
+			name: "reject the geo rule of both the country and the continent",
+			geoRules: []domain.GeoRule{
+				{Country: "TW", Continent: domain.ContinentAS, Url: "https://tw.example.com/"},
+			},
+			expErr: fmt.Errorf("%w: want either the country or the continent of geo rule %d", domain.ErrInvalidRule, 1),
+		},
+		{
+			name: "reject the country code in lower case",
+			geoRules: []domain.GeoRule{
+				{Country: "tw", Url: "https://tw.example.com/"},
+			},
+			expErr: fmt.Errorf("%w: unknown country %q of geo rule %d", domain.ErrInvalidRule, "tw", 1),
+		},
+		{
+			name: "reject the geo rule never matched",
+			geoRules: []domain.GeoRule{
+				{Continent: domain.ContinentEU, Url: "https://eu.example.com/"},
+				{Continent: domain.ContinentEU, Url: "https://eu2.example.com/"},
+			},
+			expErr: fmt.Errorf("%w: duplicated location of geo rule %d", domain.ErrInvalidRule, 2),
+		},
+		{
+			name: "create the short url with the variants scanned",
+			variants: []domain.Variant{
+				{Url: "https://a.example.com/", Weight: 70},
+				{Url: "https://b.example.com/", Weight: 30},
+			},
+			setup: func() {
+				s.scanner.On("Scan", mock.Anything, url).Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://a.example.com/").Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://b.example.com/").Once().Return("", nil)
+				s.repo.On("Create", mock.Anything, &domain.CreateReqDto{
+					Url: url, TargetID: targetID, Status: domain.LinkStatusActive, Variants: []domain.Variant{
+						{Url: "https://a.example.com/", Weight: 70},
+						{Url: "https://b.example.com/", Weight: 30},
+					},
+				}).Once().Return(targetID, nil)
+				s.nc.On("Del", mock.Anything, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, targetID).Once().Return(nil)
+			},
+			exp: &domain.CreateRespDto{TargetID: targetID, ShortUrl: "http://localhost/" + targetID, Status: domain.LinkStatusActive},
+		},
+		{
+			name: "reject the variant flagged",
+			variants: []domain.Variant{
+				{Url: "https://a.example.com/", Weight: 50},
+				{Url: "https://evil.example.com/", Weight: 50},
+			},
+			setup: func() {
+				s.scanner.On("Scan", mock.Anything, url).Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://a.example.com/").Once().Return("", nil)
+				s.scanner.On("Scan", mock.Anything, "https://evil.example.com/").Once().Return("MALWARE", nil)
+			},
+			expErr: &policykit.Violation{Reason: policykit.ReasonThreatDetected, Detail: "url is flagged as MALWARE"},
+		},
+		{
+			name: "reject the single variant",
+			variants: []domain.Variant{
+				{Url: "https://a.example.com/", Weight: 100},
+			},
+			expErr: fmt.Errorf("%w: want at least 2 variants", domain.ErrInvalidVariant),
+		},
+		{
+			name: "reject the variant never picked",
+			variants: []domain.Variant{
+				{Url: "https://a.example.com/", Weight: 100},
+				{Url: "https://b.example.com/", Weight: 0},
+			},
+			expErr: fmt.Errorf("%w: non-positive weight %d of variant %d", domain.ErrInvalidVariant, 0, 2),
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			policy, err := policykit.New(policykit.Rules{})
+			s.Require().NoError(err)
+			impl := NewShortUrlUseCase(s.repo, nil, s.nc, CRC32IDGenerator, policy, s.scanner, usecase.NewClickRecorder(s.T()),
+				Config{AppHost: "http://localhost", ScanMode: ScanModeSync, ScanTimeout: time.Second})
+
+			obj, err := impl.Create(s.ctx, &domain.CreateReqDto{Url: url, Rules: t.rules, GeoRules: t.geoRules, Variants: t.variants})
+			s.Equal(t.expErr, err)
+			s.Equal(t.exp, obj)
+		})
+	}
+}
+
+func (s *ShortUrlUseCaseScanTestSuite) expectCreate(targetID string, status domain.LinkStatus) {
+	s.repo.On("Create", mock.Anything, &domain.CreateReqDto{Url: "https://example.com/whatever1", TargetID: targetID, Status: status}).Once().Return(targetID, nil)
+	s.nc.On("Del", mock.Anything, domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, targetID).Once().Return(nil)
+}
+
 func ConnectToDockerTestRedis() (string, string, func() error, error) {
 	// Set up test db
 	pool, err := dockertest.NewPool("")
@@ -355,3 +865,75 @@ func ConnectToDockerTestRedis() (string, string, func() error, error) {
 		return pool.Purge(resource)
 	}, nil
 }
+
+type ShortUrlUseCaseMetricsTestSuite struct {
+	suite.Suite
+	ctx  context.Context
+	repo *usecase.Repository
+	impl UseCase
+}
+
+func TestShortUrlUseCaseMetricsTestSuite(t *testing.T) {
+	suite.Run(t, new(ShortUrlUseCaseMetricsTestSuite))
+}
+
+func (s *ShortUrlUseCaseMetricsTestSuite) SetupSubTest() {
+	s.ctx = context.Background()
+	s.repo = usecase.NewRepository(s.T())
+
+	// the local tiers only, the hooks of the negative cache record its hits like main does
+	c := cachekit.New(nil, cache.NewTinyLFU(100), []cachekit.Setting{
+		{Prefix: domain.CACHE_PREFIX_SHORT_URL, LocalTTL: time.Minute},
+	}, cachekit.Hooks{})
+	nc := cachekit.New(nil, cache.NewTinyLFU(100), []cachekit.Setting{
+		{Prefix: domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, LocalTTL: time.Minute},
+	}, cachekit.Hooks{OnCacheHit: metrics.OnCacheHit})
+	policy, err := policykit.New(policykit.Rules{})
+	s.Require().NoError(err)
+	s.impl = NewShortUrlUseCase(s.repo, c, nc, CRC32IDGenerator, policy, nil, usecase.NewClickRecorder(s.T()), Config{AppHost: "http://localhost", ScanMode: ScanModeNone})
+}
+
+func (s *ShortUrlUseCaseMetricsTestSuite) TestNegativeCacheLookups() {
+	for _, t := range []struct {
+		name    string
+		id      string
+		setup   func()
+		expHit  float64
+		expMiss float64
+	}{
+		{
+			name: "never count the existing ids as the misses of the negative cache",
+			id:   "metricsid1",
+			setup: func() {
+				s.repo.On("Get", mock.Anything, "metricsid1").Once().Return(&domain.GetRespDto{
+					Url:      "https://example.com/whatever1",
+					ExpireAt: time.Now().Add(time.Hour),
+				}, nil)
+			},
+		},
+		{
+			name: "count the miss of the id not found, then the hit",
+			id:   "metricsid2",
+			setup: func() {
+				s.repo.On("Get", mock.Anything, "metricsid2").Once().Return(nil, domain.ErrRecordNotFound)
+			},
+			expHit:  1,
+			expMiss: 1,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			t.setup()
+			hit := metrics.CacheLookups.WithLabelValues(domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, "hit")
+			miss := metrics.CacheLookups.WithLabelValues(domain.CACHE_PREFIX_SHORT_URL_NOT_FOUND, "miss")
+			hitBefore, missBefore := testutil.ToFloat64(hit), testutil.ToFloat64(miss)
+
+			for range 2 {
+				_, err := s.impl.Get(s.ctx, t.id)
+				s.Require().NoError(err)
+			}
+
+			s.Equal(hitBefore+t.expHit, testutil.ToFloat64(hit))
+			s.Equal(missBefore+t.expMiss, testutil.ToFloat64(miss))
+		})
+	}
+}
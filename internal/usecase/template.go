@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/pkg/logkit"
+)
+
+// paramTemplateSample validates the placeholders of the param templates on saving them
+var paramTemplateSample = &domain.ParamTemplateData{ID: "abc123", Date: "2025-01-01", ClickID: "0123456789abcdef"}
+
+type ShortUrlParamTemplateUseCase struct {
+	repo Repository
+	c    Cache
+}
+
+// NewShortUrlParamTemplateUseCase generates the use case implementation of the param template use case interface.
+// `c` caches the param templates by the name for rendering them on redirecting.
+func NewShortUrlParamTemplateUseCase(repo Repository, c Cache) ParamTemplateUseCase {
+	return &ShortUrlParamTemplateUseCase{
+		repo: repo,
+		c:    c,
+	}
+}
+
+// Create validates and creates the param template
+func (uc *ShortUrlParamTemplateUseCase) Create(ctx context.Context, templateDto *domain.ParamTemplateDto) error {
+	if _, err := renderParamTemplate(templateDto.Query, paramTemplateSample); err != nil {
+		return err
+	}
+
+	templateDto.CreatedAt = now()
+	templateDto.UpdatedAt = templateDto.CreatedAt
+	if err := uc.repo.CreateParamTemplate(ctx, templateDto); err != nil {
+		return err
+	}
+	// the one deleted before is cached as empty
+	uc.purge(ctx, templateDto.Name)
+	slog.InfoContext(ctx, "ShortUrlParamTemplateUseCase.Create. Create the param template", "name", templateDto.Name)
+	return nil
+}
+
+// Get gets the param template by the name
+func (uc *ShortUrlParamTemplateUseCase) Get(ctx context.Context, name string) (*domain.ParamTemplateDto, error) {
+	return uc.repo.GetParamTemplate(ctx, name)
+}
+
+// List lists all the param templates by the name
+func (uc *ShortUrlParamTemplateUseCase) List(ctx context.Context) ([]*domain.ParamTemplateDto, error) {
+	return uc.repo.ListParamTemplates(ctx)
+}
+
+// Update validates and replaces the query of the param template, which takes effect on the other instances
+// once their local cache expires
+func (uc *ShortUrlParamTemplateUseCase) Update(ctx context.Context, templateDto *domain.ParamTemplateDto) error {
+	if _, err := renderParamTemplate(templateDto.Query, paramTemplateSample); err != nil {
+		return err
+	}
+
+	templateDto.UpdatedAt = now()
+	if err := uc.repo.UpdateParamTemplate(ctx, templateDto); err != nil {
+		return err
+	}
+	uc.purge(ctx, templateDto.Name)
+	slog.InfoContext(ctx, "ShortUrlParamTemplateUseCase.Update. Update the param template", "name", templateDto.Name)
+	return nil
+}
+
+// Delete deletes the param template, the short urls attached to it are redirected without the params
+func (uc *ShortUrlParamTemplateUseCase) Delete(ctx context.Context, name string) error {
+	if err := uc.repo.DeleteParamTemplate(ctx, name); err != nil {
+		return err
+	}
+	uc.purge(ctx, name)
+	slog.InfoContext(ctx, "ShortUrlParamTemplateUseCase.Delete. Delete the param template", "name", name)
+	return nil
+}
+
+// Render renders the params of the cached param template by the placeholders
+func (uc *ShortUrlParamTemplateUseCase) Render(ctx context.Context, name string, data *domain.ParamTemplateData) (url.Values, error) {
+	obj := &domain.ParamTemplateDto{}
+	if err := uc.c.GetByFunc(ctx, domain.CACHE_PREFIX_PARAM_TEMPLATE, name, obj, func(ctx context.Context) (interface{}, error) {
+		obj, err := uc.repo.GetParamTemplate(ctx, name)
+		if err == domain.ErrRecordNotFound {
+			// cache the deleted one as empty, so that its short urls don't reach the DB on every redirect
+			return &domain.ParamTemplateDto{Name: name}, nil
+		}
+		return obj, err
+	}); err != nil {
+		slog.ErrorContext(ctx, "ShortUrlParamTemplateUseCase.Render. Failed to get the param template from cache", "name", name, logkit.Err(err))
+		return nil, err
+	}
+	return renderParamTemplate(obj.Query, data)
+}
+
+func (uc *ShortUrlParamTemplateUseCase) purge(ctx context.Context, name string) {
+	if err := uc.c.Del(ctx, domain.CACHE_PREFIX_PARAM_TEMPLATE, name); err != nil {
+		slog.WarnContext(ctx, "ShortUrlParamTemplateUseCase.purge. Failed to purge the cache", "name", name, logkit.Err(err))
+	}
+}
+
+// paramPlaceholders removes the placeholders of the param templates, so that the unknown ones are left
+var paramPlaceholders = strings.NewReplacer("{{.ID}}", "", "{{.Date}}", "", "{{.ClickID}}", "")
+
+// renderParamTemplate parses the query and replaces the placeholders of its values, which are substituted as they are
+// rather than executed, and the unknown ones are rejected.
+// The rendered values are escaped on encoding the query, so they can't add the other params.
+func renderParamTemplate(query string, data *domain.ParamTemplateData) (url.Values, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", domain.ErrInvalidTemplate, err)
+	}
+	var replacer *strings.Replacer
+	for key, vals := range values {
+		if key == "" {
+			return nil, fmt.Errorf("%w: empty param name", domain.ErrInvalidTemplate)
+		}
+		for i, val := range vals {
+			if !strings.Contains(val, "{{") {
+				continue
+			}
+			if strings.Contains(paramPlaceholders.Replace(val), "{{") {
+				return nil, fmt.Errorf("%w: unknown placeholder in %q", domain.ErrInvalidTemplate, val)
+			}
+			if replacer == nil {
+				replacer = strings.NewReplacer("{{.ID}}", data.ID, "{{.Date}}", data.Date, "{{.ClickID}}", data.ClickID)
+			}
+			vals[i] = replacer.Replace(val)
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/internal/domain"
+	"github.com/Hao1995/short-url/mocks/internal_/usecase"
+	"github.com/Hao1995/short-url/pkg/cachekit"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/viney-shih/go-cache"
+)
+
+type ShortUrlParamTemplateUseCaseTestSuite struct {
+	suite.Suite
+	ctx context.Context
+	now time.Time
+
+	repo *usecase.Repository
+	c    *usecase.Cache
+	impl ParamTemplateUseCase
+}
+
+func TestShortUrlParamTemplateUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ShortUrlParamTemplateUseCaseTestSuite))
+}
+
+func (s *ShortUrlParamTemplateUseCaseTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	s.now = time.Date(2025, 2, 10, 8, 30, 15, 0, time.UTC)
+	now = func() time.Time {
+		return s.now
+	}
+}
+
+func (s *ShortUrlParamTemplateUseCaseTestSuite) TearDownSuite() {
+	now = func() time.Time {
+		return time.Now()
+	}
+}
+
+func (s *ShortUrlParamTemplateUseCaseTestSuite) SetupSubTest() {
+	s.repo = usecase.NewRepository(s.T())
+	s.c = usecase.NewCache(s.T())
+	s.impl = NewShortUrlParamTemplateUseCase(s.repo, s.c)
+}
+
+func (s *ShortUrlParamTemplateUseCaseTestSuite) TestCreate() {
+	for _, t := range []struct {
+		name   string
+		query  string
+		setup  func()
+		expErr error
+	}{
+		{
+			name:  "create the param template and purge the cache",
+			query: "utm_source=newsletter&utm_campaign={{.Date}}",
+			setup: func() {
+				s.repo.On("CreateParamTemplate", s.ctx, &domain.ParamTemplateDto{
+					Name: "newsletter", Query: "utm_source=newsletter&utm_campaign={{.Date}}", CreatedAt: s.now, UpdatedAt: s.now,
+				}).Once().Return(nil)
+				s.c.On("Del", s.ctx, domain.CACHE_PREFIX_PARAM_TEMPLATE, "newsletter").Once().Return(nil)
+			},
+		},
+		{
+			name:  "name taken",
+			query: "utm_source=newsletter",
+			setup: func() {
+				s.repo.On("CreateParamTemplate", s.ctx, mock.Anything).Once().Return(domain.ErrDuplicatedKey)
+			},
+			expErr: domain.ErrDuplicatedKey,
+		},
+		{
+			name:   "unknown placeholder",
+			query:  "utm_campaign={{.Whatever}}",
+			expErr: domain.ErrInvalidTemplate,
+		},
+		{
+			name:   "broken placeholder",
+			query:  "utm_campaign={{.Date",
+			expErr: domain.ErrInvalidTemplate,
+		},
+		{
+			name:   "builtin function",
+			query:  "utm_campaign={{html .Date}}",
+			expErr: domain.ErrInvalidTemplate,
+		},
+		{
+			name:   "invalid query",
+			query:  "utm_campaign=%zz",
+			expErr: domain.ErrInvalidTemplate,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+			err := s.impl.Create(s.ctx, &domain.ParamTemplateDto{Name: "newsletter", Query: t.query})
+			s.ErrorIs(err, t.expErr)
+		})
+	}
+}
+
+func (s *ShortUrlParamTemplateUseCaseTestSuite) TestUpdateAndDelete() {
+	s.Suite.Run("update the param template and purge the cache", func() {
+		s.repo.On("UpdateParamTemplate", s.ctx, &domain.ParamTemplateDto{Name: "newsletter", Query: "utm_source=mail", UpdatedAt: s.now}).Once().Return(nil)
+		s.c.On("Del", s.ctx, domain.CACHE_PREFIX_PARAM_TEMPLATE, "newsletter").Once().Return(nil)
+
+		s.NoError(s.impl.Update(s.ctx, &domain.ParamTemplateDto{Name: "newsletter", Query: "utm_source=mail"}))
+	})
+	s.Suite.Run("update the param template not found", func() {
+		s.repo.On("UpdateParamTemplate", s.ctx, mock.Anything).Once().Return(domain.ErrRecordNotFound)
+
+		s.Equal(domain.ErrRecordNotFound, s.impl.Update(s.ctx, &domain.ParamTemplateDto{Name: "newsletter", Query: "utm_source=mail"}))
+	})
+	s.Suite.Run("delete the param template and purge the cache despite the failure", func() {
+		s.repo.On("DeleteParamTemplate", s.ctx, "newsletter").Once().Return(nil)
+		s.c.On("Del", s.ctx, domain.CACHE_PREFIX_PARAM_TEMPLATE, "newsletter").Once().Return(errors.New("whatever"))
+
+		s.NoError(s.impl.Delete(s.ctx, "newsletter"))
+	})
+}
+
+func (s *ShortUrlParamTemplateUseCaseTestSuite) TestRender() {
+	data := &domain.ParamTemplateData{ID: "testid1", Date: "2025-02-10", ClickID: "whatever-click"}
+	for _, t := range []struct {
+		name   string
+		setup  func()
+		exp    url.Values
+		expErr error
+	}{
+		{
+			name: "render the placeholders",
+			setup: func() {
+				s.repo.On("GetParamTemplate", mock.Anything, "newsletter").Once().Return(&domain.ParamTemplateDto{
+					Name: "newsletter", Query: "utm_source=newsletter&utm_campaign={{.Date}}-{{.ID}}&cid={{.ClickID}}&cid=fixed",
+				}, nil)
+			},
+			exp: url.Values{"utm_source": {"newsletter"}, "utm_campaign": {"2025-02-10-testid1"}, "cid": {"whatever-click", "fixed"}},
+		},
+		{
+			name: "render the value as it is",
+			setup: func() {
+				s.repo.On("GetParamTemplate", mock.Anything, "newsletter").Once().Return(&domain.ParamTemplateDto{
+					Name: "newsletter", Query: "utm_campaign=%7B%7B.ID%7D%7D%7B%7B.ID%7D%7D}}",
+				}, nil)
+			},
+			exp: url.Values{"utm_campaign": {"testid1testid1}}"}},
+		},
+		{
+			name: "reject the unknown placeholder saved before",
+			setup: func() {
+				s.repo.On("GetParamTemplate", mock.Anything, "newsletter").Once().Return(&domain.ParamTemplateDto{
+					Name: "newsletter", Query: "utm_campaign={{.Date | html}}",
+				}, nil)
+			},
+			expErr: domain.ErrInvalidTemplate,
+		},
+		{
+			name: "render nothing for the param template deleted",
+			setup: func() {
+				s.repo.On("GetParamTemplate", mock.Anything, "newsletter").Once().Return(nil, domain.ErrRecordNotFound)
+			},
+			exp: url.Values{},
+		},
+		{
+			name: "failed to get the param template",
+			setup: func() {
+				s.repo.On("GetParamTemplate", mock.Anything, "newsletter").Once().Return(nil, domain.ErrTimeout)
+			},
+			expErr: domain.ErrTimeout,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			t.setup()
+			c := cachekit.New(nil, cache.NewTinyLFU(10), []cachekit.Setting{
+				{Prefix: domain.CACHE_PREFIX_PARAM_TEMPLATE, LocalTTL: time.Minute},
+			}, cachekit.Hooks{})
+			impl := NewShortUrlParamTemplateUseCase(s.repo, c)
+
+			// the second one is rendered from the cache
+			for range 2 {
+				values, err := impl.Render(s.ctx, "newsletter", data)
+				s.ErrorIs(err, t.expErr)
+				s.Equal(t.exp, values)
+				if err != nil {
+					break
+				}
+			}
+		})
+	}
+}
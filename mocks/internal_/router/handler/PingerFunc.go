@@ -0,0 +1,82 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package handler
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PingerFunc is an autogenerated mock type for the PingerFunc type
+type PingerFunc struct {
+	mock.Mock
+}
+
+type PingerFunc_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PingerFunc) EXPECT() *PingerFunc_Expecter {
+	return &PingerFunc_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx
+func (_m *PingerFunc) Execute(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PingerFunc_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type PingerFunc_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *PingerFunc_Expecter) Execute(ctx interface{}) *PingerFunc_Execute_Call {
+	return &PingerFunc_Execute_Call{Call: _e.mock.On("Execute", ctx)}
+}
+
+func (_c *PingerFunc_Execute_Call) Run(run func(ctx context.Context)) *PingerFunc_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *PingerFunc_Execute_Call) Return(_a0 error) *PingerFunc_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PingerFunc_Execute_Call) RunAndReturn(run func(context.Context) error) *PingerFunc_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPingerFunc creates a new instance of PingerFunc. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPingerFunc(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PingerFunc {
+	mock := &PingerFunc{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
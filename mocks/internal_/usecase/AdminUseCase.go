@@ -0,0 +1,419 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package usecase
+
+import (
+	context "context"
+
+	domain "github.com/Hao1995/short-url/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// AdminUseCase is an autogenerated mock type for the AdminUseCase type
+type AdminUseCase struct {
+	mock.Mock
+}
+
+type AdminUseCase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AdminUseCase) EXPECT() *AdminUseCase_Expecter {
+	return &AdminUseCase_Expecter{mock: &_m.Mock}
+}
+
+// Clicks provides a mock function with given fields: ctx, id, limit
+func (_m *AdminUseCase) Clicks(ctx context.Context, id string, limit int) ([]*domain.ClickDto, error) {
+	ret := _m.Called(ctx, id, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Clicks")
+	}
+
+	var r0 []*domain.ClickDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*domain.ClickDto, error)); ok {
+		return rf(ctx, id, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*domain.ClickDto); ok {
+		r0 = rf(ctx, id, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ClickDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, id, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AdminUseCase_Clicks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Clicks'
+type AdminUseCase_Clicks_Call struct {
+	*mock.Call
+}
+
+// Clicks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - limit int
+func (_e *AdminUseCase_Expecter) Clicks(ctx interface{}, id interface{}, limit interface{}) *AdminUseCase_Clicks_Call {
+	return &AdminUseCase_Clicks_Call{Call: _e.mock.On("Clicks", ctx, id, limit)}
+}
+
+func (_c *AdminUseCase_Clicks_Call) Run(run func(ctx context.Context, id string, limit int)) *AdminUseCase_Clicks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *AdminUseCase_Clicks_Call) Return(_a0 []*domain.ClickDto, _a1 error) *AdminUseCase_Clicks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AdminUseCase_Clicks_Call) RunAndReturn(run func(context.Context, string, int) ([]*domain.ClickDto, error)) *AdminUseCase_Clicks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Extend provides a mock function with given fields: ctx, id, expireAt
+func (_m *AdminUseCase) Extend(ctx context.Context, id string, expireAt time.Time) error {
+	ret := _m.Called(ctx, id, expireAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Extend")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, id, expireAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AdminUseCase_Extend_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Extend'
+type AdminUseCase_Extend_Call struct {
+	*mock.Call
+}
+
+// Extend is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - expireAt time.Time
+func (_e *AdminUseCase_Expecter) Extend(ctx interface{}, id interface{}, expireAt interface{}) *AdminUseCase_Extend_Call {
+	return &AdminUseCase_Extend_Call{Call: _e.mock.On("Extend", ctx, id, expireAt)}
+}
+
+func (_c *AdminUseCase_Extend_Call) Run(run func(ctx context.Context, id string, expireAt time.Time)) *AdminUseCase_Extend_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *AdminUseCase_Extend_Call) Return(_a0 error) *AdminUseCase_Extend_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AdminUseCase_Extend_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *AdminUseCase_Extend_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Inspect provides a mock function with given fields: ctx, id
+func (_m *AdminUseCase) Inspect(ctx context.Context, id string) (*domain.ShortUrlDto, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Inspect")
+	}
+
+	var r0 *domain.ShortUrlDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.ShortUrlDto, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ShortUrlDto); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ShortUrlDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AdminUseCase_Inspect_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Inspect'
+type AdminUseCase_Inspect_Call struct {
+	*mock.Call
+}
+
+// Inspect is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *AdminUseCase_Expecter) Inspect(ctx interface{}, id interface{}) *AdminUseCase_Inspect_Call {
+	return &AdminUseCase_Inspect_Call{Call: _e.mock.On("Inspect", ctx, id)}
+}
+
+func (_c *AdminUseCase_Inspect_Call) Run(run func(ctx context.Context, id string)) *AdminUseCase_Inspect_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AdminUseCase_Inspect_Call) Return(_a0 *domain.ShortUrlDto, _a1 error) *AdminUseCase_Inspect_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AdminUseCase_Inspect_Call) RunAndReturn(run func(context.Context, string) (*domain.ShortUrlDto, error)) *AdminUseCase_Inspect_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Purge provides a mock function with given fields: ctx, id
+func (_m *AdminUseCase) Purge(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Purge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AdminUseCase_Purge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Purge'
+type AdminUseCase_Purge_Call struct {
+	*mock.Call
+}
+
+// Purge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *AdminUseCase_Expecter) Purge(ctx interface{}, id interface{}) *AdminUseCase_Purge_Call {
+	return &AdminUseCase_Purge_Call{Call: _e.mock.On("Purge", ctx, id)}
+}
+
+func (_c *AdminUseCase_Purge_Call) Run(run func(ctx context.Context, id string)) *AdminUseCase_Purge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AdminUseCase_Purge_Call) Return(_a0 error) *AdminUseCase_Purge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AdminUseCase_Purge_Call) RunAndReturn(run func(context.Context, string) error) *AdminUseCase_Purge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Search provides a mock function with given fields: ctx, listReqDto
+func (_m *AdminUseCase) Search(ctx context.Context, listReqDto *domain.ListReqDto) ([]*domain.ShortUrlDto, error) {
+	ret := _m.Called(ctx, listReqDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []*domain.ShortUrlDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListReqDto) ([]*domain.ShortUrlDto, error)); ok {
+		return rf(ctx, listReqDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListReqDto) []*domain.ShortUrlDto); ok {
+		r0 = rf(ctx, listReqDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ShortUrlDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ListReqDto) error); ok {
+		r1 = rf(ctx, listReqDto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AdminUseCase_Search_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Search'
+type AdminUseCase_Search_Call struct {
+	*mock.Call
+}
+
+// Search is a helper method to define mock.On call
+//   - ctx context.Context
+//   - listReqDto *domain.ListReqDto
+func (_e *AdminUseCase_Expecter) Search(ctx interface{}, listReqDto interface{}) *AdminUseCase_Search_Call {
+	return &AdminUseCase_Search_Call{Call: _e.mock.On("Search", ctx, listReqDto)}
+}
+
+func (_c *AdminUseCase_Search_Call) Run(run func(ctx context.Context, listReqDto *domain.ListReqDto)) *AdminUseCase_Search_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ListReqDto))
+	})
+	return _c
+}
+
+func (_c *AdminUseCase_Search_Call) Return(_a0 []*domain.ShortUrlDto, _a1 error) *AdminUseCase_Search_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AdminUseCase_Search_Call) RunAndReturn(run func(context.Context, *domain.ListReqDto) ([]*domain.ShortUrlDto, error)) *AdminUseCase_Search_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetStatus provides a mock function with given fields: ctx, id, status
+func (_m *AdminUseCase) SetStatus(ctx context.Context, id string, status domain.LinkStatus) error {
+	ret := _m.Called(ctx, id, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.LinkStatus) error); ok {
+		r0 = rf(ctx, id, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AdminUseCase_SetStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetStatus'
+type AdminUseCase_SetStatus_Call struct {
+	*mock.Call
+}
+
+// SetStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - status domain.LinkStatus
+func (_e *AdminUseCase_Expecter) SetStatus(ctx interface{}, id interface{}, status interface{}) *AdminUseCase_SetStatus_Call {
+	return &AdminUseCase_SetStatus_Call{Call: _e.mock.On("SetStatus", ctx, id, status)}
+}
+
+func (_c *AdminUseCase_SetStatus_Call) Run(run func(ctx context.Context, id string, status domain.LinkStatus)) *AdminUseCase_SetStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.LinkStatus))
+	})
+	return _c
+}
+
+func (_c *AdminUseCase_SetStatus_Call) Return(_a0 error) *AdminUseCase_SetStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AdminUseCase_SetStatus_Call) RunAndReturn(run func(context.Context, string, domain.LinkStatus) error) *AdminUseCase_SetStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function with given fields: ctx, id
+func (_m *AdminUseCase) Stats(ctx context.Context, id string) ([]*domain.VariantStatsDto, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 []*domain.VariantStatsDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*domain.VariantStatsDto, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*domain.VariantStatsDto); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.VariantStatsDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AdminUseCase_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type AdminUseCase_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *AdminUseCase_Expecter) Stats(ctx interface{}, id interface{}) *AdminUseCase_Stats_Call {
+	return &AdminUseCase_Stats_Call{Call: _e.mock.On("Stats", ctx, id)}
+}
+
+func (_c *AdminUseCase_Stats_Call) Run(run func(ctx context.Context, id string)) *AdminUseCase_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *AdminUseCase_Stats_Call) Return(_a0 []*domain.VariantStatsDto, _a1 error) *AdminUseCase_Stats_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AdminUseCase_Stats_Call) RunAndReturn(run func(context.Context, string) ([]*domain.VariantStatsDto, error)) *AdminUseCase_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAdminUseCase creates a new instance of AdminUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdminUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AdminUseCase {
+	mock := &AdminUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
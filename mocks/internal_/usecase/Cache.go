@@ -0,0 +1,248 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package usecase
+
+import (
+	context "context"
+
+	cachekit "github.com/Hao1995/short-url/pkg/cachekit"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Cache is an autogenerated mock type for the Cache type
+type Cache struct {
+	mock.Mock
+}
+
+type Cache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Cache) EXPECT() *Cache_Expecter {
+	return &Cache_Expecter{mock: &_m.Mock}
+}
+
+// Del provides a mock function with given fields: ctx, prefix, keys
+func (_m *Cache) Del(ctx context.Context, prefix string, keys ...string) error {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, prefix)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Del")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) error); ok {
+		r0 = rf(ctx, prefix, keys...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Cache_Del_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Del'
+type Cache_Del_Call struct {
+	*mock.Call
+}
+
+// Del is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+//   - keys ...string
+func (_e *Cache_Expecter) Del(ctx interface{}, prefix interface{}, keys ...interface{}) *Cache_Del_Call {
+	return &Cache_Del_Call{Call: _e.mock.On("Del",
+		append([]interface{}{ctx, prefix}, keys...)...)}
+}
+
+func (_c *Cache_Del_Call) Run(run func(ctx context.Context, prefix string, keys ...string)) *Cache_Del_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *Cache_Del_Call) Return(_a0 error) *Cache_Del_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Cache_Del_Call) RunAndReturn(run func(context.Context, string, ...string) error) *Cache_Del_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, prefix, key, container
+func (_m *Cache) Get(ctx context.Context, prefix string, key string, container interface{}) error {
+	ret := _m.Called(ctx, prefix, key, container)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, interface{}) error); ok {
+		r0 = rf(ctx, prefix, key, container)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Cache_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type Cache_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+//   - key string
+//   - container interface{}
+func (_e *Cache_Expecter) Get(ctx interface{}, prefix interface{}, key interface{}, container interface{}) *Cache_Get_Call {
+	return &Cache_Get_Call{Call: _e.mock.On("Get", ctx, prefix, key, container)}
+}
+
+func (_c *Cache_Get_Call) Run(run func(ctx context.Context, prefix string, key string, container interface{})) *Cache_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(interface{}))
+	})
+	return _c
+}
+
+func (_c *Cache_Get_Call) Return(_a0 error) *Cache_Get_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Cache_Get_Call) RunAndReturn(run func(context.Context, string, string, interface{}) error) *Cache_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByFunc provides a mock function with given fields: ctx, prefix, key, container, loader
+func (_m *Cache) GetByFunc(ctx context.Context, prefix string, key string, container interface{}, loader cachekit.LoaderFunc) error {
+	ret := _m.Called(ctx, prefix, key, container, loader)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByFunc")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, interface{}, cachekit.LoaderFunc) error); ok {
+		r0 = rf(ctx, prefix, key, container, loader)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Cache_GetByFunc_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByFunc'
+type Cache_GetByFunc_Call struct {
+	*mock.Call
+}
+
+// GetByFunc is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+//   - key string
+//   - container interface{}
+//   - loader cachekit.LoaderFunc
+func (_e *Cache_Expecter) GetByFunc(ctx interface{}, prefix interface{}, key interface{}, container interface{}, loader interface{}) *Cache_GetByFunc_Call {
+	return &Cache_GetByFunc_Call{Call: _e.mock.On("GetByFunc", ctx, prefix, key, container, loader)}
+}
+
+func (_c *Cache_GetByFunc_Call) Run(run func(ctx context.Context, prefix string, key string, container interface{}, loader cachekit.LoaderFunc)) *Cache_GetByFunc_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(interface{}), args[4].(cachekit.LoaderFunc))
+	})
+	return _c
+}
+
+func (_c *Cache_GetByFunc_Call) Return(_a0 error) *Cache_GetByFunc_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Cache_GetByFunc_Call) RunAndReturn(run func(context.Context, string, string, interface{}, cachekit.LoaderFunc) error) *Cache_GetByFunc_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Set provides a mock function with given fields: ctx, prefix, key, value
+func (_m *Cache) Set(ctx context.Context, prefix string, key string, value interface{}) error {
+	ret := _m.Called(ctx, prefix, key, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, interface{}) error); ok {
+		r0 = rf(ctx, prefix, key, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Cache_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
+type Cache_Set_Call struct {
+	*mock.Call
+}
+
+// Set is a helper method to define mock.On call
+//   - ctx context.Context
+//   - prefix string
+//   - key string
+//   - value interface{}
+func (_e *Cache_Expecter) Set(ctx interface{}, prefix interface{}, key interface{}, value interface{}) *Cache_Set_Call {
+	return &Cache_Set_Call{Call: _e.mock.On("Set", ctx, prefix, key, value)}
+}
+
+func (_c *Cache_Set_Call) Run(run func(ctx context.Context, prefix string, key string, value interface{})) *Cache_Set_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(interface{}))
+	})
+	return _c
+}
+
+func (_c *Cache_Set_Call) Return(_a0 error) *Cache_Set_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Cache_Set_Call) RunAndReturn(run func(context.Context, string, string, interface{}) error) *Cache_Set_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewCache creates a new instance of Cache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Cache {
+	mock := &Cache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
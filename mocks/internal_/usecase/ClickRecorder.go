@@ -0,0 +1,71 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package usecase
+
+import (
+	context "context"
+
+	domain "github.com/Hao1995/short-url/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ClickRecorder is an autogenerated mock type for the ClickRecorder type
+type ClickRecorder struct {
+	mock.Mock
+}
+
+type ClickRecorder_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ClickRecorder) EXPECT() *ClickRecorder_Expecter {
+	return &ClickRecorder_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function with given fields: ctx, clickDto
+func (_m *ClickRecorder) Record(ctx context.Context, clickDto *domain.ClickDto) {
+	_m.Called(ctx, clickDto)
+}
+
+// ClickRecorder_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type ClickRecorder_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clickDto *domain.ClickDto
+func (_e *ClickRecorder_Expecter) Record(ctx interface{}, clickDto interface{}) *ClickRecorder_Record_Call {
+	return &ClickRecorder_Record_Call{Call: _e.mock.On("Record", ctx, clickDto)}
+}
+
+func (_c *ClickRecorder_Record_Call) Run(run func(ctx context.Context, clickDto *domain.ClickDto)) *ClickRecorder_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ClickDto))
+	})
+	return _c
+}
+
+func (_c *ClickRecorder_Record_Call) Return() *ClickRecorder_Record_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *ClickRecorder_Record_Call) RunAndReturn(run func(context.Context, *domain.ClickDto)) *ClickRecorder_Record_Call {
+	_c.Run(run)
+	return _c
+}
+
+// NewClickRecorder creates a new instance of ClickRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClickRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClickRecorder {
+	mock := &ClickRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,78 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package usecase
+
+import mock "github.com/stretchr/testify/mock"
+
+// IDGenerator is an autogenerated mock type for the IDGenerator type
+type IDGenerator struct {
+	mock.Mock
+}
+
+type IDGenerator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *IDGenerator) EXPECT() *IDGenerator_Expecter {
+	return &IDGenerator_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: url
+func (_m *IDGenerator) Execute(url string) string {
+	ret := _m.Called(url)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(string) string); ok {
+		r0 = rf(url)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// IDGenerator_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type IDGenerator_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - url string
+func (_e *IDGenerator_Expecter) Execute(url interface{}) *IDGenerator_Execute_Call {
+	return &IDGenerator_Execute_Call{Call: _e.mock.On("Execute", url)}
+}
+
+func (_c *IDGenerator_Execute_Call) Run(run func(url string)) *IDGenerator_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *IDGenerator_Execute_Call) Return(_a0 string) *IDGenerator_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IDGenerator_Execute_Call) RunAndReturn(run func(string) string) *IDGenerator_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewIDGenerator creates a new instance of IDGenerator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIDGenerator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IDGenerator {
+	mock := &IDGenerator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,357 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package usecase
+
+import (
+	context "context"
+
+	domain "github.com/Hao1995/short-url/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	url "net/url"
+)
+
+// ParamTemplateUseCase is an autogenerated mock type for the ParamTemplateUseCase type
+type ParamTemplateUseCase struct {
+	mock.Mock
+}
+
+type ParamTemplateUseCase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ParamTemplateUseCase) EXPECT() *ParamTemplateUseCase_Expecter {
+	return &ParamTemplateUseCase_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, templateDto
+func (_m *ParamTemplateUseCase) Create(ctx context.Context, templateDto *domain.ParamTemplateDto) error {
+	ret := _m.Called(ctx, templateDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ParamTemplateDto) error); ok {
+		r0 = rf(ctx, templateDto)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ParamTemplateUseCase_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type ParamTemplateUseCase_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateDto *domain.ParamTemplateDto
+func (_e *ParamTemplateUseCase_Expecter) Create(ctx interface{}, templateDto interface{}) *ParamTemplateUseCase_Create_Call {
+	return &ParamTemplateUseCase_Create_Call{Call: _e.mock.On("Create", ctx, templateDto)}
+}
+
+func (_c *ParamTemplateUseCase_Create_Call) Run(run func(ctx context.Context, templateDto *domain.ParamTemplateDto)) *ParamTemplateUseCase_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ParamTemplateDto))
+	})
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Create_Call) Return(_a0 error) *ParamTemplateUseCase_Create_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Create_Call) RunAndReturn(run func(context.Context, *domain.ParamTemplateDto) error) *ParamTemplateUseCase_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function with given fields: ctx, name
+func (_m *ParamTemplateUseCase) Delete(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ParamTemplateUseCase_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type ParamTemplateUseCase_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *ParamTemplateUseCase_Expecter) Delete(ctx interface{}, name interface{}) *ParamTemplateUseCase_Delete_Call {
+	return &ParamTemplateUseCase_Delete_Call{Call: _e.mock.On("Delete", ctx, name)}
+}
+
+func (_c *ParamTemplateUseCase_Delete_Call) Run(run func(ctx context.Context, name string)) *ParamTemplateUseCase_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Delete_Call) Return(_a0 error) *ParamTemplateUseCase_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Delete_Call) RunAndReturn(run func(context.Context, string) error) *ParamTemplateUseCase_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, name
+func (_m *ParamTemplateUseCase) Get(ctx context.Context, name string) (*domain.ParamTemplateDto, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *domain.ParamTemplateDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.ParamTemplateDto, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ParamTemplateDto); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ParamTemplateDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParamTemplateUseCase_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type ParamTemplateUseCase_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *ParamTemplateUseCase_Expecter) Get(ctx interface{}, name interface{}) *ParamTemplateUseCase_Get_Call {
+	return &ParamTemplateUseCase_Get_Call{Call: _e.mock.On("Get", ctx, name)}
+}
+
+func (_c *ParamTemplateUseCase_Get_Call) Run(run func(ctx context.Context, name string)) *ParamTemplateUseCase_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Get_Call) Return(_a0 *domain.ParamTemplateDto, _a1 error) *ParamTemplateUseCase_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Get_Call) RunAndReturn(run func(context.Context, string) (*domain.ParamTemplateDto, error)) *ParamTemplateUseCase_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *ParamTemplateUseCase) List(ctx context.Context) ([]*domain.ParamTemplateDto, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.ParamTemplateDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.ParamTemplateDto, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.ParamTemplateDto); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ParamTemplateDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParamTemplateUseCase_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type ParamTemplateUseCase_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *ParamTemplateUseCase_Expecter) List(ctx interface{}) *ParamTemplateUseCase_List_Call {
+	return &ParamTemplateUseCase_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *ParamTemplateUseCase_List_Call) Run(run func(ctx context.Context)) *ParamTemplateUseCase_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_List_Call) Return(_a0 []*domain.ParamTemplateDto, _a1 error) *ParamTemplateUseCase_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_List_Call) RunAndReturn(run func(context.Context) ([]*domain.ParamTemplateDto, error)) *ParamTemplateUseCase_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Render provides a mock function with given fields: ctx, name, data
+func (_m *ParamTemplateUseCase) Render(ctx context.Context, name string, data *domain.ParamTemplateData) (url.Values, error) {
+	ret := _m.Called(ctx, name, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Render")
+	}
+
+	var r0 url.Values
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.ParamTemplateData) (url.Values, error)); ok {
+		return rf(ctx, name, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.ParamTemplateData) url.Values); ok {
+		r0 = rf(ctx, name, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(url.Values)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.ParamTemplateData) error); ok {
+		r1 = rf(ctx, name, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParamTemplateUseCase_Render_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Render'
+type ParamTemplateUseCase_Render_Call struct {
+	*mock.Call
+}
+
+// Render is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - data *domain.ParamTemplateData
+func (_e *ParamTemplateUseCase_Expecter) Render(ctx interface{}, name interface{}, data interface{}) *ParamTemplateUseCase_Render_Call {
+	return &ParamTemplateUseCase_Render_Call{Call: _e.mock.On("Render", ctx, name, data)}
+}
+
+func (_c *ParamTemplateUseCase_Render_Call) Run(run func(ctx context.Context, name string, data *domain.ParamTemplateData)) *ParamTemplateUseCase_Render_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*domain.ParamTemplateData))
+	})
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Render_Call) Return(_a0 url.Values, _a1 error) *ParamTemplateUseCase_Render_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Render_Call) RunAndReturn(run func(context.Context, string, *domain.ParamTemplateData) (url.Values, error)) *ParamTemplateUseCase_Render_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, templateDto
+func (_m *ParamTemplateUseCase) Update(ctx context.Context, templateDto *domain.ParamTemplateDto) error {
+	ret := _m.Called(ctx, templateDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ParamTemplateDto) error); ok {
+		r0 = rf(ctx, templateDto)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ParamTemplateUseCase_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type ParamTemplateUseCase_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateDto *domain.ParamTemplateDto
+func (_e *ParamTemplateUseCase_Expecter) Update(ctx interface{}, templateDto interface{}) *ParamTemplateUseCase_Update_Call {
+	return &ParamTemplateUseCase_Update_Call{Call: _e.mock.On("Update", ctx, templateDto)}
+}
+
+func (_c *ParamTemplateUseCase_Update_Call) Run(run func(ctx context.Context, templateDto *domain.ParamTemplateDto)) *ParamTemplateUseCase_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ParamTemplateDto))
+	})
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Update_Call) Return(_a0 error) *ParamTemplateUseCase_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ParamTemplateUseCase_Update_Call) RunAndReturn(run func(context.Context, *domain.ParamTemplateDto) error) *ParamTemplateUseCase_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewParamTemplateUseCase creates a new instance of ParamTemplateUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewParamTemplateUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ParamTemplateUseCase {
+	mock := &ParamTemplateUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,201 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package usecase
+
+import (
+	context "context"
+
+	domain "github.com/Hao1995/short-url/internal/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReportUseCase is an autogenerated mock type for the ReportUseCase type
+type ReportUseCase struct {
+	mock.Mock
+}
+
+type ReportUseCase_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *ReportUseCase) EXPECT() *ReportUseCase_Expecter {
+	return &ReportUseCase_Expecter{mock: &_m.Mock}
+}
+
+// List provides a mock function with given fields: ctx, listReqDto
+func (_m *ReportUseCase) List(ctx context.Context, listReqDto *domain.ListReportsReqDto) ([]*domain.ReportDto, error) {
+	ret := _m.Called(ctx, listReqDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.ReportDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListReportsReqDto) ([]*domain.ReportDto, error)); ok {
+		return rf(ctx, listReqDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListReportsReqDto) []*domain.ReportDto); ok {
+		r0 = rf(ctx, listReqDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ReportDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ListReportsReqDto) error); ok {
+		r1 = rf(ctx, listReqDto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReportUseCase_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type ReportUseCase_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - listReqDto *domain.ListReportsReqDto
+func (_e *ReportUseCase_Expecter) List(ctx interface{}, listReqDto interface{}) *ReportUseCase_List_Call {
+	return &ReportUseCase_List_Call{Call: _e.mock.On("List", ctx, listReqDto)}
+}
+
+func (_c *ReportUseCase_List_Call) Run(run func(ctx context.Context, listReqDto *domain.ListReportsReqDto)) *ReportUseCase_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ListReportsReqDto))
+	})
+	return _c
+}
+
+func (_c *ReportUseCase_List_Call) Return(_a0 []*domain.ReportDto, _a1 error) *ReportUseCase_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReportUseCase_List_Call) RunAndReturn(run func(context.Context, *domain.ListReportsReqDto) ([]*domain.ReportDto, error)) *ReportUseCase_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Report provides a mock function with given fields: ctx, reportDto
+func (_m *ReportUseCase) Report(ctx context.Context, reportDto *domain.ReportDto) error {
+	ret := _m.Called(ctx, reportDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Report")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ReportDto) error); ok {
+		r0 = rf(ctx, reportDto)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReportUseCase_Report_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Report'
+type ReportUseCase_Report_Call struct {
+	*mock.Call
+}
+
+// Report is a helper method to define mock.On call
+//   - ctx context.Context
+//   - reportDto *domain.ReportDto
+func (_e *ReportUseCase_Expecter) Report(ctx interface{}, reportDto interface{}) *ReportUseCase_Report_Call {
+	return &ReportUseCase_Report_Call{Call: _e.mock.On("Report", ctx, reportDto)}
+}
+
+func (_c *ReportUseCase_Report_Call) Run(run func(ctx context.Context, reportDto *domain.ReportDto)) *ReportUseCase_Report_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ReportDto))
+	})
+	return _c
+}
+
+func (_c *ReportUseCase_Report_Call) Return(_a0 error) *ReportUseCase_Report_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *ReportUseCase_Report_Call) RunAndReturn(run func(context.Context, *domain.ReportDto) error) *ReportUseCase_Report_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Resolve provides a mock function with given fields: ctx, id, action
+func (_m *ReportUseCase) Resolve(ctx context.Context, id string, action domain.ReportAction) (domain.ReportStatus, error) {
+	ret := _m.Called(ctx, id, action)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 domain.ReportStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ReportAction) (domain.ReportStatus, error)); ok {
+		return rf(ctx, id, action)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ReportAction) domain.ReportStatus); ok {
+		r0 = rf(ctx, id, action)
+	} else {
+		r0 = ret.Get(0).(domain.ReportStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ReportAction) error); ok {
+		r1 = rf(ctx, id, action)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReportUseCase_Resolve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Resolve'
+type ReportUseCase_Resolve_Call struct {
+	*mock.Call
+}
+
+// Resolve is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - action domain.ReportAction
+func (_e *ReportUseCase_Expecter) Resolve(ctx interface{}, id interface{}, action interface{}) *ReportUseCase_Resolve_Call {
+	return &ReportUseCase_Resolve_Call{Call: _e.mock.On("Resolve", ctx, id, action)}
+}
+
+func (_c *ReportUseCase_Resolve_Call) Run(run func(ctx context.Context, id string, action domain.ReportAction)) *ReportUseCase_Resolve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.ReportAction))
+	})
+	return _c
+}
+
+func (_c *ReportUseCase_Resolve_Call) Return(_a0 domain.ReportStatus, _a1 error) *ReportUseCase_Resolve_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *ReportUseCase_Resolve_Call) RunAndReturn(run func(context.Context, string, domain.ReportAction) (domain.ReportStatus, error)) *ReportUseCase_Resolve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewReportUseCase creates a new instance of ReportUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReportUseCase(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReportUseCase {
+	mock := &ReportUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -22,6 +22,113 @@ func (_m *Repository) EXPECT() *Repository_Expecter {
 	return &Repository_Expecter{mock: &_m.Mock}
 }
 
+// BanCreator provides a mock function with given fields: ctx, creator, reason
+func (_m *Repository) BanCreator(ctx context.Context, creator string, reason string) error {
+	ret := _m.Called(ctx, creator, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BanCreator")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, creator, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_BanCreator_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BanCreator'
+type Repository_BanCreator_Call struct {
+	*mock.Call
+}
+
+// BanCreator is a helper method to define mock.On call
+//   - ctx context.Context
+//   - creator string
+//   - reason string
+func (_e *Repository_Expecter) BanCreator(ctx interface{}, creator interface{}, reason interface{}) *Repository_BanCreator_Call {
+	return &Repository_BanCreator_Call{Call: _e.mock.On("BanCreator", ctx, creator, reason)}
+}
+
+func (_c *Repository_BanCreator_Call) Run(run func(ctx context.Context, creator string, reason string)) *Repository_BanCreator_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_BanCreator_Call) Return(_a0 error) *Repository_BanCreator_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_BanCreator_Call) RunAndReturn(run func(context.Context, string, string) error) *Repository_BanCreator_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountClicksByVariant provides a mock function with given fields: ctx, id
+func (_m *Repository) CountClicksByVariant(ctx context.Context, id string) (map[int]int64, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountClicksByVariant")
+	}
+
+	var r0 map[int]int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (map[int]int64, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) map[int]int64); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_CountClicksByVariant_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountClicksByVariant'
+type Repository_CountClicksByVariant_Call struct {
+	*mock.Call
+}
+
+// CountClicksByVariant is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Repository_Expecter) CountClicksByVariant(ctx interface{}, id interface{}) *Repository_CountClicksByVariant_Call {
+	return &Repository_CountClicksByVariant_Call{Call: _e.mock.On("CountClicksByVariant", ctx, id)}
+}
+
+func (_c *Repository_CountClicksByVariant_Call) Run(run func(ctx context.Context, id string)) *Repository_CountClicksByVariant_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_CountClicksByVariant_Call) Return(_a0 map[int]int64, _a1 error) *Repository_CountClicksByVariant_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_CountClicksByVariant_Call) RunAndReturn(run func(context.Context, string) (map[int]int64, error)) *Repository_CountClicksByVariant_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, CreateReqDto
 func (_m *Repository) Create(ctx context.Context, CreateReqDto *domain.CreateReqDto) (string, error) {
 	ret := _m.Called(ctx, CreateReqDto)
@@ -79,6 +186,253 @@ func (_c *Repository_Create_Call) RunAndReturn(run func(context.Context, *domain
 	return _c
 }
 
+// CreateClicks provides a mock function with given fields: ctx, clickDtos
+func (_m *Repository) CreateClicks(ctx context.Context, clickDtos []*domain.ClickDto) error {
+	ret := _m.Called(ctx, clickDtos)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateClicks")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*domain.ClickDto) error); ok {
+		r0 = rf(ctx, clickDtos)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_CreateClicks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateClicks'
+type Repository_CreateClicks_Call struct {
+	*mock.Call
+}
+
+// CreateClicks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clickDtos []*domain.ClickDto
+func (_e *Repository_Expecter) CreateClicks(ctx interface{}, clickDtos interface{}) *Repository_CreateClicks_Call {
+	return &Repository_CreateClicks_Call{Call: _e.mock.On("CreateClicks", ctx, clickDtos)}
+}
+
+func (_c *Repository_CreateClicks_Call) Run(run func(ctx context.Context, clickDtos []*domain.ClickDto)) *Repository_CreateClicks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*domain.ClickDto))
+	})
+	return _c
+}
+
+func (_c *Repository_CreateClicks_Call) Return(_a0 error) *Repository_CreateClicks_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_CreateClicks_Call) RunAndReturn(run func(context.Context, []*domain.ClickDto) error) *Repository_CreateClicks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateParamTemplate provides a mock function with given fields: ctx, templateDto
+func (_m *Repository) CreateParamTemplate(ctx context.Context, templateDto *domain.ParamTemplateDto) error {
+	ret := _m.Called(ctx, templateDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateParamTemplate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ParamTemplateDto) error); ok {
+		r0 = rf(ctx, templateDto)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_CreateParamTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateParamTemplate'
+type Repository_CreateParamTemplate_Call struct {
+	*mock.Call
+}
+
+// CreateParamTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateDto *domain.ParamTemplateDto
+func (_e *Repository_Expecter) CreateParamTemplate(ctx interface{}, templateDto interface{}) *Repository_CreateParamTemplate_Call {
+	return &Repository_CreateParamTemplate_Call{Call: _e.mock.On("CreateParamTemplate", ctx, templateDto)}
+}
+
+func (_c *Repository_CreateParamTemplate_Call) Run(run func(ctx context.Context, templateDto *domain.ParamTemplateDto)) *Repository_CreateParamTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ParamTemplateDto))
+	})
+	return _c
+}
+
+func (_c *Repository_CreateParamTemplate_Call) Return(_a0 error) *Repository_CreateParamTemplate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_CreateParamTemplate_Call) RunAndReturn(run func(context.Context, *domain.ParamTemplateDto) error) *Repository_CreateParamTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateReport provides a mock function with given fields: ctx, reportDto
+func (_m *Repository) CreateReport(ctx context.Context, reportDto *domain.ReportDto) error {
+	ret := _m.Called(ctx, reportDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReport")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ReportDto) error); ok {
+		r0 = rf(ctx, reportDto)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_CreateReport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateReport'
+type Repository_CreateReport_Call struct {
+	*mock.Call
+}
+
+// CreateReport is a helper method to define mock.On call
+//   - ctx context.Context
+//   - reportDto *domain.ReportDto
+func (_e *Repository_Expecter) CreateReport(ctx interface{}, reportDto interface{}) *Repository_CreateReport_Call {
+	return &Repository_CreateReport_Call{Call: _e.mock.On("CreateReport", ctx, reportDto)}
+}
+
+func (_c *Repository_CreateReport_Call) Run(run func(ctx context.Context, reportDto *domain.ReportDto)) *Repository_CreateReport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ReportDto))
+	})
+	return _c
+}
+
+func (_c *Repository_CreateReport_Call) Return(_a0 error) *Repository_CreateReport_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_CreateReport_Call) RunAndReturn(run func(context.Context, *domain.ReportDto) error) *Repository_CreateReport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteParamTemplate provides a mock function with given fields: ctx, name
+func (_m *Repository) DeleteParamTemplate(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteParamTemplate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_DeleteParamTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteParamTemplate'
+type Repository_DeleteParamTemplate_Call struct {
+	*mock.Call
+}
+
+// DeleteParamTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *Repository_Expecter) DeleteParamTemplate(ctx interface{}, name interface{}) *Repository_DeleteParamTemplate_Call {
+	return &Repository_DeleteParamTemplate_Call{Call: _e.mock.On("DeleteParamTemplate", ctx, name)}
+}
+
+func (_c *Repository_DeleteParamTemplate_Call) Run(run func(ctx context.Context, name string)) *Repository_DeleteParamTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_DeleteParamTemplate_Call) Return(_a0 error) *Repository_DeleteParamTemplate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_DeleteParamTemplate_Call) RunAndReturn(run func(context.Context, string) error) *Repository_DeleteParamTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Find provides a mock function with given fields: ctx, id
+func (_m *Repository) Find(ctx context.Context, id string) (*domain.ShortUrlDto, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 *domain.ShortUrlDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.ShortUrlDto, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ShortUrlDto); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ShortUrlDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_Find_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Find'
+type Repository_Find_Call struct {
+	*mock.Call
+}
+
+// Find is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *Repository_Expecter) Find(ctx interface{}, id interface{}) *Repository_Find_Call {
+	return &Repository_Find_Call{Call: _e.mock.On("Find", ctx, id)}
+}
+
+func (_c *Repository_Find_Call) Run(run func(ctx context.Context, id string)) *Repository_Find_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_Find_Call) Return(_a0 *domain.ShortUrlDto, _a1 error) *Repository_Find_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_Find_Call) RunAndReturn(run func(context.Context, string) (*domain.ShortUrlDto, error)) *Repository_Find_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function with given fields: ctx, id
 func (_m *Repository) Get(ctx context.Context, id string) (*domain.GetRespDto, error) {
 	ret := _m.Called(ctx, id)
@@ -138,6 +492,570 @@ func (_c *Repository_Get_Call) RunAndReturn(run func(context.Context, string) (*
 	return _c
 }
 
+// GetParamTemplate provides a mock function with given fields: ctx, name
+func (_m *Repository) GetParamTemplate(ctx context.Context, name string) (*domain.ParamTemplateDto, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetParamTemplate")
+	}
+
+	var r0 *domain.ParamTemplateDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.ParamTemplateDto, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.ParamTemplateDto); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.ParamTemplateDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_GetParamTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetParamTemplate'
+type Repository_GetParamTemplate_Call struct {
+	*mock.Call
+}
+
+// GetParamTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+func (_e *Repository_Expecter) GetParamTemplate(ctx interface{}, name interface{}) *Repository_GetParamTemplate_Call {
+	return &Repository_GetParamTemplate_Call{Call: _e.mock.On("GetParamTemplate", ctx, name)}
+}
+
+func (_c *Repository_GetParamTemplate_Call) Run(run func(ctx context.Context, name string)) *Repository_GetParamTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_GetParamTemplate_Call) Return(_a0 *domain.ParamTemplateDto, _a1 error) *Repository_GetParamTemplate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_GetParamTemplate_Call) RunAndReturn(run func(context.Context, string) (*domain.ParamTemplateDto, error)) *Repository_GetParamTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsCreatorBanned provides a mock function with given fields: ctx, creator
+func (_m *Repository) IsCreatorBanned(ctx context.Context, creator string) (bool, error) {
+	ret := _m.Called(ctx, creator)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsCreatorBanned")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(ctx, creator)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(ctx, creator)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, creator)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_IsCreatorBanned_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsCreatorBanned'
+type Repository_IsCreatorBanned_Call struct {
+	*mock.Call
+}
+
+// IsCreatorBanned is a helper method to define mock.On call
+//   - ctx context.Context
+//   - creator string
+func (_e *Repository_Expecter) IsCreatorBanned(ctx interface{}, creator interface{}) *Repository_IsCreatorBanned_Call {
+	return &Repository_IsCreatorBanned_Call{Call: _e.mock.On("IsCreatorBanned", ctx, creator)}
+}
+
+func (_c *Repository_IsCreatorBanned_Call) Run(run func(ctx context.Context, creator string)) *Repository_IsCreatorBanned_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *Repository_IsCreatorBanned_Call) Return(_a0 bool, _a1 error) *Repository_IsCreatorBanned_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_IsCreatorBanned_Call) RunAndReturn(run func(context.Context, string) (bool, error)) *Repository_IsCreatorBanned_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, listReqDto
+func (_m *Repository) List(ctx context.Context, listReqDto *domain.ListReqDto) ([]*domain.ShortUrlDto, error) {
+	ret := _m.Called(ctx, listReqDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*domain.ShortUrlDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListReqDto) ([]*domain.ShortUrlDto, error)); ok {
+		return rf(ctx, listReqDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListReqDto) []*domain.ShortUrlDto); ok {
+		r0 = rf(ctx, listReqDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ShortUrlDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ListReqDto) error); ok {
+		r1 = rf(ctx, listReqDto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type Repository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - listReqDto *domain.ListReqDto
+func (_e *Repository_Expecter) List(ctx interface{}, listReqDto interface{}) *Repository_List_Call {
+	return &Repository_List_Call{Call: _e.mock.On("List", ctx, listReqDto)}
+}
+
+func (_c *Repository_List_Call) Run(run func(ctx context.Context, listReqDto *domain.ListReqDto)) *Repository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ListReqDto))
+	})
+	return _c
+}
+
+func (_c *Repository_List_Call) Return(_a0 []*domain.ShortUrlDto, _a1 error) *Repository_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_List_Call) RunAndReturn(run func(context.Context, *domain.ListReqDto) ([]*domain.ShortUrlDto, error)) *Repository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByStatus provides a mock function with given fields: ctx, listReqDto
+func (_m *Repository) ListByStatus(ctx context.Context, listReqDto *domain.ListByStatusReqDto) ([]*domain.ShortUrlDto, error) {
+	ret := _m.Called(ctx, listReqDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByStatus")
+	}
+
+	var r0 []*domain.ShortUrlDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListByStatusReqDto) ([]*domain.ShortUrlDto, error)); ok {
+		return rf(ctx, listReqDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListByStatusReqDto) []*domain.ShortUrlDto); ok {
+		r0 = rf(ctx, listReqDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ShortUrlDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ListByStatusReqDto) error); ok {
+		r1 = rf(ctx, listReqDto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_ListByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByStatus'
+type Repository_ListByStatus_Call struct {
+	*mock.Call
+}
+
+// ListByStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - listReqDto *domain.ListByStatusReqDto
+func (_e *Repository_Expecter) ListByStatus(ctx interface{}, listReqDto interface{}) *Repository_ListByStatus_Call {
+	return &Repository_ListByStatus_Call{Call: _e.mock.On("ListByStatus", ctx, listReqDto)}
+}
+
+func (_c *Repository_ListByStatus_Call) Run(run func(ctx context.Context, listReqDto *domain.ListByStatusReqDto)) *Repository_ListByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ListByStatusReqDto))
+	})
+	return _c
+}
+
+func (_c *Repository_ListByStatus_Call) Return(_a0 []*domain.ShortUrlDto, _a1 error) *Repository_ListByStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_ListByStatus_Call) RunAndReturn(run func(context.Context, *domain.ListByStatusReqDto) ([]*domain.ShortUrlDto, error)) *Repository_ListByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListClicks provides a mock function with given fields: ctx, id, limit
+func (_m *Repository) ListClicks(ctx context.Context, id string, limit int) ([]*domain.ClickDto, error) {
+	ret := _m.Called(ctx, id, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListClicks")
+	}
+
+	var r0 []*domain.ClickDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]*domain.ClickDto, error)); ok {
+		return rf(ctx, id, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []*domain.ClickDto); ok {
+		r0 = rf(ctx, id, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ClickDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, id, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_ListClicks_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListClicks'
+type Repository_ListClicks_Call struct {
+	*mock.Call
+}
+
+// ListClicks is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - limit int
+func (_e *Repository_Expecter) ListClicks(ctx interface{}, id interface{}, limit interface{}) *Repository_ListClicks_Call {
+	return &Repository_ListClicks_Call{Call: _e.mock.On("ListClicks", ctx, id, limit)}
+}
+
+func (_c *Repository_ListClicks_Call) Run(run func(ctx context.Context, id string, limit int)) *Repository_ListClicks_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *Repository_ListClicks_Call) Return(_a0 []*domain.ClickDto, _a1 error) *Repository_ListClicks_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_ListClicks_Call) RunAndReturn(run func(context.Context, string, int) ([]*domain.ClickDto, error)) *Repository_ListClicks_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListParamTemplates provides a mock function with given fields: ctx
+func (_m *Repository) ListParamTemplates(ctx context.Context) ([]*domain.ParamTemplateDto, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListParamTemplates")
+	}
+
+	var r0 []*domain.ParamTemplateDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*domain.ParamTemplateDto, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*domain.ParamTemplateDto); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ParamTemplateDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_ListParamTemplates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListParamTemplates'
+type Repository_ListParamTemplates_Call struct {
+	*mock.Call
+}
+
+// ListParamTemplates is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *Repository_Expecter) ListParamTemplates(ctx interface{}) *Repository_ListParamTemplates_Call {
+	return &Repository_ListParamTemplates_Call{Call: _e.mock.On("ListParamTemplates", ctx)}
+}
+
+func (_c *Repository_ListParamTemplates_Call) Run(run func(ctx context.Context)) *Repository_ListParamTemplates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *Repository_ListParamTemplates_Call) Return(_a0 []*domain.ParamTemplateDto, _a1 error) *Repository_ListParamTemplates_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_ListParamTemplates_Call) RunAndReturn(run func(context.Context) ([]*domain.ParamTemplateDto, error)) *Repository_ListParamTemplates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListReports provides a mock function with given fields: ctx, listReqDto
+func (_m *Repository) ListReports(ctx context.Context, listReqDto *domain.ListReportsReqDto) ([]*domain.ReportDto, error) {
+	ret := _m.Called(ctx, listReqDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListReports")
+	}
+
+	var r0 []*domain.ReportDto
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListReportsReqDto) ([]*domain.ReportDto, error)); ok {
+		return rf(ctx, listReqDto)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ListReportsReqDto) []*domain.ReportDto); ok {
+		r0 = rf(ctx, listReqDto)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*domain.ReportDto)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.ListReportsReqDto) error); ok {
+		r1 = rf(ctx, listReqDto)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_ListReports_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListReports'
+type Repository_ListReports_Call struct {
+	*mock.Call
+}
+
+// ListReports is a helper method to define mock.On call
+//   - ctx context.Context
+//   - listReqDto *domain.ListReportsReqDto
+func (_e *Repository_Expecter) ListReports(ctx interface{}, listReqDto interface{}) *Repository_ListReports_Call {
+	return &Repository_ListReports_Call{Call: _e.mock.On("ListReports", ctx, listReqDto)}
+}
+
+func (_c *Repository_ListReports_Call) Run(run func(ctx context.Context, listReqDto *domain.ListReportsReqDto)) *Repository_ListReports_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ListReportsReqDto))
+	})
+	return _c
+}
+
+func (_c *Repository_ListReports_Call) Return(_a0 []*domain.ReportDto, _a1 error) *Repository_ListReports_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_ListReports_Call) RunAndReturn(run func(context.Context, *domain.ListReportsReqDto) ([]*domain.ReportDto, error)) *Repository_ListReports_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResolveReports provides a mock function with given fields: ctx, id, status
+func (_m *Repository) ResolveReports(ctx context.Context, id string, status domain.ReportStatus) (int, error) {
+	ret := _m.Called(ctx, id, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResolveReports")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ReportStatus) (int, error)); ok {
+		return rf(ctx, id, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ReportStatus) int); ok {
+		r0 = rf(ctx, id, status)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.ReportStatus) error); ok {
+		r1 = rf(ctx, id, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Repository_ResolveReports_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResolveReports'
+type Repository_ResolveReports_Call struct {
+	*mock.Call
+}
+
+// ResolveReports is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - status domain.ReportStatus
+func (_e *Repository_Expecter) ResolveReports(ctx interface{}, id interface{}, status interface{}) *Repository_ResolveReports_Call {
+	return &Repository_ResolveReports_Call{Call: _e.mock.On("ResolveReports", ctx, id, status)}
+}
+
+func (_c *Repository_ResolveReports_Call) Run(run func(ctx context.Context, id string, status domain.ReportStatus)) *Repository_ResolveReports_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.ReportStatus))
+	})
+	return _c
+}
+
+func (_c *Repository_ResolveReports_Call) Return(_a0 int, _a1 error) *Repository_ResolveReports_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *Repository_ResolveReports_Call) RunAndReturn(run func(context.Context, string, domain.ReportStatus) (int, error)) *Repository_ResolveReports_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, id, updateReqDto
+func (_m *Repository) Update(ctx context.Context, id string, updateReqDto *domain.UpdateReqDto) error {
+	ret := _m.Called(ctx, id, updateReqDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.UpdateReqDto) error); ok {
+		r0 = rf(ctx, id, updateReqDto)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type Repository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - updateReqDto *domain.UpdateReqDto
+func (_e *Repository_Expecter) Update(ctx interface{}, id interface{}, updateReqDto interface{}) *Repository_Update_Call {
+	return &Repository_Update_Call{Call: _e.mock.On("Update", ctx, id, updateReqDto)}
+}
+
+func (_c *Repository_Update_Call) Run(run func(ctx context.Context, id string, updateReqDto *domain.UpdateReqDto)) *Repository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(*domain.UpdateReqDto))
+	})
+	return _c
+}
+
+func (_c *Repository_Update_Call) Return(_a0 error) *Repository_Update_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_Update_Call) RunAndReturn(run func(context.Context, string, *domain.UpdateReqDto) error) *Repository_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateParamTemplate provides a mock function with given fields: ctx, templateDto
+func (_m *Repository) UpdateParamTemplate(ctx context.Context, templateDto *domain.ParamTemplateDto) error {
+	ret := _m.Called(ctx, templateDto)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateParamTemplate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.ParamTemplateDto) error); ok {
+		r0 = rf(ctx, templateDto)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Repository_UpdateParamTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateParamTemplate'
+type Repository_UpdateParamTemplate_Call struct {
+	*mock.Call
+}
+
+// UpdateParamTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - templateDto *domain.ParamTemplateDto
+func (_e *Repository_Expecter) UpdateParamTemplate(ctx interface{}, templateDto interface{}) *Repository_UpdateParamTemplate_Call {
+	return &Repository_UpdateParamTemplate_Call{Call: _e.mock.On("UpdateParamTemplate", ctx, templateDto)}
+}
+
+func (_c *Repository_UpdateParamTemplate_Call) Run(run func(ctx context.Context, templateDto *domain.ParamTemplateDto)) *Repository_UpdateParamTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ParamTemplateDto))
+	})
+	return _c
+}
+
+func (_c *Repository_UpdateParamTemplate_Call) Return(_a0 error) *Repository_UpdateParamTemplate_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Repository_UpdateParamTemplate_Call) RunAndReturn(run func(context.Context, *domain.ParamTemplateDto) error) *Repository_UpdateParamTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {
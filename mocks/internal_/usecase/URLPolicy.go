@@ -0,0 +1,78 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package usecase
+
+import mock "github.com/stretchr/testify/mock"
+
+// URLPolicy is an autogenerated mock type for the URLPolicy type
+type URLPolicy struct {
+	mock.Mock
+}
+
+type URLPolicy_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLPolicy) EXPECT() *URLPolicy_Expecter {
+	return &URLPolicy_Expecter{mock: &_m.Mock}
+}
+
+// Check provides a mock function with given fields: rawURL
+func (_m *URLPolicy) Check(rawURL string) error {
+	ret := _m.Called(rawURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(rawURL)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// URLPolicy_Check_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Check'
+type URLPolicy_Check_Call struct {
+	*mock.Call
+}
+
+// Check is a helper method to define mock.On call
+//   - rawURL string
+func (_e *URLPolicy_Expecter) Check(rawURL interface{}) *URLPolicy_Check_Call {
+	return &URLPolicy_Check_Call{Call: _e.mock.On("Check", rawURL)}
+}
+
+func (_c *URLPolicy_Check_Call) Run(run func(rawURL string)) *URLPolicy_Check_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *URLPolicy_Check_Call) Return(_a0 error) *URLPolicy_Check_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *URLPolicy_Check_Call) RunAndReturn(run func(string) error) *URLPolicy_Check_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLPolicy creates a new instance of URLPolicy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLPolicy(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLPolicy {
+	mock := &URLPolicy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
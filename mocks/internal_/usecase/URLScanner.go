@@ -0,0 +1,93 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package usecase
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// URLScanner is an autogenerated mock type for the URLScanner type
+type URLScanner struct {
+	mock.Mock
+}
+
+type URLScanner_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *URLScanner) EXPECT() *URLScanner_Expecter {
+	return &URLScanner_Expecter{mock: &_m.Mock}
+}
+
+// Scan provides a mock function with given fields: ctx, rawURL
+func (_m *URLScanner) Scan(ctx context.Context, rawURL string) (string, error) {
+	ret := _m.Called(ctx, rawURL)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Scan")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, rawURL)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, rawURL)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, rawURL)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// URLScanner_Scan_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Scan'
+type URLScanner_Scan_Call struct {
+	*mock.Call
+}
+
+// Scan is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rawURL string
+func (_e *URLScanner_Expecter) Scan(ctx interface{}, rawURL interface{}) *URLScanner_Scan_Call {
+	return &URLScanner_Scan_Call{Call: _e.mock.On("Scan", ctx, rawURL)}
+}
+
+func (_c *URLScanner_Scan_Call) Run(run func(ctx context.Context, rawURL string)) *URLScanner_Scan_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *URLScanner_Scan_Call) Return(threat string, err error) *URLScanner_Scan_Call {
+	_c.Call.Return(threat, err)
+	return _c
+}
+
+func (_c *URLScanner_Scan_Call) RunAndReturn(run func(context.Context, string) (string, error)) *URLScanner_Scan_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewURLScanner creates a new instance of URLScanner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewURLScanner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *URLScanner {
+	mock := &URLScanner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
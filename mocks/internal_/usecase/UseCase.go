@@ -22,6 +22,40 @@ func (_m *UseCase) EXPECT() *UseCase_Expecter {
 	return &UseCase_Expecter{mock: &_m.Mock}
 }
 
+// Click provides a mock function with given fields: ctx, clickDto
+func (_m *UseCase) Click(ctx context.Context, clickDto *domain.ClickDto) {
+	_m.Called(ctx, clickDto)
+}
+
+// UseCase_Click_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Click'
+type UseCase_Click_Call struct {
+	*mock.Call
+}
+
+// Click is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clickDto *domain.ClickDto
+func (_e *UseCase_Expecter) Click(ctx interface{}, clickDto interface{}) *UseCase_Click_Call {
+	return &UseCase_Click_Call{Call: _e.mock.On("Click", ctx, clickDto)}
+}
+
+func (_c *UseCase_Click_Call) Run(run func(ctx context.Context, clickDto *domain.ClickDto)) *UseCase_Click_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.ClickDto))
+	})
+	return _c
+}
+
+func (_c *UseCase_Click_Call) Return() *UseCase_Click_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *UseCase_Click_Call) RunAndReturn(run func(context.Context, *domain.ClickDto)) *UseCase_Click_Call {
+	_c.Run(run)
+	return _c
+}
+
 // Create provides a mock function with given fields: ctx, CreateReqDto
 func (_m *UseCase) Create(ctx context.Context, CreateReqDto *domain.CreateReqDto) (*domain.CreateRespDto, error) {
 	ret := _m.Called(ctx, CreateReqDto)
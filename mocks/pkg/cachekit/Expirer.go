@@ -0,0 +1,81 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package cachekit
+
+import (
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Expirer is an autogenerated mock type for the Expirer type
+type Expirer struct {
+	mock.Mock
+}
+
+type Expirer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Expirer) EXPECT() *Expirer_Expecter {
+	return &Expirer_Expecter{mock: &_m.Mock}
+}
+
+// CacheExpiry provides a mock function with no fields
+func (_m *Expirer) CacheExpiry() time.Time {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CacheExpiry")
+	}
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func() time.Time); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// Expirer_CacheExpiry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CacheExpiry'
+type Expirer_CacheExpiry_Call struct {
+	*mock.Call
+}
+
+// CacheExpiry is a helper method to define mock.On call
+func (_e *Expirer_Expecter) CacheExpiry() *Expirer_CacheExpiry_Call {
+	return &Expirer_CacheExpiry_Call{Call: _e.mock.On("CacheExpiry")}
+}
+
+func (_c *Expirer_CacheExpiry_Call) Run(run func()) *Expirer_CacheExpiry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *Expirer_CacheExpiry_Call) Return(_a0 time.Time) *Expirer_CacheExpiry_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Expirer_CacheExpiry_Call) RunAndReturn(run func() time.Time) *Expirer_CacheExpiry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewExpirer creates a new instance of Expirer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExpirer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Expirer {
+	mock := &Expirer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.52.1. DO NOT EDIT.
+
+package lifecyclekit
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// StepFunc is an autogenerated mock type for the StepFunc type
+type StepFunc struct {
+	mock.Mock
+}
+
+type StepFunc_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *StepFunc) EXPECT() *StepFunc_Expecter {
+	return &StepFunc_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: ctx
+func (_m *StepFunc) Execute(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StepFunc_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type StepFunc_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *StepFunc_Expecter) Execute(ctx interface{}) *StepFunc_Execute_Call {
+	return &StepFunc_Execute_Call{Call: _e.mock.On("Execute", ctx)}
+}
+
+func (_c *StepFunc_Execute_Call) Run(run func(ctx context.Context)) *StepFunc_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *StepFunc_Execute_Call) Return(_a0 error) *StepFunc_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *StepFunc_Execute_Call) RunAndReturn(run func(context.Context) error) *StepFunc_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewStepFunc creates a new instance of StepFunc. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewStepFunc(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *StepFunc {
+	mock := &StepFunc{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,97 @@
+package cachekit
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+
+	"github.com/Hao1995/short-url/pkg/logkit"
+	"github.com/Hao1995/short-url/pkg/migrationkit/randkit"
+
+	"github.com/viney-shih/go-cache"
+)
+
+// Broadcaster carries the keys deleted on an instance to the other ones over the pubsub, e.g. cache.NewRedis,
+// which evict them from their local tiers. Otherwise the local tiers of the other instances keep serving
+// the deleted values until their TTLs.
+type Broadcaster struct {
+	pubsub cache.Pubsub
+	topic  string
+	// id tells the messages of the instance itself, whose local tiers are evicted on deleting
+	id string
+
+	mu     sync.RWMutex
+	locals []cache.Adapter
+}
+
+// eviction is the message of the keys deleted by an instance
+type eviction struct {
+	From string   `json:"from"`
+	Keys []string `json:"keys"`
+}
+
+// NewBroadcaster generates the broadcaster over the pubsub, which is dedicated to it since the ones of go-cache subscribe once.
+// All the instances must use the same topic.
+func NewBroadcaster(pubsub cache.Pubsub, topic string) *Broadcaster {
+	return &Broadcaster{
+		pubsub: pubsub,
+		topic:  topic,
+		id:     randkit.String(16),
+	}
+}
+
+// Register broadcasts the keys deleted from the cache, and evicts the keys deleted on the other instances from its local tier
+func (b *Broadcaster) Register(c *Cache) {
+	c.mu.Lock()
+	c.broadcaster = b
+	c.mu.Unlock()
+
+	if c.local == nil {
+		return
+	}
+	b.mu.Lock()
+	b.locals = append(b.locals, c.local)
+	b.mu.Unlock()
+}
+
+// Run evicts the keys deleted on the other instances until the context is done
+func (b *Broadcaster) Run(ctx context.Context) {
+	msgs := b.pubsub.Sub(ctx, b.topic)
+	stop := context.AfterFunc(ctx, b.pubsub.Close)
+	defer stop()
+
+	// the channel is closed after the pubsub is closed
+	for msg := range msgs {
+		var e eviction
+		if err := json.Unmarshal(msg.Content(), &e); err != nil {
+			slog.WarnContext(ctx, "Broadcaster.Run. Skip the invalid eviction", logkit.Err(err))
+			continue
+		}
+		if e.From == b.id {
+			continue
+		}
+		b.evict(ctx, e.Keys)
+	}
+}
+
+// publish tells the other instances to evict the keys
+func (b *Broadcaster) publish(ctx context.Context, keys []string) error {
+	msg, err := json.Marshal(eviction{From: b.id, Keys: keys})
+	if err != nil {
+		return err
+	}
+	return b.pubsub.Pub(ctx, b.topic, msg)
+}
+
+func (b *Broadcaster) evict(ctx context.Context, keys []string) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	// the keys carry the prefixes, so the ones of the other caches are missing in the local tier
+	for _, local := range b.locals {
+		if err := local.Del(ctx, keys...); err != nil {
+			slog.WarnContext(ctx, "Broadcaster.Run. Failed to evict the keys from the local tier", logkit.Err(err))
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package cachekit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/viney-shih/go-cache"
+)
+
+type message struct {
+	topic   string
+	content []byte
+}
+
+func (m *message) Topic() string {
+	return m.topic
+}
+
+func (m *message) Content() []byte {
+	return m.content
+}
+
+// hub delivers the published messages to all the subscribed pubsubs, like a Redis channel
+type hub struct {
+	mu   sync.Mutex
+	subs []chan cache.Message
+}
+
+func (h *hub) Pub(ctx context.Context, topic string, content []byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sub := range h.subs {
+		sub <- &message{topic: topic, content: content}
+	}
+	return nil
+}
+
+func (h *hub) pubsub() *pubsub {
+	ps := &pubsub{hub: h, ch: make(chan cache.Message, 10)}
+	h.mu.Lock()
+	h.subs = append(h.subs, ps.ch)
+	h.mu.Unlock()
+	return ps
+}
+
+type pubsub struct {
+	*hub
+	ch        chan cache.Message
+	closeOnce sync.Once
+}
+
+func (p *pubsub) Sub(ctx context.Context, topic ...string) <-chan cache.Message {
+	return p.ch
+}
+
+func (p *pubsub) Close() {
+	p.closeOnce.Do(func() {
+		p.hub.mu.Lock()
+		defer p.hub.mu.Unlock()
+
+		for i, sub := range p.hub.subs {
+			if sub == p.ch {
+				p.hub.subs = append(p.hub.subs[:i], p.hub.subs[i+1:]...)
+				break
+			}
+		}
+		close(p.ch)
+	})
+}
+
+type BroadcasterTestSuite struct {
+	suite.Suite
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	shared *adapter
+	// the local tiers of the two instances
+	local1 *adapter
+	local2 *adapter
+	impl1  *Cache
+	impl2  *Cache
+}
+
+func TestBroadcasterTestSuite(t *testing.T) {
+	suite.Run(t, new(BroadcasterTestSuite))
+}
+
+func (s *BroadcasterTestSuite) SetupTest() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.shared = newAdapter()
+	s.local1 = newAdapter()
+	s.local2 = newAdapter()
+
+	settings := []Setting{{Prefix: "pfx", SharedTTL: time.Hour, LocalTTL: 10 * time.Minute}}
+	s.impl1 = New(s.shared, s.local1, settings, Hooks{})
+	s.impl2 = New(s.shared, s.local2, settings, Hooks{})
+
+	h := &hub{}
+	for _, impl := range []*Cache{s.impl1, s.impl2} {
+		b := NewBroadcaster(h.pubsub(), "evict")
+		b.Register(impl)
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			b.Run(s.ctx)
+		}()
+	}
+}
+
+func (s *BroadcasterTestSuite) TearDownTest() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *BroadcasterTestSuite) TestDel() {
+	s.NoError(s.impl1.Set(s.ctx, "pfx", "key", &value{Name: "whatever"}))
+	// the other instance caches the value in its local tier
+	s.NoError(s.impl2.Get(s.ctx, "pfx", "key", &value{}))
+	s.Contains(s.local2.vals, "ca:pfx:key")
+
+	s.NoError(s.impl1.Del(s.ctx, "pfx", "key"))
+	s.Eventually(func() bool {
+		obj := &value{}
+		return s.impl2.Get(s.ctx, "pfx", "key", obj) == ErrCacheMiss
+	}, time.Second, 10*time.Millisecond)
+}
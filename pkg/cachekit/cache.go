@@ -0,0 +1,389 @@
+// Package cachekit is a two-tier (local and shared) cache over the adapters of go-cache.
+// Unlike go-cache, which applies the same TTL to all the keys of a prefix, the TTL of each key
+// is capped by the expiry of its own value.
+package cachekit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hao1995/short-url/pkg/tracekit"
+
+	"github.com/viney-shih/go-cache"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/singleflight"
+)
+
+// packageKey keeps the keys compatible with the ones written by go-cache
+const packageKey = "ca"
+
+// ExpiredTTL caps the TTLs of the values already expired, which are kept briefly
+// so that the hot ones don't reach the loader on every lookup
+const ExpiredTTL = 5 * time.Second
+
+var (
+	// ErrCacheMiss indicates the key is missing
+	ErrCacheMiss = cache.ErrCacheMiss
+	// ErrPfxNotRegistered means the prefix is not registered
+	ErrPfxNotRegistered = cache.ErrPfxNotRegistered
+
+	now = func() time.Time {
+		return time.Now()
+	}
+
+	tracer = otel.Tracer("github.com/Hao1995/short-url/pkg/cachekit")
+)
+
+// the attributes of the spans
+const (
+	attrPrefix            = attribute.Key("cache.prefix")
+	attrHit               = attribute.Key("cache.hit")
+	attrSingleflightShare = attribute.Key("cache.singleflight.shared")
+)
+
+// Setting provides the TTLs of a group of keys with the same prefix.
+// A tier is skipped if its TTL is not positive.
+type Setting struct {
+	Prefix    string
+	LocalTTL  time.Duration
+	SharedTTL time.Duration
+}
+
+// Expirer is implemented by the values which are useless after a certain time.
+// The TTLs of such value are capped by the time until its expiry, or by ExpiredTTL if it's expired.
+// The zero expiry means the value never expires.
+type Expirer interface {
+	CacheExpiry() time.Time
+}
+
+// LoaderFunc loads the value when the key is missing in both tiers.
+// The context carries the span of the cache lookup rather than the one of the caller.
+type LoaderFunc func(ctx context.Context) (interface{}, error)
+
+// Tier is where the value is looked up
+type Tier string
+
+const (
+	TierLocal  Tier = "local"
+	TierShared Tier = "shared"
+	TierLoader Tier = "loader"
+)
+
+// Hooks are the callbacks on looking up the cache, the nil ones are skipped
+type Hooks struct {
+	// OnCacheHit is called when the key is hitted in either tier
+	OnCacheHit func(prefix string, key string, count int)
+	// OnCacheMiss is called when the key is missing in both tiers
+	OnCacheMiss func(prefix string, key string, count int)
+	// OnTierLookup is called on each lookup of the tiers. `ok` means hitted for the local and the shared tier,
+	// and loaded without error for the loader.
+	OnTierLookup func(prefix string, tier Tier, ok bool)
+}
+
+type Cache struct {
+	shared cache.Adapter
+	local  cache.Adapter
+
+	mu          sync.RWMutex
+	settings    map[string]Setting
+	broadcaster *Broadcaster
+
+	onCacheHit   func(prefix string, key string, count int)
+	onCacheMiss  func(prefix string, key string, count int)
+	onTierLookup func(prefix string, tier Tier, ok bool)
+
+	singleflight singleflight.Group
+}
+
+// New generates the cache over the shared and local adapters, e.g. cache.NewRedis and cache.NewTinyLFU.
+// Either of them can be nil.
+func New(shared cache.Adapter, local cache.Adapter, settings []Setting, hooks Hooks) *Cache {
+	c := &Cache{
+		shared:       shared,
+		local:        local,
+		settings:     map[string]Setting{},
+		onCacheHit:   hooks.OnCacheHit,
+		onCacheMiss:  hooks.OnCacheMiss,
+		onTierLookup: hooks.OnTierLookup,
+	}
+	if c.onCacheHit == nil {
+		c.onCacheHit = func(prefix string, key string, count int) {}
+	}
+	if c.onCacheMiss == nil {
+		c.onCacheMiss = func(prefix string, key string, count int) {}
+	}
+	if c.onTierLookup == nil {
+		c.onTierLookup = func(prefix string, tier Tier, ok bool) {}
+	}
+	for _, setting := range settings {
+		c.settings[setting.Prefix] = setting
+	}
+	return c
+}
+
+// GetByFunc returns the value in the cache, or loads it by the loader and fills both tiers on cache missed.
+// The concurrent calls of the same key share one shared cache lookup and one loader call.
+func (c *Cache) GetByFunc(ctx context.Context, prefix, key string, container interface{}, loader LoaderFunc) (err error) {
+	ctx, span := tracer.Start(ctx, "cachekit.GetByFunc", trace.WithAttributes(attrPrefix.String(prefix)))
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	setting, ok := c.setting(prefix)
+	if !ok {
+		return ErrPfxNotRegistered
+	}
+
+	cacheKey := getCacheKey(prefix, key)
+	if b, ok := c.getLocal(ctx, prefix, cacheKey); ok {
+		c.onCacheHit(prefix, key, 1)
+		return json.Unmarshal(b, container)
+	}
+
+	// the span of the callers waiting for the other one has no child span
+	sfCtx, sfSpan := tracer.Start(ctx, "cachekit.singleflight")
+	intf, err, shared := c.singleflight.Do(cacheKey, func() (interface{}, error) {
+		b, ok, err := c.getShared(sfCtx, prefix, cacheKey)
+		if err != nil {
+			return nil, err
+		} else if ok {
+			c.onCacheHit(prefix, key, 1)
+			return b, nil
+		}
+
+		// cache missed in both tiers, using the loader to implement Cache-Aside pattern
+		c.onCacheMiss(prefix, key, 1)
+		value, err := loader(sfCtx)
+		c.onTierLookup(prefix, TierLoader, err == nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if b, err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+		if err := c.setShared(sfCtx, cacheKey, b, capTTL(setting.SharedTTL, value)); err != nil {
+			return nil, err
+		}
+		return b, nil
+	})
+	sfSpan.SetAttributes(attrSingleflightShare.Bool(shared))
+	tracekit.RecordError(sfSpan, err)
+	sfSpan.End()
+	if err != nil {
+		return err
+	}
+
+	b := intf.([]byte)
+	if err := json.Unmarshal(b, container); err != nil {
+		return err
+	}
+	c.setLocal(ctx, cacheKey, b, capTTL(setting.LocalTTL, container))
+	return nil
+}
+
+// Get returns the value in the cache, or ErrCacheMiss if it's missing in both tiers
+func (c *Cache) Get(ctx context.Context, prefix, key string, container interface{}) (err error) {
+	ctx, span := tracer.Start(ctx, "cachekit.Get", trace.WithAttributes(attrPrefix.String(prefix)))
+	defer func() {
+		// missing the key is the expected result rather than a failure
+		if err != ErrCacheMiss {
+			tracekit.RecordError(span, err)
+		}
+		span.End()
+	}()
+
+	setting, ok := c.setting(prefix)
+	if !ok {
+		return ErrPfxNotRegistered
+	}
+
+	cacheKey := getCacheKey(prefix, key)
+	if b, ok := c.getLocal(ctx, prefix, cacheKey); ok {
+		c.onCacheHit(prefix, key, 1)
+		return json.Unmarshal(b, container)
+	}
+
+	b, ok, err := c.getShared(ctx, prefix, cacheKey)
+	if err != nil {
+		return err
+	} else if !ok {
+		c.onCacheMiss(prefix, key, 1)
+		return ErrCacheMiss
+	}
+	c.onCacheHit(prefix, key, 1)
+
+	if err := json.Unmarshal(b, container); err != nil {
+		return err
+	}
+	c.setLocal(ctx, cacheKey, b, capTTL(setting.LocalTTL, container))
+	return nil
+}
+
+// Set sets the value into both tiers
+func (c *Cache) Set(ctx context.Context, prefix, key string, value interface{}) (err error) {
+	ctx, span := tracer.Start(ctx, "cachekit.Set", trace.WithAttributes(attrPrefix.String(prefix)))
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	setting, ok := c.setting(prefix)
+	if !ok {
+		return ErrPfxNotRegistered
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	cacheKey := getCacheKey(prefix, key)
+	if err := c.setShared(ctx, cacheKey, b, capTTL(setting.SharedTTL, value)); err != nil {
+		return err
+	}
+	c.setLocal(ctx, cacheKey, b, capTTL(setting.LocalTTL, value))
+	return nil
+}
+
+// Del removes the keys from both tiers, and from the local tiers of the other instances if a broadcaster is registered
+func (c *Cache) Del(ctx context.Context, prefix string, keys ...string) (err error) {
+	ctx, span := tracer.Start(ctx, "cachekit.Del", trace.WithAttributes(attrPrefix.String(prefix)))
+	defer func() {
+		tracekit.RecordError(span, err)
+		span.End()
+	}()
+
+	if _, ok := c.setting(prefix); !ok {
+		return ErrPfxNotRegistered
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	cacheKeys := make([]string, len(keys))
+	for i, key := range keys {
+		cacheKeys[i] = getCacheKey(prefix, key)
+	}
+
+	if c.shared != nil {
+		if err := c.shared.Del(ctx, cacheKeys...); err != nil {
+			return err
+		}
+	}
+	if c.local != nil {
+		if err := c.local.Del(ctx, cacheKeys...); err != nil {
+			return err
+		}
+	}
+	if b := c.getBroadcaster(); b != nil {
+		return b.publish(ctx, cacheKeys)
+	}
+	return nil
+}
+
+// SetSettings replaces the settings of the prefixes, e.g. on reloading the config.
+// The keys cached before keep their TTLs.
+func (c *Cache) SetSettings(settings ...Setting) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, setting := range settings {
+		c.settings[setting.Prefix] = setting
+	}
+}
+
+func (c *Cache) setting(prefix string) (Setting, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	setting, ok := c.settings[prefix]
+	return setting, ok
+}
+
+func (c *Cache) getBroadcaster() *Broadcaster {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.broadcaster
+}
+
+func (c *Cache) getLocal(ctx context.Context, prefix, cacheKey string) ([]byte, bool) {
+	if c.local == nil {
+		return nil, false
+	}
+
+	ctx, span := tracer.Start(ctx, "cachekit.local")
+	defer span.End()
+
+	// allow the failure when getting local cache
+	vals, err := c.local.MGet(ctx, []string{cacheKey})
+	if err != nil || !vals[0].Valid {
+		tracekit.RecordError(span, err)
+		span.SetAttributes(attrHit.Bool(false))
+		c.onTierLookup(prefix, TierLocal, false)
+		return nil, false
+	}
+	span.SetAttributes(attrHit.Bool(true))
+	c.onTierLookup(prefix, TierLocal, true)
+	return vals[0].Bytes, true
+}
+
+func (c *Cache) getShared(ctx context.Context, prefix, cacheKey string) ([]byte, bool, error) {
+	if c.shared == nil {
+		return nil, false, nil
+	}
+
+	ctx, span := tracer.Start(ctx, "cachekit.shared")
+	defer span.End()
+
+	vals, err := c.shared.MGet(ctx, []string{cacheKey})
+	if err != nil {
+		tracekit.RecordError(span, err)
+		return nil, false, err
+	}
+	span.SetAttributes(attrHit.Bool(vals[0].Valid))
+	c.onTierLookup(prefix, TierShared, vals[0].Valid)
+	return vals[0].Bytes, vals[0].Valid, nil
+}
+
+func (c *Cache) setLocal(ctx context.Context, cacheKey string, b []byte, ttl time.Duration) {
+	if c.local == nil || ttl <= 0 {
+		return
+	}
+
+	// allow the failure when setting local cache
+	c.local.MSet(ctx, map[string][]byte{cacheKey: b}, ttl)
+}
+
+func (c *Cache) setShared(ctx context.Context, cacheKey string, b []byte, ttl time.Duration) error {
+	if c.shared == nil || ttl <= 0 {
+		return nil
+	}
+	return c.shared.MSet(ctx, map[string][]byte{cacheKey: b}, ttl)
+}
+
+// capTTL caps the ttl by the time until the expiry of the value, or by ExpiredTTL if it's expired
+func capTTL(ttl time.Duration, value interface{}) time.Duration {
+	expirer, ok := value.(Expirer)
+	if !ok || expirer.CacheExpiry().IsZero() {
+		return ttl
+	}
+
+	if d := expirer.CacheExpiry().Sub(now()); d <= 0 {
+		return min(ttl, ExpiredTTL)
+	} else if d < ttl {
+		return d
+	}
+	return ttl
+}
+
+func getCacheKey(prefix, key string) string {
+	return strings.Join([]string{packageKey, prefix, key}, ":")
+}
@@ -0,0 +1,239 @@
+package cachekit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/viney-shih/go-cache"
+)
+
+type value struct {
+	Name     string
+	ExpireAt time.Time
+}
+
+func (v *value) CacheExpiry() time.Time {
+	return v.ExpireAt
+}
+
+// adapter is an in-memory adapter recording the TTL of each key
+type adapter struct {
+	mu   sync.Mutex
+	vals map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func newAdapter() *adapter {
+	return &adapter{vals: map[string][]byte{}, ttls: map[string]time.Duration{}}
+}
+
+func (a *adapter) MGet(ctx context.Context, keys []string) ([]cache.Value, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	vals := make([]cache.Value, len(keys))
+	for i, key := range keys {
+		b, ok := a.vals[key]
+		vals[i] = cache.Value{Valid: ok, Bytes: b}
+	}
+	return vals, nil
+}
+
+func (a *adapter) MSet(ctx context.Context, keyVals map[string][]byte, ttl time.Duration, options ...cache.MSetOptions) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for key, b := range keyVals {
+		a.vals[key] = b
+		a.ttls[key] = ttl
+	}
+	return nil
+}
+
+func (a *adapter) Del(ctx context.Context, keys ...string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, key := range keys {
+		delete(a.vals, key)
+		delete(a.ttls, key)
+	}
+	return nil
+}
+
+type CacheTestSuite struct {
+	suite.Suite
+	ctx context.Context
+	now time.Time
+
+	shared  *adapter
+	local   *adapter
+	lookups []string
+	impl    *Cache
+}
+
+func TestCacheTestSuite(t *testing.T) {
+	suite.Run(t, new(CacheTestSuite))
+}
+
+func (s *CacheTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+	s.now = time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time {
+		return s.now
+	}
+}
+
+func (s *CacheTestSuite) SetupSubTest() {
+	s.shared = newAdapter()
+	s.local = newAdapter()
+	s.lookups = []string{}
+	s.impl = New(s.shared, s.local, []Setting{
+		{Prefix: "pfx", SharedTTL: time.Hour, LocalTTL: 10 * time.Minute},
+	}, Hooks{
+		OnTierLookup: func(prefix string, tier Tier, ok bool) {
+			s.lookups = append(s.lookups, fmt.Sprintf("%s:%s:%t", prefix, tier, ok))
+		},
+	})
+}
+
+func (s *CacheTestSuite) TestGetByFunc() {
+	for _, t := range []struct {
+		name         string
+		setup        func()
+		loader       LoaderFunc
+		exp          *value
+		expErr       error
+		expSharedTTL time.Duration
+		expLocalTTL  time.Duration
+		expLookups   []string
+	}{
+		{
+			name: "load the value without expiry with the TTLs of the setting",
+			loader: func(ctx context.Context) (interface{}, error) {
+				return &value{Name: "whatever"}, nil
+			},
+			exp:          &value{Name: "whatever"},
+			expSharedTTL: time.Hour,
+			expLocalTTL:  10 * time.Minute,
+			expLookups:   []string{"pfx:local:false", "pfx:shared:false", "pfx:loader:true"},
+		},
+		{
+			name: "load the value expired in the past with the TTLs capped by ExpiredTTL",
+			loader: func(ctx context.Context) (interface{}, error) {
+				return &value{Name: "whatever", ExpireAt: s.now.Add(-1 * time.Second)}, nil
+			},
+			exp:          &value{Name: "whatever", ExpireAt: s.now.Add(-1 * time.Second)},
+			expSharedTTL: ExpiredTTL,
+			expLocalTTL:  ExpiredTTL,
+			expLookups:   []string{"pfx:local:false", "pfx:shared:false", "pfx:loader:true"},
+		},
+		{
+			name: "load the value with the TTLs capped by its expiry",
+			loader: func(ctx context.Context) (interface{}, error) {
+				return &value{Name: "whatever", ExpireAt: s.now.Add(30 * time.Second)}, nil
+			},
+			exp:          &value{Name: "whatever", ExpireAt: s.now.Add(30 * time.Second)},
+			expSharedTTL: 30 * time.Second,
+			expLocalTTL:  30 * time.Second,
+			expLookups:   []string{"pfx:local:false", "pfx:shared:false", "pfx:loader:true"},
+		},
+		{
+			name: "refill the local cache from the shared cache with the TTL capped by the expiry",
+			setup: func() {
+				s.shared.vals["ca:pfx:key"] = []byte(`{"Name":"whatever","ExpireAt":"2025-03-15T00:20:00Z"}`)
+				s.shared.ttls["ca:pfx:key"] = time.Hour
+			},
+			loader: func(ctx context.Context) (interface{}, error) {
+				return nil, errors.New("should not be called")
+			},
+			exp:          &value{Name: "whatever", ExpireAt: s.now.Add(20 * time.Minute)},
+			expSharedTTL: time.Hour,
+			expLocalTTL:  10 * time.Minute,
+			expLookups:   []string{"pfx:local:false", "pfx:shared:true"},
+		},
+		{
+			name: "failed to load the value",
+			loader: func(ctx context.Context) (interface{}, error) {
+				return nil, errors.New("unknown error")
+			},
+			exp:        &value{},
+			expErr:     errors.New("unknown error"),
+			expLookups: []string{"pfx:local:false", "pfx:shared:false", "pfx:loader:false"},
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			obj := &value{}
+			err := s.impl.GetByFunc(s.ctx, "pfx", "key", obj, t.loader)
+			s.Equal(t.expErr, err)
+			s.Equal(t.exp, obj)
+			s.Equal(t.expSharedTTL, s.shared.ttls["ca:pfx:key"])
+			s.Equal(t.expLocalTTL, s.local.ttls["ca:pfx:key"])
+			s.Equal(t.expLookups, s.lookups)
+		})
+	}
+}
+
+func (s *CacheTestSuite) TestSetSettings() {
+	s.Suite.Run("set the value with the TTLs of the new setting", func() {
+		s.impl.SetSettings(Setting{Prefix: "pfx", SharedTTL: 2 * time.Hour, LocalTTL: time.Minute})
+		s.NoError(s.impl.Set(s.ctx, "pfx", "key", &value{Name: "whatever"}))
+
+		s.Equal(2*time.Hour, s.shared.ttls["ca:pfx:key"])
+		s.Equal(time.Minute, s.local.ttls["ca:pfx:key"])
+	})
+}
+
+func (s *CacheTestSuite) TestGetSetDel() {
+	for _, t := range []struct {
+		name   string
+		setup  func()
+		prefix string
+		exp    *value
+		expErr error
+	}{
+		{
+			name: "get the value set before",
+			setup: func() {
+				s.NoError(s.impl.Set(s.ctx, "pfx", "key", &value{Name: "whatever"}))
+			},
+			prefix: "pfx",
+			exp:    &value{Name: "whatever"},
+		},
+		{
+			name: "cache missed after the value is deleted",
+			setup: func() {
+				s.NoError(s.impl.Set(s.ctx, "pfx", "key", &value{Name: "whatever"}))
+				s.NoError(s.impl.Del(s.ctx, "pfx", "key"))
+			},
+			prefix: "pfx",
+			exp:    &value{},
+			expErr: ErrCacheMiss,
+		},
+		{
+			name:   "prefix not registered",
+			prefix: "unknown",
+			exp:    &value{},
+			expErr: ErrPfxNotRegistered,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			if t.setup != nil {
+				t.setup()
+			}
+
+			obj := &value{}
+			s.ErrorIs(s.impl.Get(s.ctx, t.prefix, "key", obj), t.expErr)
+			s.Equal(t.exp, obj)
+		})
+	}
+}
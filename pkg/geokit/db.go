@@ -0,0 +1,138 @@
+// Package geokit locates the ip addresses by a MaxMind-format database, e.g. GeoLite2-Country,
+// and finds the client ip of the requests behind the trusted proxies.
+package geokit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Hao1995/short-url/pkg/logkit"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Location is where an ip address is, which is empty if the database doesn't know it
+type Location struct {
+	// Country is the ISO 3166-1 alpha-2 code, e.g. TW
+	Country string
+	// Continent is the two-letter code, e.g. AS, EU or NA
+	Continent string
+}
+
+// record is the part of the Country and City databases used
+type record struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Continent struct {
+		Code string `maxminddb:"code"`
+	} `maxminddb:"continent"`
+}
+
+// DB looks up the locations in the .mmdb file loaded at startup, which can be swapped at runtime.
+// The file is read into the memory rather than mapped, so that the lookups in flight survive the swap.
+type DB struct {
+	reader atomic.Pointer[maxminddb.Reader]
+
+	mu      sync.Mutex
+	path    string
+	modTime time.Time
+}
+
+// Open loads the database of the file, the DB of the empty path locates nothing
+func Open(path string) (*DB, error) {
+	db := &DB{}
+	if _, err := db.Reload(path); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Reload loads the file if the path or its modification time changes. The database in use is kept if the file is invalid.
+func (db *DB) Reload(path string) (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if path == "" {
+		changed := db.path != ""
+		db.reader.Store(nil)
+		db.path, db.modTime = "", time.Time{}
+		return changed, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, err
+	}
+	if path == db.path && info.ModTime().Equal(db.modTime) {
+		return false, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	reader, err := maxminddb.FromBytes(b)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", path, err)
+	}
+	db.reader.Store(reader)
+	db.path, db.modTime = path, info.ModTime()
+	return true, nil
+}
+
+// Run reloads the file of the current path every interval until the context is done, so that the updates
+// of the file in place, e.g. by geoipupdate, take effect without reloading the config
+func (db *DB) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			path := db.currentPath()
+			if path == "" {
+				continue
+			}
+			if ok, err := db.Reload(path); err != nil {
+				slog.WarnContext(ctx, "geokit. Failed to reload the GeoIP database, keep the current one", "path", path, logkit.Err(err))
+			} else if ok {
+				slog.InfoContext(ctx, "geokit. Reload the GeoIP database", "path", path)
+			}
+		}
+	}
+}
+
+// Loaded reports whether a database is loaded, the geo rules are never matched otherwise
+func (db *DB) Loaded() bool {
+	return db.reader.Load() != nil
+}
+
+func (db *DB) currentPath() string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.path
+}
+
+// Lookup returns the location of the ip address
+func (db *DB) Lookup(addr netip.Addr) (Location, error) {
+	reader := db.reader.Load()
+	if reader == nil || !addr.IsValid() {
+		return Location{}, nil
+	}
+
+	var r record
+	if err := reader.Lookup(addr.AsSlice(), &r); err != nil {
+		return Location{}, err
+	}
+	return Location{Country: r.Country.ISOCode, Continent: r.Continent.Code}, nil
+}
@@ -0,0 +1,164 @@
+package geokit_test
+
+import (
+	"net/http"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Hao1995/short-url/pkg/geokit"
+	"github.com/Hao1995/short-url/pkg/geokit/geokittest"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type GeoKitTestSuite struct {
+	suite.Suite
+}
+
+func TestGeoKitTestSuite(t *testing.T) {
+	suite.Run(t, new(GeoKitTestSuite))
+}
+
+func (s *GeoKitTestSuite) TestLookup() {
+	path := filepath.Join(s.T().TempDir(), "country.mmdb")
+	s.Require().NoError(geokittest.WriteDB(path, map[string]geokit.Location{
+		"192.0.2.0/24":  {Country: "TW", Continent: "AS"},
+		"2001:db8::/32": {Country: "DE", Continent: "EU"},
+	}))
+	db, err := geokit.Open(path)
+	s.Require().NoError(err)
+
+	for _, t := range []struct {
+		name string
+		addr string
+		exp  geokit.Location
+	}{
+		{
+			name: "IPv4",
+			addr: "192.0.2.10",
+			exp:  geokit.Location{Country: "TW", Continent: "AS"},
+		},
+		{
+			name: "IPv6",
+			addr: "2001:db8::1",
+			exp:  geokit.Location{Country: "DE", Continent: "EU"},
+		},
+		{
+			name: "unknown address",
+			addr: "198.51.100.1",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			location, err := db.Lookup(netip.MustParseAddr(t.addr))
+			s.NoError(err)
+			s.Equal(t.exp, location)
+		})
+	}
+}
+
+func (s *GeoKitTestSuite) TestReload() {
+	dir := s.T().TempDir()
+	path := filepath.Join(dir, "country.mmdb")
+	addr := netip.MustParseAddr("192.0.2.10")
+
+	s.Suite.Run("locate nothing without the file", func() {
+		db, err := geokit.Open("")
+		s.Require().NoError(err)
+		s.False(db.Loaded())
+		location, err := db.Lookup(addr)
+		s.NoError(err)
+		s.Equal(geokit.Location{}, location)
+	})
+
+	s.Suite.Run("swap the database once the file changes", func() {
+		s.Require().NoError(geokittest.WriteDB(path, map[string]geokit.Location{"192.0.2.0/24": {Country: "TW", Continent: "AS"}}))
+		db, err := geokit.Open(path)
+		s.Require().NoError(err)
+		s.True(db.Loaded())
+
+		ok, err := db.Reload(path)
+		s.NoError(err)
+		s.False(ok)
+
+		s.Require().NoError(geokittest.WriteDB(path, map[string]geokit.Location{"192.0.2.0/24": {Country: "JP", Continent: "AS"}}))
+		s.Require().NoError(os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+		ok, err = db.Reload(path)
+		s.NoError(err)
+		s.True(ok)
+		location, err := db.Lookup(addr)
+		s.NoError(err)
+		s.Equal("JP", location.Country)
+	})
+
+	s.Suite.Run("keep the database if the new file is invalid", func() {
+		db, err := geokit.Open(path)
+		s.Require().NoError(err)
+
+		invalid := filepath.Join(dir, "invalid.mmdb")
+		s.Require().NoError(os.WriteFile(invalid, []byte("whatever"), 0o644))
+		_, err = db.Reload(invalid)
+		s.Error(err)
+		location, err := db.Lookup(addr)
+		s.NoError(err)
+		s.Equal("JP", location.Country)
+	})
+}
+
+func (s *GeoKitTestSuite) TestClientIP() {
+	proxies, err := geokit.NewProxies([]string{"10.0.0.0/8", "2001:db8::1"})
+	s.Require().NoError(err)
+
+	for _, t := range []struct {
+		name       string
+		remoteAddr string
+		forwarded  []string
+		exp        string
+	}{
+		{
+			name:       "ignore the header sent by the untrusted peer",
+			remoteAddr: "192.0.2.10:1234",
+			forwarded:  []string{"198.51.100.1"},
+			exp:        "192.0.2.10",
+		},
+		{
+			name:       "take the address forwarded by the trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"198.51.100.1, 192.0.2.10"},
+			exp:        "192.0.2.10",
+		},
+		{
+			name:       "walk through the trusted proxies in the multiple headers",
+			remoteAddr: "[2001:db8::1]:1234",
+			forwarded:  []string{"198.51.100.1, 192.0.2.10", "10.0.0.2"},
+			exp:        "192.0.2.10",
+		},
+		{
+			name:       "take the trusted proxy without the header",
+			remoteAddr: "10.0.0.1:1234",
+			exp:        "10.0.0.1",
+		},
+		{
+			name:       "stop at the garbage forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"192.0.2.10, unknown"},
+			exp:        "10.0.0.1",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.RemoteAddr = t.remoteAddr
+			for _, v := range t.forwarded {
+				r.Header.Add(geokit.HeaderForwardedFor, v)
+			}
+			s.Equal(netip.MustParseAddr(t.exp), proxies.ClientIP(r))
+		})
+	}
+
+	s.Suite.Run("reject the invalid proxy", func() {
+		s.Error(proxies.Set([]string{"10.0.0.0/33"}))
+		s.Error(proxies.Set([]string{"whatever"}))
+	})
+}
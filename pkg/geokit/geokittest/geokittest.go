@@ -0,0 +1,47 @@
+// Package geokittest generates the tiny .mmdb files for the tests, shaped like the ones of GeoLite2-Country
+package geokittest
+
+import (
+	"net"
+	"os"
+
+	"github.com/Hao1995/short-url/pkg/geokit"
+
+	"github.com/maxmind/mmdbwriter"
+	"github.com/maxmind/mmdbwriter/mmdbtype"
+)
+
+// WriteDB writes the database of the locations by the CIDRs to the path.
+// The reserved networks are allowed, so that the tests can use the documentation ones, e.g. 192.0.2.0/24.
+func WriteDB(path string, locations map[string]geokit.Location) error {
+	tree, err := mmdbwriter.New(mmdbwriter.Options{
+		DatabaseType:            "GeoLite2-Country",
+		RecordSize:              24,
+		IncludeReservedNetworks: true,
+	})
+	if err != nil {
+		return err
+	}
+	for cidr, location := range locations {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return err
+		}
+		if err := tree.Insert(network, mmdbtype.Map{
+			"country":   mmdbtype.Map{"iso_code": mmdbtype.String(location.Country)},
+			"continent": mmdbtype.Map{"code": mmdbtype.String(location.Continent)},
+		}); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := tree.WriteTo(f); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,95 @@
+package geokit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+)
+
+// HeaderForwardedFor is appended with the address of the peer by each proxy
+const HeaderForwardedFor = "X-Forwarded-For"
+
+// Proxies are the trusted proxies in front of the service, which can be replaced at runtime.
+// Unlike the default of gin, the X-Forwarded-For of the peers other than them is never trusted.
+type Proxies struct {
+	prefixes atomic.Pointer[[]netip.Prefix]
+}
+
+// NewProxies generates the trusted proxies of the ips or the CIDRs, e.g. `10.0.0.1` or `10.0.0.0/8`
+func NewProxies(cidrs []string) (*Proxies, error) {
+	p := &Proxies{}
+	if err := p.Set(cidrs); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Set replaces the current proxies, which are kept if the new ones are invalid
+func (p *Proxies) Set(cidrs []string) error {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := ParsePrefix(cidr)
+		if err != nil {
+			return err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	p.prefixes.Store(&prefixes)
+	return nil
+}
+
+// ParsePrefix parses the ip or the CIDR, the ip is regarded as the CIDR of itself
+func ParsePrefix(cidr string) (netip.Prefix, error) {
+	if !strings.Contains(cidr, "/") {
+		addr, err := netip.ParseAddr(cidr)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+	}
+	return prefix.Masked(), nil
+}
+
+// ClientIP returns the address of the peer, or the one the trusted proxies forwarded for if the peer is one of them.
+// X-Forwarded-For is walked from the right, and the first address not trusted is the client,
+// since the entries on the left of it can be forged by the client.
+func (p *Proxies) ClientIP(r *http.Request) netip.Addr {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}
+	}
+	addr = addr.WithZone("").Unmap()
+
+	// the multiple headers are regarded as one list joined in order
+	hops := strings.Split(strings.Join(r.Header.Values(HeaderForwardedFor), ","), ",")
+	for i := len(hops) - 1; i >= 0 && p.trusted(addr); i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// the trusted proxy forwarded garbage, which is the closest to the client we can tell
+			return addr
+		}
+		addr = hop.WithZone("").Unmap()
+	}
+	return addr
+}
+
+func (p *Proxies) trusted(addr netip.Addr) bool {
+	for _, prefix := range *p.prefixes.Load() {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
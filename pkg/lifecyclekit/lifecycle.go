@@ -0,0 +1,115 @@
+// Package lifecyclekit runs the background workers of the service, and shuts the service down step by step
+// in the order the steps are appended, each of them within its own timeout.
+package lifecyclekit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/Hao1995/short-url/pkg/logkit"
+)
+
+// StepFunc releases a resource, it should return once the context is done
+type StepFunc func(ctx context.Context) error
+
+type step struct {
+	name    string
+	timeout time.Duration
+	fn      StepFunc
+}
+
+type Manager struct {
+	steps []step
+
+	workerCtx    context.Context
+	cancelWorker context.CancelFunc
+	workers      sync.WaitGroup
+}
+
+func New() *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{
+		workerCtx:    ctx,
+		cancelWorker: cancel,
+	}
+}
+
+// Append adds the step run on shutdown after the ones appended before
+func (m *Manager) Append(name string, timeout time.Duration, fn StepFunc) {
+	m.steps = append(m.steps, step{name: name, timeout: timeout, fn: fn})
+}
+
+// Go runs the background worker until its context is canceled by StopWorkers
+func (m *Manager) Go(fn func(ctx context.Context)) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		fn(m.workerCtx)
+	}()
+}
+
+// StopWorkers cancels the context of the background workers and waits for them to return.
+// It's a StepFunc, so that the workers are stopped at the right step.
+func (m *Manager) StopWorkers(ctx context.Context) error {
+	m.cancelWorker()
+
+	done := make(chan struct{})
+	go func() {
+		m.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Wait blocks until one of the signals is received or the context is done
+func (m *Manager) Wait(ctx context.Context, signals ...os.Signal) {
+	ctx, stop := signal.NotifyContext(ctx, signals...)
+	defer stop()
+	<-ctx.Done()
+}
+
+// Shutdown runs all the steps in order. A failed or timed out step doesn't stop the following ones,
+// since the resources released by them are independent, and the errors are joined.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, s := range m.steps {
+		start := time.Now()
+		if err := m.run(ctx, s); err != nil {
+			slog.ErrorContext(ctx, "lifecyclekit. failed to shut down", "step", s.name, "elapsed", time.Since(start), logkit.Err(err))
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+			continue
+		}
+		slog.InfoContext(ctx, "lifecyclekit. shut down", "step", s.name, "elapsed", time.Since(start))
+	}
+	return errors.Join(errs...)
+}
+
+// run runs the step within its timeout, it returns on the timeout even if the step doesn't respect the context
+func (m *Manager) run(ctx context.Context, s step) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.fn(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
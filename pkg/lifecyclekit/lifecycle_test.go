@@ -0,0 +1,132 @@
+package lifecyclekit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LifecycleTestSuite struct {
+	suite.Suite
+	ctx context.Context
+}
+
+func TestLifecycleTestSuite(t *testing.T) {
+	suite.Run(t, new(LifecycleTestSuite))
+}
+
+func (s *LifecycleTestSuite) SetupSuite() {
+	s.ctx = context.Background()
+}
+
+func (s *LifecycleTestSuite) TestShutdown() {
+	for _, t := range []struct {
+		name   string
+		steps  func(m *Manager, ran *[]string)
+		expRan []string
+		expErr string
+	}{
+		{
+			name: "run the steps in order",
+			steps: func(m *Manager, ran *[]string) {
+				for _, name := range []string{"server", "workers", "db"} {
+					m.Append(name, time.Second, func(ctx context.Context) error {
+						*ran = append(*ran, name)
+						return nil
+					})
+				}
+			},
+			expRan: []string{"server", "workers", "db"},
+		},
+		{
+			name: "keep running the steps after a step failed",
+			steps: func(m *Manager, ran *[]string) {
+				m.Append("server", time.Second, func(ctx context.Context) error {
+					*ran = append(*ran, "server")
+					return errors.New("unknown error")
+				})
+				m.Append("db", time.Second, func(ctx context.Context) error {
+					*ran = append(*ran, "db")
+					return nil
+				})
+			},
+			expRan: []string{"server", "db"},
+			expErr: "server: unknown error",
+		},
+		{
+			name: "give up the step after its timeout",
+			steps: func(m *Manager, ran *[]string) {
+				m.Append("server", 10*time.Millisecond, func(ctx context.Context) error {
+					// ignore the context on purpose
+					time.Sleep(time.Second)
+					return nil
+				})
+				m.Append("db", time.Second, func(ctx context.Context) error {
+					*ran = append(*ran, "db")
+					return nil
+				})
+			},
+			expRan: []string{"db"},
+			expErr: "server: context deadline exceeded",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			m := New()
+			ran := []string{}
+			t.steps(m, &ran)
+
+			err := m.Shutdown(s.ctx)
+			if t.expErr != "" {
+				s.EqualError(err, t.expErr)
+			} else {
+				s.NoError(err)
+			}
+			s.Equal(t.expRan, ran)
+		})
+	}
+}
+
+func (s *LifecycleTestSuite) TestStopWorkers() {
+	for _, t := range []struct {
+		name       string
+		worker     func(ctx context.Context, stopped *atomic.Bool)
+		expErr     error
+		expStopped bool
+	}{
+		{
+			name: "wait for the workers to return",
+			worker: func(ctx context.Context, stopped *atomic.Bool) {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond)
+				stopped.Store(true)
+			},
+			expStopped: true,
+		},
+		{
+			name: "give up the workers ignoring the context",
+			worker: func(ctx context.Context, stopped *atomic.Bool) {
+				time.Sleep(time.Second)
+				stopped.Store(true)
+			},
+			expErr:     context.DeadlineExceeded,
+			expStopped: false,
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			m := New()
+			stopped := &atomic.Bool{}
+			m.Go(func(ctx context.Context) {
+				t.worker(ctx, stopped)
+			})
+
+			ctx, cancel := context.WithTimeout(s.ctx, 100*time.Millisecond)
+			defer cancel()
+			s.ErrorIs(m.StopWorkers(ctx), t.expErr)
+			s.Equal(t.expStopped, stopped.Load())
+		})
+	}
+}
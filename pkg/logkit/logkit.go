@@ -0,0 +1,71 @@
+// Package logkit sets up the structured JSON logger of log/slog, which attaches the request id and the trace
+// of the context to the records, redacts the secrets and the query strings, and samples the hot paths.
+package logkit
+
+import (
+	"io"
+	"log/slog"
+	"sync/atomic"
+)
+
+type Config struct {
+	// Level is one of `debug`, `info`, `warn` and `error`
+	Level string
+	// SampleRatio is the ratio of the records below the warn level kept by the sampled logger
+	SampleRatio float64
+}
+
+var (
+	level   = new(slog.LevelVar)
+	handler atomic.Pointer[slog.Handler]
+	sampled atomic.Pointer[slog.Logger]
+)
+
+// Setup sets the default logger of slog, which also takes over the standard logger
+func Setup(w io.Writer, cfg Config) error {
+	if err := SetLevel(cfg.Level); err != nil {
+		return err
+	}
+
+	var h slog.Handler = slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	})
+	h = &contextHandler{Handler: h}
+
+	slog.SetDefault(slog.New(h))
+	handler.Store(&h)
+	SetSampleRatio(cfg.SampleRatio)
+	return nil
+}
+
+// SetSampleRatio changes the sample ratio of the sampled logger set up by Setup
+func SetSampleRatio(ratio float64) {
+	if h := handler.Load(); h != nil {
+		sampled.Store(slog.New(newSamplingHandler(*h, ratio)))
+	}
+}
+
+// SetLevel changes the level of the loggers set up by Setup
+func SetLevel(s string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// Sampled returns the logger for the hot paths, e.g. redirecting, which keeps a part of the records below the warn level.
+// It's the default logger before Setup.
+func Sampled() *slog.Logger {
+	if l := sampled.Load(); l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// Err is the attribute of the error
+func Err(err error) slog.Attr {
+	return slog.Any("error", err)
+}
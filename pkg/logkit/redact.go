@@ -0,0 +1,62 @@
+package logkit
+
+import (
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces the secrets in the logs
+const Redacted = "[REDACTED]"
+
+// secretKeys are the substrings of the attribute keys whose values are secrets
+var secretKeys = []string{"password", "passwd", "secret", "token", "authorization", "api_key", "apikey", "cookie", "dsn"}
+
+// redact hides the values of the secret attributes, and the query strings and the credentials of the urls,
+// which might carry the personal data or the tokens
+func redact(groups []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, secret := range secretKeys {
+		if strings.Contains(key, secret) {
+			return slog.String(a.Key, Redacted)
+		}
+	}
+
+	if a.Value.Kind() == slog.KindString {
+		if s, ok := RedactURL(a.Value.String()); ok {
+			return slog.String(a.Key, s)
+		}
+	}
+	return a
+}
+
+// RedactURL hides the query string, the fragment and the password of the url.
+// It returns false if the string isn't an absolute url or there's nothing to hide.
+func RedactURL(s string) (string, bool) {
+	if !strings.Contains(s, "://") {
+		return s, false
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return s, false
+	}
+
+	redacted := false
+	if u.RawQuery != "" {
+		u.RawQuery = Redacted
+		redacted = true
+	}
+	if u.Fragment != "" {
+		u.Fragment = ""
+		u.RawFragment = ""
+		redacted = true
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.User(u.User.Username())
+		redacted = true
+	}
+	if !redacted {
+		return s, false
+	}
+	return u.String(), true
+}
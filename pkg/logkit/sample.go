@@ -0,0 +1,44 @@
+package logkit
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sync/atomic"
+)
+
+// samplingHandler keeps one out of every `every` records below the warn level, and all the others
+type samplingHandler struct {
+	slog.Handler
+	every uint64
+	count *atomic.Uint64
+}
+
+func newSamplingHandler(h slog.Handler, ratio float64) *samplingHandler {
+	every := uint64(math.MaxUint64)
+	if ratio >= 1 {
+		every = 1
+	} else if ratio > 0 {
+		every = uint64(math.Round(1 / ratio))
+	}
+	return &samplingHandler{Handler: h, every: every, count: new(atomic.Uint64)}
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.Handler.Enabled(ctx, l)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level < slog.LevelWarn && h.count.Add(1)%h.every != 0 {
+		return nil
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithAttrs(attrs), every: h.every, count: h.count}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithGroup(name), every: h.every, count: h.count}
+}
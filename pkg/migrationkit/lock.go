@@ -0,0 +1,37 @@
+package migrationkit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLocked means the lock is held by another session, e.g. another pod migrating the same DB
+var ErrLocked = errors.New("migration lock is held by another session")
+
+// mysqlLocker is the goose session locker over the named lock of MySQL, which is released
+// when the session ends even if the process crashes
+type mysqlLocker struct {
+	name    string
+	timeout time.Duration
+}
+
+func (l *mysqlLocker) SessionLock(ctx context.Context, conn *sql.Conn) error {
+	var ok sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", l.name, int(l.timeout.Seconds())).Scan(&ok); err != nil {
+		return fmt.Errorf("failed to get the migration lock: %w", err)
+	}
+	if !ok.Valid || ok.Int64 != 1 {
+		return ErrLocked
+	}
+	return nil
+}
+
+func (l *mysqlLocker) SessionUnlock(ctx context.Context, conn *sql.Conn) error {
+	if _, err := conn.ExecContext(ctx, "DO RELEASE_LOCK(?)", l.name); err != nil {
+		return fmt.Errorf("failed to release the migration lock: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package migrationkit runs the goose migrations against MySQL. The migrations are read from a file system,
+// e.g. an embed.FS, and the runs are serialized by a named lock so that concurrent pods don't race.
+package migrationkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+const (
+	// DefaultLockName is the name of the MySQL lock held during migrating
+	DefaultLockName = "goose_migration"
+	// DefaultLockTimeout is how long to wait for the lock held by another session
+	DefaultLockTimeout = time.Minute
+)
+
+type Config struct {
+	LockName    string
+	LockTimeout time.Duration
+}
+
+// Plan is a migration to be applied, with the SQL statements of its direction
+type Plan struct {
+	Version int64
+	Source  string
+	SQL     string
+}
+
+// Migrator runs the migrations of the file system against the DB
+type Migrator struct {
+	db       *sql.DB
+	fsys     fs.FS
+	locker   *mysqlLocker
+	provider *goose.Provider
+	// unlocked runs the steps of a run whose lock is held by the migrator, e.g. Redo
+	unlocked *goose.Provider
+}
+
+// NewMigrator generates the migrator of the migrations at the root of the file system
+func NewMigrator(db *sql.DB, fsys fs.FS, cfg Config) (*Migrator, error) {
+	if cfg.LockName == "" {
+		cfg.LockName = DefaultLockName
+	}
+	if cfg.LockTimeout <= 0 {
+		cfg.LockTimeout = DefaultLockTimeout
+	}
+
+	locker := &mysqlLocker{name: cfg.LockName, timeout: cfg.LockTimeout}
+	provider, err := goose.NewProvider(goose.DialectMySQL, db, fsys, goose.WithSessionLocker(locker))
+	if err != nil {
+		return nil, fmt.Errorf("failed to init goose provider: %w", err)
+	}
+	unlocked, err := goose.NewProvider(goose.DialectMySQL, db, fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init goose provider: %w", err)
+	}
+	return &Migrator{db: db, fsys: fsys, locker: locker, provider: provider, unlocked: unlocked}, nil
+}
+
+// Up applies all the pending migrations
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	return m.provider.Up(ctx)
+}
+
+// Down rolls back the latest applied migration
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	return m.provider.Down(ctx)
+}
+
+// Redo rolls back the latest applied migration, then applies it again.
+// The lock is held across both steps, so that another pod can't migrate in between.
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	var results []*goose.MigrationResult
+	err := m.withLock(ctx, func() error {
+		down, err := m.unlocked.Down(ctx)
+		if err != nil {
+			return err
+		}
+		results = append(results, down)
+
+		up, err := m.unlocked.UpByOne(ctx)
+		if err != nil {
+			return err
+		}
+		results = append(results, up)
+		return nil
+	})
+	return results, err
+}
+
+// withLock runs fn while holding the lock on a dedicated session
+func (m *Migrator) withLock(ctx context.Context, fn func() error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get the connection of the migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	if err := m.locker.SessionLock(ctx, conn); err != nil {
+		return err
+	}
+	defer func() {
+		// release the lock even if the run is canceled, otherwise it's held until the session ends
+		if unlockErr := m.locker.SessionUnlock(context.WithoutCancel(ctx), conn); err == nil {
+			err = unlockErr
+		}
+	}()
+	return fn()
+}
+
+// Status returns the states of all the migrations
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	return m.provider.Status(ctx)
+}
+
+// Version returns the version of the latest applied migration
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	return m.provider.GetDBVersion(ctx)
+}
+
+// PlanUp returns the pending migrations applied by Up, without applying them
+func (m *Migrator) PlanUp(ctx context.Context) ([]*Plan, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := []*Plan{}
+	for _, status := range statuses {
+		if status.State != goose.StatePending {
+			continue
+		}
+		plan, err := m.plan(status.Source, true)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// PlanDown returns the migration rolled back by Down, without rolling it back
+func (m *Migrator) PlanDown(ctx context.Context) ([]*Plan, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if statuses[i].State == goose.StateApplied {
+			plan, err := m.plan(statuses[i].Source, false)
+			if err != nil {
+				return nil, err
+			}
+			return []*Plan{plan}, nil
+		}
+	}
+	return []*Plan{}, nil
+}
+
+func (m *Migrator) plan(source *goose.Source, up bool) (*Plan, error) {
+	b, err := fs.ReadFile(m.fsys, source.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration(%d): %w", source.Version, err)
+	}
+	return &Plan{Version: source.Version, Source: source.Path, SQL: section(string(b), up)}, nil
+}
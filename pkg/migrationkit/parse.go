@@ -0,0 +1,34 @@
+package migrationkit
+
+import (
+	"strings"
+)
+
+const (
+	annotationUp   = "-- +goose Up"
+	annotationDown = "-- +goose Down"
+	annotation     = "-- +goose"
+)
+
+// section returns the SQL of the up or down section of a goose SQL migration, without the goose annotations
+func section(content string, up bool) string {
+	want := annotationDown
+	if up {
+		want = annotationUp
+	}
+
+	lines := []string{}
+	in := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, annotationUp) || strings.HasPrefix(trimmed, annotationDown):
+			in = strings.HasPrefix(trimmed, want)
+		case strings.HasPrefix(trimmed, annotation):
+			// e.g. StatementBegin, NO TRANSACTION
+		case in:
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
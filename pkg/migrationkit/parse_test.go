@@ -0,0 +1,59 @@
+package migrationkit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ParseTestSuite struct {
+	suite.Suite
+}
+
+func TestParseTestSuite(t *testing.T) {
+	suite.Run(t, new(ParseTestSuite))
+}
+
+func (s *ParseTestSuite) TestSection() {
+	content := `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE ` + "`t`" + ` (
+	` + "`id`" + ` INT
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE ` + "`t`" + `;
+-- +goose StatementEnd
+`
+	for _, t := range []struct {
+		name    string
+		content string
+		up      bool
+		exp     string
+	}{
+		{
+			name:    "get the up section",
+			content: content,
+			up:      true,
+			exp:     "CREATE TABLE `t` (\n\t`id` INT\n);",
+		},
+		{
+			name:    "get the down section",
+			content: content,
+			up:      false,
+			exp:     "DROP TABLE `t`;",
+		},
+		{
+			name:    "no down section",
+			content: "-- +goose Up\nSELECT 1;\n",
+			up:      false,
+			exp:     "",
+		},
+	} {
+		s.Suite.Run(t.name, func() {
+			s.Equal(t.exp, section(t.content, t.up))
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package policykit
+
+import (
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// cgnat is the shared address space of the carrier-grade NAT, RFC 6598
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+// ParseIP parses the IP literal of the host, including the IPv4 forms which the browsers accept
+// but netip doesn't, e.g. `2130706433`, `0x7f.1` and `0177.0.0.1` for 127.0.0.1
+func ParseIP(host string) (netip.Addr, bool) {
+	if addr, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return addr.WithZone("").Unmap(), true
+	}
+	return parseLooseIPv4(host)
+}
+
+// parseLooseIPv4 parses the IPv4 address in the inet_aton forms, where each part is decimal, octal (0 prefixed)
+// or hex (0x prefixed), and the last part fills the rest bytes
+func parseLooseIPv4(host string) (netip.Addr, bool) {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return netip.Addr{}, false
+	}
+
+	var ip uint64
+	for i, part := range parts {
+		n, ok := parseIPv4Part(part)
+		if !ok {
+			return netip.Addr{}, false
+		}
+		if i < len(parts)-1 {
+			if n > 0xff {
+				return netip.Addr{}, false
+			}
+			ip |= n << (8 * (3 - i))
+			continue
+		}
+		if n >= 1<<(8*(4-i)) {
+			return netip.Addr{}, false
+		}
+		ip |= n
+	}
+	return netip.AddrFrom4([4]byte{byte(ip >> 24), byte(ip >> 16), byte(ip >> 8), byte(ip)}), true
+}
+
+func parseIPv4Part(part string) (uint64, bool) {
+	base := 10
+	switch {
+	case strings.HasPrefix(part, "0x"):
+		base, part = 16, part[2:]
+	case len(part) > 1 && part[0] == '0':
+		base, part = 8, part[1:]
+	}
+	if part == "" || strings.ContainsAny(part, "+-_") {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(part, base, 32)
+	return n, err == nil
+}
+
+// isPrivate reports whether the address isn't routable on the internet
+func isPrivate(addr netip.Addr) bool {
+	return addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		cgnat.Contains(addr)
+}
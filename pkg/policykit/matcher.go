@@ -0,0 +1,114 @@
+package policykit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Matcher matches the hosts against the patterns, which are one of
+//   - exact: `example.com`
+//   - wildcard: `*.example.com`, which matches the subdomains rather than example.com itself
+//   - regex: `/^ads[0-9]*\.example\.com$/`, which is matched against the normalized host
+//
+// The exact and the wildcard patterns are looked up in maps, so that a long list costs nothing on checking.
+type Matcher struct {
+	exact    map[string]string
+	suffixes map[string]string
+	regexps  []*regexp.Regexp
+}
+
+// NewMatcher compiles the patterns, the empty ones are skipped
+func NewMatcher(patterns []string) (*Matcher, error) {
+	m := &Matcher{
+		exact:    map[string]string{},
+		suffixes: map[string]string{},
+	}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		switch {
+		case pattern == "":
+		case len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/"):
+			re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			m.regexps = append(m.regexps, re)
+		case strings.HasPrefix(pattern, "*."):
+			host, err := NormalizeHost(pattern[2:])
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			m.suffixes["."+host] = pattern
+		default:
+			host, err := NormalizeHost(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+			m.exact[host] = pattern
+		}
+	}
+	return m, nil
+}
+
+// Match returns the pattern matching the normalized host
+func (m *Matcher) Match(host string) (string, bool) {
+	if pattern, ok := m.exact[host]; ok {
+		return pattern, true
+	}
+	for i := strings.IndexByte(host, '.'); i >= 0; {
+		if pattern, ok := m.suffixes[host[i:]]; ok {
+			return pattern, true
+		}
+		j := strings.IndexByte(host[i+1:], '.')
+		if j < 0 {
+			break
+		}
+		i += j + 1
+	}
+	for _, re := range m.regexps {
+		if re.MatchString(host) {
+			return "/" + re.String() + "/", true
+		}
+	}
+	return "", false
+}
+
+// NormalizeHost lowercases the host, removes the trailing dot and converts the internationalized one to punycode,
+// so that the different spellings of the same host are matched by the same pattern
+func NormalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return "", fmt.Errorf("empty host")
+	}
+	return idna.Lookup.ToASCII(host)
+}
+
+// ReadPatterns reads one pattern per line, the blank lines and the ones starting with `#` are skipped
+func ReadPatterns(r io.Reader) ([]string, error) {
+	patterns := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// ReadPatternsFile reads the patterns from the file, see ReadPatterns
+func ReadPatternsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadPatterns(f)
+}
@@ -0,0 +1,117 @@
+// Package policykit decides whether a url is allowed as the destination of a short url.
+package policykit
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// Reason is the code of the rejection, which is returned to the clients
+type Reason string
+
+const (
+	ReasonInvalidURL       Reason = "invalid_url"
+	ReasonSchemeNotAllowed Reason = "scheme_not_allowed"
+	ReasonPrivateAddress   Reason = "private_address"
+	ReasonRedirectLoop     Reason = "redirect_loop"
+	ReasonHostDenied       Reason = "host_denied"
+	// ReasonThreatDetected is the url flagged by the reputation sources, e.g. scankit, rather than the policy itself
+	ReasonThreatDetected Reason = "threat_detected"
+)
+
+// Violation is the error of the url rejected by the policy
+type Violation struct {
+	Reason Reason
+	Detail string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("url rejected (%s): %s", v.Reason, v.Detail)
+}
+
+func violate(reason Reason, format string, args ...any) *Violation {
+	return &Violation{Reason: reason, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Rules are the host patterns of the policy, see Matcher for the syntax
+type Rules struct {
+	// DenyHosts are the hosts rejected
+	DenyHosts []string
+	// OwnHosts are the hosts serving the short urls, which are rejected to avoid the redirect loops
+	OwnHosts []string
+}
+
+type compiled struct {
+	deny *Matcher
+	own  *Matcher
+}
+
+// Policy allows the http(s) urls except the ones to the private addresses, the own hosts and the denied hosts.
+// The rules are compiled once and swapped atomically on SetRules, so Check never waits for reloading.
+type Policy struct {
+	rules atomic.Pointer[compiled]
+}
+
+// New generates the policy with the rules
+func New(rules Rules) (*Policy, error) {
+	p := &Policy{}
+	if err := p.SetRules(rules); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// SetRules replaces the rules, e.g. on reloading the config. The rules in use are kept if the new ones are invalid.
+func (p *Policy) SetRules(rules Rules) error {
+	deny, err := NewMatcher(rules.DenyHosts)
+	if err != nil {
+		return fmt.Errorf("deny hosts: %w", err)
+	}
+	own, err := NewMatcher(rules.OwnHosts)
+	if err != nil {
+		return fmt.Errorf("own hosts: %w", err)
+	}
+	p.rules.Store(&compiled{deny: deny, own: own})
+	return nil
+}
+
+// Check returns a *Violation if the url isn't allowed
+func (p *Policy) Check(rawURL string) error {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return violate(ReasonInvalidURL, "failed to parse the url")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return violate(ReasonSchemeNotAllowed, "scheme %q is not allowed", u.Scheme)
+	}
+	hostname := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if hostname == "" {
+		return violate(ReasonInvalidURL, "missing host")
+	}
+
+	var host string
+	if addr, ok := ParseIP(hostname); ok {
+		if isPrivate(addr) {
+			return violate(ReasonPrivateAddress, "address %s is not public", addr)
+		}
+		host = addr.String()
+	} else {
+		if host, err = NormalizeHost(hostname); err != nil {
+			return violate(ReasonInvalidURL, "invalid host")
+		}
+		if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+			return violate(ReasonPrivateAddress, "host %s is loopback", host)
+		}
+	}
+
+	rules := p.rules.Load()
+	if _, ok := rules.own.Match(host); ok {
+		return violate(ReasonRedirectLoop, "host %s serves the short urls", host)
+	}
+	if pattern, ok := rules.deny.Match(host); ok {
+		return violate(ReasonHostDenied, "host %s is denied by %s", host, pattern)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package policykit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PolicyTestSuite struct {
+	suite.Suite
+	impl *Policy
+}
+
+func TestPolicyTestSuite(t *testing.T) {
+	suite.Run(t, new(PolicyTestSuite))
+}
+
+func (s *PolicyTestSuite) SetupSubTest() {
+	var err error
+	s.impl, err = New(Rules{
+		DenyHosts: []string{"evil.com", "*.phish.net", `/^ads[0-9]*\.example\.org$/`, "bücher.example"},
+		OwnHosts:  []string{"sho.rt", "*.sho.rt"},
+	})
+	s.Require().NoError(err)
+}
+
+func (s *PolicyTestSuite) TestCheck() {
+	for _, t := range []struct {
+		name      string
+		url       string
+		expReason Reason
+	}{
+		{name: "allow the https url", url: "https://example.com/whatever?a=1"},
+		{name: "allow the http url with port", url: "http://example.com:8080/whatever"},
+		{name: "allow the public IP", url: "http://8.8.8.8/"},
+		{name: "allow the public IPv6", url: "http://[2001:4860:4860::8888]/"},
+		{name: "allow the parent domain of the wildcard", url: "https://phish.net/"},
+		{name: "reject the javascript url", url: "javascript:alert(1)", expReason: ReasonSchemeNotAllowed},
+		{name: "reject the data url", url: "data:text/html;base64,PHNjcmlwdD4=", expReason: ReasonSchemeNotAllowed},
+		{name: "reject the file url", url: "file:///etc/passwd", expReason: ReasonSchemeNotAllowed},
+		{name: "reject the url without host", url: "http:///whatever", expReason: ReasonInvalidURL},
+		{name: "reject the relative url", url: "/whatever", expReason: ReasonSchemeNotAllowed},
+		{name: "reject the loopback IP", url: "http://127.0.0.1/", expReason: ReasonPrivateAddress},
+		{name: "reject the private IP", url: "http://10.1.2.3:8080/", expReason: ReasonPrivateAddress},
+		{name: "reject the link local IP", url: "http://169.254.169.254/latest/meta-data", expReason: ReasonPrivateAddress},
+		{name: "reject the loopback IPv6", url: "http://[::1]/", expReason: ReasonPrivateAddress},
+		{name: "reject the IPv4-mapped IPv6", url: "http://[::ffff:192.168.0.1]/", expReason: ReasonPrivateAddress},
+		{name: "reject the decimal IP", url: "http://2130706433/", expReason: ReasonPrivateAddress},
+		{name: "reject the hex and octal IP", url: "http://0x7f.0.0.01/", expReason: ReasonPrivateAddress},
+		{name: "reject the localhost", url: "http://LocalHost./", expReason: ReasonPrivateAddress},
+		{name: "reject the own host", url: "https://sho.rt/abcd1234", expReason: ReasonRedirectLoop},
+		{name: "reject the subdomain of the own host", url: "https://www.SHO.rt./abcd1234", expReason: ReasonRedirectLoop},
+		{name: "reject the exact denied host", url: "https://EVIL.com/", expReason: ReasonHostDenied},
+		{name: "reject the userinfo pointing to the denied host", url: "https://example.com@evil.com/", expReason: ReasonHostDenied},
+		{name: "reject the wildcard denied host", url: "https://a.b.phish.net/", expReason: ReasonHostDenied},
+		{name: "reject the regex denied host", url: "https://ads12.example.org/", expReason: ReasonHostDenied},
+		{name: "reject the internationalized denied host", url: "https://xn--bcher-kva.example/", expReason: ReasonHostDenied},
+	} {
+		s.Suite.Run(t.name, func() {
+			err := s.impl.Check(t.url)
+			if t.expReason == "" {
+				s.NoError(err)
+				return
+			}
+
+			var violation *Violation
+			s.Require().ErrorAs(err, &violation)
+			s.Equal(t.expReason, violation.Reason)
+		})
+	}
+}
+
+func (s *PolicyTestSuite) TestSetRules() {
+	s.Suite.Run("replace the rules", func() {
+		s.NoError(s.impl.SetRules(Rules{DenyHosts: []string{"example.com"}}))
+		s.Error(s.impl.Check("https://example.com/"))
+		s.NoError(s.impl.Check("https://evil.com/"))
+	})
+
+	s.Suite.Run("keep the rules if the new ones are invalid", func() {
+		s.Error(s.impl.SetRules(Rules{DenyHosts: []string{"/[/"}}))
+		s.Error(s.impl.Check("https://evil.com/"))
+	})
+}
+
+func (s *PolicyTestSuite) TestReadPatterns() {
+	patterns, err := ReadPatterns(strings.NewReader("# the phishing hosts\nevil.com\n\n  *.phish.net  \n"))
+	s.NoError(err)
+	s.Equal([]string{"evil.com", "*.phish.net"}, patterns)
+}
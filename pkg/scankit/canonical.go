@@ -0,0 +1,205 @@
+// Package scankit looks up the urls in the local threat lists in the style of the Safe Browsing v4 local lists,
+// so that the destinations are scanned without reaching any remote service.
+package scankit
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	"github.com/Hao1995/short-url/pkg/policykit"
+)
+
+// ErrInvalidURL is the error of the url which has no host
+var ErrInvalidURL = errors.New("invalid url")
+
+const (
+	maxHostSuffixes = 4
+	maxPathPrefixes = 4
+)
+
+// Canonicalize canonicalizes the url as the Safe Browsing API does, the returned query keeps its leading `?`.
+// See https://developers.google.com/safe-browsing/v4/urls-hashing#canonicalization
+func Canonicalize(rawURL string) (host, path, query string, err error) {
+	s := strings.TrimSpace(strings.NewReplacer("\t", "", "\r", "", "\n", "").Replace(rawURL))
+	if i := strings.IndexByte(s, '#'); i >= 0 {
+		s = s[:i]
+	}
+	s = unescape(s)
+
+	if i := strings.Index(s, "://"); i >= 0 {
+		s = s[i+3:]
+	}
+	authority := s
+	rest := ""
+	if i := strings.IndexAny(s, "/?"); i >= 0 {
+		authority, rest = s[:i], s[i:]
+	}
+	if i := strings.LastIndexByte(authority, '@'); i >= 0 {
+		authority = authority[i+1:]
+	}
+
+	host = canonicalHost(authority)
+	if host == "" {
+		return "", "", "", ErrInvalidURL
+	}
+	path = rest
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		path, query = rest[:i], rest[i:]
+	}
+	return escape(host), escape(canonicalPath(path)), escape(query), nil
+}
+
+// Expressions generates the host suffix / path prefix expressions of the url to be looked up,
+// e.g. `a.b.c/1/2.html?param=1`, `a.b.c/1/2.html`, `a.b.c/`, `a.b.c/1/`, `b.c/1/2.html?param=1` and so on.
+// See https://developers.google.com/safe-browsing/v4/urls-hashing#suffixprefix-expressions
+func Expressions(rawURL string) ([]string, error) {
+	host, path, query, err := Canonicalize(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := []string{host}
+	if _, ok := policykit.ParseIP(host); !ok {
+		// the last 5 components at most, and the top-level domain alone is skipped
+		components := strings.Split(host, ".")
+		for i := max(1, len(components)-5); i < len(components)-1 && len(hosts) <= maxHostSuffixes; i++ {
+			hosts = append(hosts, strings.Join(components[i:], "."))
+		}
+	}
+
+	paths := []string{}
+	if query != "" {
+		paths = append(paths, path+query)
+	}
+	paths = append(paths, path)
+	prefix := "/"
+	components := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i < maxPathPrefixes; i++ {
+		if prefix != path {
+			paths = append(paths, prefix)
+		}
+		if i >= len(components)-1 {
+			break
+		}
+		prefix += components[i] + "/"
+	}
+
+	exprs := make([]string, 0, len(hosts)*len(paths))
+	for _, h := range hosts {
+		for _, p := range paths {
+			exprs = append(exprs, h+p)
+		}
+	}
+	return exprs, nil
+}
+
+// Hash is the full hash of the expression, the entries of the lists are its prefixes
+func Hash(expression string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(expression))
+}
+
+func canonicalHost(authority string) string {
+	host := authority
+	if strings.HasPrefix(host, "[") {
+		if i := strings.IndexByte(host, ']'); i >= 0 {
+			host = host[:i+1]
+		}
+	} else if i := strings.LastIndexByte(host, ':'); i >= 0 {
+		host = host[:i]
+	}
+
+	host = strings.ToLower(strings.Trim(host, "."))
+	for strings.Contains(host, "..") {
+		host = strings.ReplaceAll(host, "..", ".")
+	}
+	if addr, ok := policykit.ParseIP(host); ok && addr.Is4() {
+		return addr.String()
+	}
+	if normalized, err := policykit.NormalizeHost(host); err == nil {
+		return normalized
+	}
+	return host
+}
+
+// canonicalPath resolves `/./` and `/../`, and merges the consecutive slashes
+func canonicalPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	segments := []string{}
+	parts := strings.Split(path, "/")
+	for i, part := range parts {
+		last := i == len(parts)-1
+		switch part {
+		case "", ".":
+			if last {
+				segments = append(segments, "")
+			}
+		case "..":
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+			if last {
+				segments = append(segments, "")
+			}
+		default:
+			segments = append(segments, part)
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// unescape percent-unescapes the url repeatedly until it has no more escapes, the invalid ones are kept as they are
+func unescape(s string) string {
+	for {
+		var b strings.Builder
+		changed := false
+		for i := 0; i < len(s); i++ {
+			if s[i] == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+				b.WriteByte(unhex(s[i+1])<<4 | unhex(s[i+2]))
+				i += 2
+				changed = true
+				continue
+			}
+			b.WriteByte(s[i])
+		}
+		if !changed {
+			return s
+		}
+		s = b.String()
+	}
+}
+
+// escape percent-escapes the control characters, the non-ASCII bytes, `#` and `%`
+func escape(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= 0x20 || c >= 0x7f || c == '#' || c == '%' {
+			b.WriteByte('%')
+			b.WriteByte(hex[c>>4])
+			b.WriteByte(hex[c&0xf])
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
@@ -0,0 +1,169 @@
+package scankit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Hao1995/short-url/pkg/logkit"
+)
+
+const (
+	minPrefixLen = 4
+	maxPrefixLen = 32
+)
+
+type entry struct {
+	prefix []byte
+	threat string
+}
+
+// lists indexes the entries by the first 4 bytes, which is the shortest prefix allowed
+type lists struct {
+	entries map[[minPrefixLen]byte][]entry
+	size    int
+}
+
+// LocalDB looks up the urls in the threat lists loaded from a local file, one entry per line:
+//
+//	# comments and blank lines are skipped
+//	MALWARE            1a2b3c4d
+//	SOCIAL_ENGINEERING 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//
+// Each entry is the threat type and the hex SHA-256 hash prefix of 4 to 32 bytes of an expression, e.g. `evil.example.com/`.
+// Unlike Safe Browsing, there is no server to confirm the full hashes, so a url matching a prefix is regarded as
+// flagged. The full hashes are preferred to avoid the false positives of the short prefixes.
+type LocalDB struct {
+	path  string
+	lists atomic.Pointer[lists]
+
+	mu      sync.Mutex
+	modTime time.Time
+}
+
+// Open loads the threat lists of the file
+func Open(path string) (*LocalDB, error) {
+	db := &LocalDB{path: path}
+	if _, err := db.Reload(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Reload loads the file again if its modification time changes. The lists in use are kept if the file is invalid.
+func (db *LocalDB) Reload() (bool, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	info, err := os.Stat(db.path)
+	if err != nil {
+		return false, err
+	}
+	if db.lists.Load() != nil && info.ModTime().Equal(db.modTime) {
+		return false, nil
+	}
+
+	f, err := os.Open(db.path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	l, err := readLists(f)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", db.path, err)
+	}
+	db.lists.Store(l)
+	db.modTime = info.ModTime()
+	return true, nil
+}
+
+// Run reloads the file every interval until the context is done
+func (db *LocalDB) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if ok, err := db.Reload(); err != nil {
+				slog.WarnContext(ctx, "scankit. Failed to reload the threat lists, keep the current ones", "path", db.path, logkit.Err(err))
+			} else if ok {
+				slog.InfoContext(ctx, "scankit. Reload the threat lists", "path", db.path, "size", db.Len())
+			}
+		}
+	}
+}
+
+// Len is the number of entries
+func (db *LocalDB) Len() int {
+	return db.lists.Load().size
+}
+
+// Scan returns the threat type of the url, or empty if the url isn't flagged
+func (db *LocalDB) Scan(ctx context.Context, rawURL string) (string, error) {
+	exprs, err := Expressions(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	l := db.lists.Load()
+	for _, expr := range exprs {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		hash := Hash(expr)
+		for _, e := range l.entries[[minPrefixLen]byte(hash[:minPrefixLen])] {
+			if bytes.HasPrefix(hash[:], e.prefix) {
+				return e.threat, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+func readLists(r io.Reader) (*lists, error) {
+	l := &lists{entries: map[[minPrefixLen]byte][]entry{}}
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want `<threat type> <hash prefix>`", n)
+		}
+		prefix, err := hex.DecodeString(fields[1])
+		if err != nil || len(prefix) < minPrefixLen || len(prefix) > maxPrefixLen {
+			return nil, fmt.Errorf("line %d: want a hex hash prefix of %d to %d bytes", n, minPrefixLen, maxPrefixLen)
+		}
+
+		key := [minPrefixLen]byte(prefix[:minPrefixLen])
+		l.entries[key] = append(l.entries[key], entry{prefix: prefix, threat: fields[0]})
+		l.size++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// the full hashes are matched before the short prefixes, so the more specific threat type is reported
+	for _, entries := range l.entries {
+		slices.SortStableFunc(entries, func(a, b entry) int {
+			return len(b.prefix) - len(a.prefix)
+		})
+	}
+	return l, nil
+}
@@ -0,0 +1,40 @@
+// Package shardkit maps the ids to the shards, either by hashing the id or by the shard number embedded in the id.
+package shardkit
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strconv"
+)
+
+// MaxShards is the number of shards that can be embedded in the two hex characters prefix
+const MaxShards = 256
+
+var ErrInvalidID = errors.New("invalid id")
+
+// Hash returns the shard of the id by its crc32 checksum
+func Hash(id string, n int) int {
+	return int(crc32.ChecksumIEEE([]byte(id)) % uint32(n))
+}
+
+// Prefix returns the shard embedded in the first two hex characters of the id
+func Prefix(id string) (int, error) {
+	if len(id) < 2 {
+		return 0, ErrInvalidID
+	}
+
+	shard, err := strconv.ParseUint(id[:2], 16, 8)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidID, err)
+	}
+	return int(shard), nil
+}
+
+// Embed replaces the first two characters of the id with the shard in hex
+func Embed(id string, shard int) string {
+	if len(id) < 2 {
+		return id
+	}
+	return fmt.Sprintf("%02x", shard%MaxShards) + id[2:]
+}